@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const localClient = "local"
+
+// auditLogger logs every API request to the process-compose log
+func auditLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		log.Debug().
+			Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Str("remote_addr", c.Request.RemoteAddr).
+			Int("status", c.Writer.Status()).
+			Dur("duration", time.Since(start)).
+			Msg("API request")
+	}
+}
+
+// auditClient returns "local" for the requests received on the unix socket or the loopback interface,
+// and the client IP otherwise
+func auditClient(c *gin.Context) string {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil || ip.IsLoopback() {
+		return localClient
+	}
+	return ip.String()
+}
+
+// audit records the state changing command together with its client
+func (api *PcApi) audit(c *gin.Context, command string, names ...string) {
+	api.project.Audit(auditClient(c), command, names...)
+}
+
+// projectProcessNames returns the sorted names of the updated processes
+func projectProcessNames(project *types.Project) []string {
+	names := make([]string, 0, len(project.Processes))
+	for name := range project.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
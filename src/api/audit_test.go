@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+type auditEntry struct {
+	client  string
+	command string
+	names   []string
+}
+
+// auditProject records the audited commands of the handlers under test
+type auditProject struct {
+	app.IProject
+	entries []auditEntry
+}
+
+func (p *auditProject) Audit(client, command string, names ...string) {
+	p.entries = append(p.entries, auditEntry{client: client, command: command, names: names})
+}
+
+func (p *auditProject) GetProcessesState() (*types.ProcessesState, error) {
+	return &types.ProcessesState{}, nil
+}
+
+func (p *auditProject) StartProcess(string) error {
+	return nil
+}
+
+func (p *auditProject) RestartProcess(string) error {
+	return nil
+}
+
+func (p *auditProject) StopProcess(string, time.Duration) error {
+	return nil
+}
+
+func (p *auditProject) StopProcesses(names []string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (p *auditProject) ScaleProcess(string, int) error {
+	return nil
+}
+
+func (p *auditProject) UpdateProject(*types.Project) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func TestAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		remoteAddr string
+		want       []auditEntry
+	}{
+		{
+			name:       "restart from loopback",
+			method:     http.MethodPost,
+			path:       "/process/restart/db",
+			remoteAddr: "127.0.0.1:41234",
+			want:       []auditEntry{{client: localClient, command: "Restart", names: []string{"db"}}},
+		},
+		{
+			name:       "start from remote",
+			method:     http.MethodPost,
+			path:       "/process/start/db",
+			remoteAddr: "192.168.1.17:41234",
+			want:       []auditEntry{{client: "192.168.1.17", command: "Start", names: []string{"db"}}},
+		},
+		{
+			name:       "stop processes",
+			method:     http.MethodPatch,
+			path:       "/processes/stop",
+			body:       `["db","web"]`,
+			remoteAddr: "[::1]:41234",
+			want:       []auditEntry{{client: localClient, command: "Stop", names: []string{"db", "web"}}},
+		},
+		{
+			name:       "scale",
+			method:     http.MethodPatch,
+			path:       "/process/scale/web/3",
+			remoteAddr: "10.0.0.2:41234",
+			want:       []auditEntry{{client: "10.0.0.2", command: "Scale", names: []string{"web"}}},
+		},
+		{
+			name:       "update project",
+			method:     http.MethodPost,
+			path:       "/project",
+			body:       `{"processes":{"web":{"command":"true"},"db":{"command":"true"}}}`,
+			remoteAddr: "127.0.0.1:41234",
+			want:       []auditEntry{{client: localClient, command: "Update", names: []string{"db", "web"}}},
+		},
+		{
+			name:       "invalid scale isn't audited",
+			method:     http.MethodPatch,
+			path:       "/process/scale/web/many",
+			remoteAddr: "127.0.0.1:41234",
+		},
+		{
+			name:       "state query isn't audited",
+			method:     http.MethodGet,
+			path:       "/processes",
+			remoteAddr: "192.168.1.17:41234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &auditProject{}
+			router := InitRoutes(false, NewPcApi(project))
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.RemoteAddr = tt.remoteAddr
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code >= http.StatusInternalServerError {
+				t.Fatalf("%s %s returned %d: %s", tt.method, tt.path, rec.Code, rec.Body.String())
+			}
+			if !reflect.DeepEqual(project.entries, tt.want) {
+				t.Errorf("audit entries = %+v, want %+v", project.entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&buf).Level(zerolog.DebugLevel)
+	defer func() { log.Logger = logger }()
+
+	router := InitRoutes(false, NewPcApi(&auditProject{}))
+	req := httptest.NewRequest(http.MethodGet, "/processes", nil)
+	req.RemoteAddr = "192.168.1.17:41234"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse the request log %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":       "debug",
+		"method":      http.MethodGet,
+		"path":        "/processes",
+		"remote_addr": "192.168.1.17:41234",
+		"status":      float64(http.StatusOK),
+		"message":     "API request",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("request log %s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Errorf("request log has no duration: %v", entry)
+	}
+}
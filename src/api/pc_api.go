@@ -3,7 +3,9 @@ package api
 import (
 	"github.com/f1bonacc1/process-compose/src/types"
 	"net/http"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/gin-gonic/gin"
@@ -107,17 +109,77 @@ func (api *PcApi) GetProcessLogs(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"logs": logs})
 }
 
+// @Schemes
+// @Description Searches the process log file, or the in-memory log if the process has no log file
+// @Tags Process
+// @Summary Search process logs
+// @Produce  json
+// @Param name path string true "Process Name"
+// @Param q query string false "Regular expression to match"
+// @Param since query string false "Match lines logged at or after this RFC3339 timestamp"
+// @Param until query string false "Match lines logged at or before this RFC3339 timestamp"
+// @Param limit query int false "Max number of the most recent matching lines (0 for no limit)"
+// @Success 200 {array} string "Matching Log Lines"
+// @Router /processes/{name}/logs [get]
+func (api *PcApi) SearchProcessLogs(c *gin.Context) {
+	name := c.Param("name")
+	query := &types.LogSearchQuery{
+		Pattern: c.Query("q"),
+	}
+	if _, err := regexp.Compile(query.Pattern); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var err error
+	if since := c.Query("since"); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if until := c.Query("until"); until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	logs, err := api.project.SearchProcessLog(name, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
 // @Schemes
 // @Description Sends kill signal to the process
 // @Tags Process
 // @Summary Stop a process
 // @Produce  json
 // @Param name path string true "Process Name"
+// @Param timeout query string false "Max time to wait for the process to exit, e.g. 10s (no limit if omitted)"
 // @Success 200 {string} string "Stopped Process Name"
 // @Router /process/stop/{name} [patch]
 func (api *PcApi) StopProcess(c *gin.Context) {
 	name := c.Param("name")
-	err := api.project.StopProcess(name)
+	var timeout time.Duration
+	if t := c.Query("timeout"); t != "" {
+		var err error
+		if timeout, err = time.ParseDuration(t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	api.audit(c, "Stop", name)
+	err := api.project.StopProcess(name, timeout)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -140,6 +202,7 @@ func (api *PcApi) StopProcesses(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	api.audit(c, "Stop", names...)
 	stopped, err := api.project.StopProcesses(names)
 	if err != nil {
 		if len(stopped) == 0 {
@@ -163,6 +226,7 @@ func (api *PcApi) StopProcesses(c *gin.Context) {
 // @Router /process/start/{name} [post]
 func (api *PcApi) StartProcess(c *gin.Context) {
 	name := c.Param("name")
+	api.audit(c, "Start", name)
 	err := api.project.StartProcess(name)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -182,6 +246,7 @@ func (api *PcApi) StartProcess(c *gin.Context) {
 // @Router /process/restart/{name} [post]
 func (api *PcApi) RestartProcess(c *gin.Context) {
 	name := c.Param("name")
+	api.audit(c, "Restart", name)
 	err := api.project.RestartProcess(name)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -191,6 +256,45 @@ func (api *PcApi) RestartProcess(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"name": name})
 }
 
+// @Schemes
+// @Description Sends the reload signal (SIGHUP by default) to the process, instead of restarting it
+// @Tags Process
+// @Summary Reload a process
+// @Produce  json
+// @Param name path string true "Process Name"
+// @Success 200 {string} string "Reloaded Process Name"
+// @Router /processes/{name}/reload [post]
+func (api *PcApi) ReloadProcess(c *gin.Context) {
+	name := c.Param("name")
+	api.audit(c, "Reload", name)
+	err := api.project.ReloadProcess(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"name": name})
+}
+
+// @Schemes
+// @Description Explains why the process is in its current state, e.g. which dependency it is waiting for
+// @Tags Process
+// @Summary Explain a process state
+// @Produce  json
+// @Param name path string true "Process Name"
+// @Success 200 {object} map[string]string "Process Name and State Explanation"
+// @Router /processes/{name}/explain [get]
+func (api *PcApi) ExplainProcess(c *gin.Context) {
+	name := c.Param("name")
+	explanation, err := api.project.ExplainProcess(name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"name": name, "explanation": explanation})
+}
+
 // @Schemes
 // @Description Scale a process
 // @Tags Process
@@ -207,6 +311,7 @@ func (api *PcApi) ScaleProcess(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	api.audit(c, "Scale", name)
 	err = api.project.ScaleProcess(name, scale)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -227,6 +332,27 @@ func (api *PcApi) IsAlive(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "alive"})
 }
 
+// @Schemes
+// @Description Check that none of the critical processes failed or won't run
+// @Tags Liveness
+// @Summary Health Check
+// @Produce  json
+// @Success 200 {object} map[string]string "Healthy Status"
+// @Failure 503 {object} map[string]interface{} "Unhealthy Status and the Failed Critical Processes"
+// @Router /health [get]
+func (api *PcApi) GetHealth(c *gin.Context) {
+	unhealthy, err := app.GetUnhealthyCriticalProcesses(api.project)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(unhealthy) > 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unhealthy", "failed": unhealthy})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+}
+
 // @Schemes
 // @Description Get process compose hostname
 // @Tags Hostname
@@ -269,10 +395,22 @@ func (api *PcApi) GetProcessPorts(c *gin.Context) {
 // @Tags Project
 // @Summary Stops all the processes and the server
 // @Produce  json
+// @Param timeout query string false "Grace period before killing the processes that are still running, e.g. 60s (the processes shutdown timeouts if omitted)"
 // @Success 200
 // @Router /project/stop [post]
 func (api *PcApi) ShutDownProject(c *gin.Context) {
-	api.project.ShutDownProject()
+	if t := c.Query("timeout"); t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		api.audit(c, "Shutdown")
+		_ = api.project.ShutDownProjectWithTimeout(timeout)
+	} else {
+		api.audit(c, "Shutdown")
+		api.project.ShutDownProject()
+	}
 	c.JSON(http.StatusOK, gin.H{"status": "stopped"})
 }
 
@@ -289,6 +427,7 @@ func (api *PcApi) UpdateProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	api.audit(c, "Update", projectProcessNames(&project)...)
 	status, err := api.project.UpdateProject(&project)
 	if err != nil {
 		if len(status) == 0 {
@@ -301,6 +440,31 @@ func (api *PcApi) UpdateProject(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// @Schemes
+// @Description Updates the process description, owner, runbook URL, labels and max output lines without restarting it. Other changes are rejected
+// @Tags Process
+// @Summary Update process config
+// @Accept json
+// @Produce  json
+// @Param name path string true "Process Name"
+// @Param config body object true "Process Config"
+// @Success 200 {object} object "Updated Process Name"
+// @Router /processes/{name} [patch]
+func (api *PcApi) UpdateProcessConfig(c *gin.Context) {
+	name := c.Param("name")
+	var procConf types.ProcessConfig
+	if err := c.ShouldBindJSON(&procConf); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	api.audit(c, "Update", name)
+	if err := api.project.UpdateProcessConfig(name, &procConf); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"name": name})
+}
+
 // @Schemes
 // @Description Retrieves project state information
 // @Tags Project
@@ -27,10 +27,13 @@ import (
 // InitRoutes initialize routing information
 func InitRoutes(useLogger bool, handler *PcApi) *gin.Engine {
 	r := gin.New()
+	// namespaced process names contain an escaped '/' (%2F)
+	r.UseRawPath = true
 	if useLogger {
 		r.Use(gin.Logger())
 	}
 	r.Use(gin.Recovery())
+	r.Use(auditLogger())
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.GET("/", func(c *gin.Context) {
@@ -39,16 +42,21 @@ func InitRoutes(useLogger bool, handler *PcApi) *gin.Engine {
 	})
 
 	r.GET("/live", handler.IsAlive)
+	r.GET("/health", handler.GetHealth)
 	r.GET("/hostname", handler.GetHostName)
 	r.GET("/processes", handler.GetProcesses)
 	r.GET("/process/:name", handler.GetProcess)
 	r.GET("/process/info/:name", handler.GetProcessInfo)
 	r.GET("/process/ports/:name", handler.GetProcessPorts)
 	r.GET("/process/logs/:name/:endOffset/:limit", handler.GetProcessLogs)
+	r.GET("/processes/:name/logs", handler.SearchProcessLogs)
+	r.GET("/processes/:name/explain", handler.ExplainProcess)
 	r.PATCH("/process/stop/:name", handler.StopProcess)
 	r.PATCH("/processes/stop", handler.StopProcesses)
+	r.PATCH("/processes/:name", handler.UpdateProcessConfig)
 	r.POST("/process/start/:name", handler.StartProcess)
 	r.POST("/process/restart/:name", handler.RestartProcess)
+	r.POST("/processes/:name/reload", handler.ReloadProcess)
 	r.POST("/project/stop", handler.ShutDownProject)
 	r.POST("/project", handler.UpdateProject)
 	r.GET("/project/state", handler.GetProjectState)
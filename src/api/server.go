@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/config"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"net"
@@ -10,7 +11,7 @@ import (
 	"os"
 )
 
-const EnvDebugMode = "PC_DEBUG_MODE"
+const EnvDebugMode = config.EnvVarDebugMode
 
 func StartHttpServerWithUnixSocket(useLogger bool, unixSocket string, project app.IProject) (*http.Server, error) {
 	router := getRouter(useLogger, project)
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditClientTUI is the client of the commands sent from the TUI
+const AuditClientTUI = "tui"
+
+// auditLogProcess is the process name of the audit lines in the project log
+const auditLogProcess = "process-compose"
+
+// Audit records a state changing command, and the client that sent it, in the process-compose log
+// and in the project log
+func (p *ProjectRunner) Audit(client, command string, names ...string) {
+	log.Info().
+		Str("client", client).
+		Strs("processes", names).
+		Msgf("%s requested", command)
+	p.auditMtx.Lock()
+	defer p.auditMtx.Unlock()
+	if p.auditLog == nil {
+		return
+	}
+	message := fmt.Sprintf("%s requested by %s", command, client)
+	if len(names) > 0 {
+		message += ": " + strings.Join(names, ", ")
+	}
+	p.auditLog.Info(message, pclog.LogMetadata{Process: auditLogProcess})
+}
+
+// setAuditLog sets the project log, or nil once it's closed
+func (p *ProjectRunner) setAuditLog(logger pclog.PcLogger) {
+	p.auditMtx.Lock()
+	defer p.auditMtx.Unlock()
+	p.auditLog = logger
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/pclog"
+)
+
+func TestProjectRunner_Audit(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "project.log")
+	logger := pclog.NewAggregateLogger("run")
+	logger.Open(logPath, nil)
+
+	p := &ProjectRunner{}
+	p.Audit(AuditClientTUI, "Stop", "db")
+	p.setAuditLog(logger)
+	p.Audit(AuditClientTUI, "Restart", "db", "web")
+	p.Audit("192.168.1.17", "Shutdown")
+	p.setAuditLog(nil)
+	p.Audit(AuditClientTUI, "Start", "db")
+	logger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		"Restart requested by tui: db, web",
+		"Shutdown requested by 192.168.1.17",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d audit lines, want %d:\n%s", len(lines), len(want), data)
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, want[i]) || !strings.Contains(line, auditLogProcess) {
+			t.Errorf("audit line %d = %q, want %q from %s", i, line, want[i], auditLogProcess)
+		}
+	}
+}
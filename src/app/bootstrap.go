@@ -0,0 +1,77 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// runBootstrapCommand adds the KEY=VALUE lines printed by the project bootstrap_command to the project environment
+func (p *ProjectRunner) runBootstrapCommand(ctx context.Context) error {
+	if p.project.BootstrapCommand == "" {
+		return nil
+	}
+	cmd := command.BuildCommandShellArgContext(ctx, *p.project.ShellConfig, p.project.BootstrapCommand)
+	cmd.SetEnv(append(os.Environ(), p.project.Environment...))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run the bootstrap command: %w", err)
+	}
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run the bootstrap command: %w", err)
+	}
+	env := parseBootstrapOutput(bufio.NewScanner(stdout))
+	if err = cmd.Wait(); err != nil {
+		return fmt.Errorf("bootstrap command failed with exit code %d: %w", cmd.ExitCode(), err)
+	}
+	log.Info().Msgf("Bootstrap command added %d environment variables", len(env))
+	p.project.Environment = append(p.project.Environment, env...)
+	// the command line variables override the bootstrap ones
+	p.project.Environment = append(p.project.Environment, p.project.CliEnvVars...)
+	return nil
+}
+
+// checkEnvSchemas checks the env_schema variables the loader skipped, as they might have been added by the
+// bootstrap_command, and applies their defaults to the processes in runOrder
+func (p *ProjectRunner) checkEnvSchemas(runOrder []types.ProcessConfig) error {
+	if p.project.BootstrapCommand == "" {
+		return nil
+	}
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	for i := range runOrder {
+		proc := &runOrder[i]
+		if len(proc.EnvSchema) == 0 {
+			continue
+		}
+		if err := proc.CheckEnvSchema(p.project.Environment, false); err != nil {
+			return err
+		}
+		p.project.Processes[proc.ReplicaName] = *proc
+	}
+	return nil
+}
+
+// parseBootstrapOutput parses the KEY=VALUE lines. Empty lines, comments and an 'export' prefix are allowed
+func parseBootstrapOutput(scanner *bufio.Scanner) []string {
+	env := []string{}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		if key, _, found := strings.Cut(line, "="); !found || strings.TrimSpace(key) == "" {
+			log.Warn().Msgf("ignoring '%s' from the bootstrap command output: not a KEY=VALUE pair", line)
+			continue
+		}
+		env = append(env, line)
+	}
+	return env
+}
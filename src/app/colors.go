@@ -0,0 +1,53 @@
+package app
+
+import (
+	"hash/fnv"
+	"os"
+
+	"github.com/f1bonacc1/process-compose/src/pclog"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/term"
+)
+
+// processColorPalette is used for the processes without a color
+var processColorPalette = []color.Attribute{
+	color.FgHiGreen,
+	color.FgHiYellow,
+	color.FgHiBlue,
+	color.FgHiMagenta,
+	color.FgHiCyan,
+	color.FgGreen,
+	color.FgYellow,
+	color.FgBlue,
+	color.FgMagenta,
+	color.FgCyan,
+}
+
+// paletteColor picks a color from the palette, the same one for the same process name on every run
+func paletteColor(name string) color.Attribute {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return processColorPalette[h.Sum32()%uint32(len(processColorPalette))]
+}
+
+// processColorAttrs returns the configured process color, or a palette color if unset
+func processColorAttrs(name, value string) []color.Attribute {
+	if value != "" {
+		attrs, err := pclog.ParseColor(value)
+		if err == nil {
+			return attrs
+		}
+		log.Warn().Err(err).Msgf("Using the default color for process %s", name)
+	}
+	return []color.Attribute{paletteColor(name)}
+}
+
+// newColor returns a color that is disabled when stdout is not a terminal
+func newColor(attrs ...color.Attribute) *color.Color {
+	c := color.New(attrs...)
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		c.DisableColor()
+	}
+	return c
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const corePatternFile = "/proc/sys/kernel/core_pattern"
+
+// coreFileCandidates lists the locations in which the kernel writes the core file with the default core pattern
+func coreFileCandidates(workDir string, pid int) []string {
+	return []string{
+		filepath.Join(workDir, fmt.Sprintf("core.%d", pid)),
+		filepath.Join(workDir, "core"),
+		// macOS
+		fmt.Sprintf("/cores/core.%d", pid),
+	}
+}
+
+// collectCoreDump moves the core file of a crashed process to dumpDir/{name}-{pid}-{timestamp}.core.
+// It returns an empty path if no core file was found
+func collectCoreDump(name string, pid int, workDir, dumpDir string, now time.Time) (string, error) {
+	for _, candidate := range coreFileCandidates(workDir, pid) {
+		info, err := os.Stat(candidate)
+		if err != nil || !info.Mode().IsRegular() || info.ModTime().Before(now.Add(-time.Minute)) {
+			continue
+		}
+		if err = os.MkdirAll(dumpDir, 0755); err != nil {
+			return candidate, fmt.Errorf("failed to create the core dump directory %s: %w", dumpDir, err)
+		}
+		fileName := fmt.Sprintf("%s-%d-%d.core", strings.ReplaceAll(name, "/", "_"), pid, now.Unix())
+		target := filepath.Join(dumpDir, fileName)
+		if err = os.Rename(candidate, target); err != nil {
+			return candidate, fmt.Errorf("failed to move %s to %s: %w", candidate, target, err)
+		}
+		return target, nil
+	}
+	return "", nil
+}
+
+// reportCoreDump logs the core file location of a process that was killed by a signal
+func (p *Process) reportCoreDump(pid int) {
+	workDir := p.procConf.WorkingDir
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	dumpDir := p.procConf.CoreDumpDir
+	if dumpDir == "" {
+		dumpDir = workDir
+	} else if !filepath.IsAbs(dumpDir) {
+		dumpDir = filepath.Join(workDir, dumpDir)
+	}
+	path, err := collectCoreDump(p.getName(), pid, workDir, dumpDir, time.Now())
+	switch {
+	case err != nil:
+		log.Warn().Err(err).Str("process", p.getName()).Str("core_file", path).Msg("Process was killed by a signal")
+	case path != "":
+		log.Warn().Str("process", p.getName()).Str("core_file", path).Msg("Process was killed by a signal")
+	default:
+		pattern, _ := os.ReadFile(corePatternFile)
+		log.Warn().Str("process", p.getName()).Str("core_pattern", strings.TrimSpace(string(pattern))).
+			Msg("Process was killed by a signal, no core file found in its working directory")
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+const minDeadlockCheckInterval = 10 * time.Millisecond
+
+func (p *ProjectRunner) onStateChange() {
+	p.lastStateChange.Store(time.Now().UnixNano())
+}
+
+// watchDeadlock shuts down the project if processes are pending while no process changed its state for deadlock_timeout
+func (p *ProjectRunner) watchDeadlock(ctx context.Context) {
+	timeout := p.project.DeadlockTimeout
+	interval := max(timeout/10, minDeadlockCheckInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ctxApp.Done():
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, p.lastStateChange.Load())) < timeout {
+				continue
+			}
+			pending := p.getPendingProcesses()
+			if len(pending) == 0 {
+				continue
+			}
+			log.Error().Msgf("No process changed its state for %v, while %d processes are pending. Aborting",
+				timeout, len(pending))
+			for _, name := range pending {
+				log.Error().Msgf("%s is waiting for: %s", name, p.describeDependencyChain(name, map[string]bool{name: true}))
+			}
+			p.setExitCode(1)
+			_ = p.ShutDownProject()
+			return
+		}
+	}
+}
+
+func (p *ProjectRunner) getPendingProcesses() []string {
+	p.runProcMutex.Lock()
+	procs := make([]*Process, 0, len(p.runningProcesses))
+	for _, proc := range p.runningProcesses {
+		procs = append(procs, proc)
+	}
+	p.runProcMutex.Unlock()
+	pending := []string{}
+	for _, proc := range procs {
+		if proc.getStatusName() == types.ProcessStatePending {
+			pending = append(pending, proc.getName())
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// describeDependencyChain describes the dependencies of a process and their state, recursively
+func (p *ProjectRunner) describeDependencyChain(name string, visited map[string]bool) string {
+	proc := p.getRunningProcess(name)
+	if proc == nil || len(proc.procConf.DependsOn) == 0 {
+		return "nothing"
+	}
+	deps := make([]string, 0, len(proc.procConf.DependsOn))
+	for dep := range proc.procConf.DependsOn {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	descs := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		status := "not running"
+		if depProc := p.getRunningProcess(dep); depProc != nil {
+			status = depProc.getStatusName()
+		}
+		desc := fmt.Sprintf("%s (%s, %s)", dep, proc.procConf.DependsOn[dep].Condition, status)
+		if depProc := p.getRunningProcess(dep); depProc != nil && len(depProc.procConf.DependsOn) > 0 && !visited[dep] {
+			visited[dep] = true
+			desc += " -> [" + p.describeDependencyChain(dep, visited) + "]"
+		}
+		descs = append(descs, desc)
+	}
+	return strings.Join(descs, ", ")
+}
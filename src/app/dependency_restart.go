@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// dependencyRestart is the restart of a failed dependency, shared by all its dependents
+type dependencyRestart struct {
+	done chan struct{}
+	proc *Process
+	err  error
+}
+
+// restartDependency restarts the failed run of a dependency after the delay and returns its new run.
+// The dependents of the same run share a single restart
+func (p *ProjectRunner) restartDependency(ended *Process, delay time.Duration) (*Process, error) {
+	p.restartsMtx.Lock()
+	restart, ok := p.dependencyRestarts[ended]
+	if !ok {
+		restart = &dependencyRestart{done: make(chan struct{})}
+		p.dependencyRestarts[ended] = restart
+		go func() {
+			defer close(restart.done)
+			restart.proc, restart.err = p.doRestartDependency(ended, delay)
+		}()
+	}
+	p.restartsMtx.Unlock()
+	<-restart.done
+	return restart.proc, restart.err
+}
+
+func (p *ProjectRunner) doRestartDependency(ended *Process, delay time.Duration) (*Process, error) {
+	name := ended.getName()
+	p.supersedeExitCode(ended)
+	log.Info().Msgf("Restarting %s in %v", name, delay)
+	select {
+	case <-p.ctxWait.Done():
+		return nil, p.ctxWait.Err()
+	case <-time.After(delay):
+	}
+	select {
+	case <-p.ctxWait.Done():
+		return nil, p.ctxWait.Err()
+	case <-ended.removed:
+	}
+	if err := p.ctxWait.Err(); err != nil {
+		return nil, err
+	}
+	if err := p.StartProcess(name); err != nil {
+		return nil, err
+	}
+	restarted := p.getRunningProcess(name)
+	if restarted == nil {
+		return nil, fmt.Errorf("process %s isn't running", name)
+	}
+	return restarted, nil
+}
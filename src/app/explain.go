@@ -0,0 +1,128 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// ExplainProcess describes why the process is in its current state
+func (p *ProjectRunner) ExplainProcess(name string) (string, error) {
+	name = p.project.ResolveProcessName(name)
+	state, err := p.GetProcessState(name)
+	if err != nil {
+		return "", err
+	}
+	procConf, ok := p.project.Processes[name]
+	if !ok {
+		return "", fmt.Errorf("no such process: %s", name)
+	}
+	switch state.Status {
+	case types.ProcessStatePending:
+		return p.explainPending(&procConf), nil
+	case types.ProcessStateSkipped:
+		if reason := p.getEndReason(name); reason != "" {
+			return fmt.Sprintf("Process %s is Skipped because %s", name, reason), nil
+		}
+		return fmt.Sprintf("Process %s is Skipped, it was stopped before it started", name), nil
+	case types.ProcessStateError:
+		if reason := p.getEndReason(name); reason != "" {
+			return fmt.Sprintf("Process %s is in Error state because it failed to start: %s", name, reason), nil
+		}
+		return fmt.Sprintf("Process %s is in Error state because it failed to start", name), nil
+	case types.ProcessStateDisabled:
+		return fmt.Sprintf("Process %s is Disabled, it only runs when it is started manually", name), nil
+	case types.ProcessStateForeground:
+		return fmt.Sprintf("Process %s is a Foreground process, it only runs when it is started from the TUI", name), nil
+	case types.ProcessStateCompleted:
+		result := "a failure"
+		if procConf.IsSuccessExitCode(state.ExitCode) {
+			result = "a success"
+		}
+		return fmt.Sprintf("Process %s Completed with exit code %d, which is %s", name, state.ExitCode, result), nil
+	case types.ProcessStateRunning, types.ProcessStateLaunched, types.ProcessStateLaunching:
+		desc := fmt.Sprintf("Process %s is %s for %v (pid %d, restarts %d)",
+			name, state.Status, state.Age.Round(time.Second), state.Pid, state.Restarts)
+		if procConf.ReadinessProbe != nil || procConf.ReadyLogLine != "" {
+			desc += ", its readiness is " + state.Health
+		}
+		return desc, nil
+	case types.ProcessStateRestarting:
+		return fmt.Sprintf("Process %s is Restarting after it exited with code %d, its restart policy is %s (restarts %d)",
+			name, state.ExitCode, procConf.RestartPolicy.Restart, state.Restarts), nil
+	case types.ProcessStateRateLimited:
+		return fmt.Sprintf("Process %s is RateLimited, it restarted %d times in the last minute (max_restarts_per_minute)",
+			name, procConf.MaxRestartsPerMinute), nil
+	case types.ProcessStateTerminating:
+		return fmt.Sprintf("Process %s is Terminating, it was asked to stop and didn't exit yet", name), nil
+	default:
+		return fmt.Sprintf("Process %s is %s", name, state.Status), nil
+	}
+}
+
+func (p *ProjectRunner) getEndReason(name string) string {
+	if proc := p.getRunningProcess(name); proc != nil {
+		if reason := proc.getEndReason(); reason != "" {
+			return reason
+		}
+	}
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	return p.endReasons[name]
+}
+
+func (p *ProjectRunner) explainPending(procConf *types.ProcessConfig) string {
+	name := procConf.ReplicaName
+	if len(procConf.DependsOn) == 0 {
+		if higher := p.startQueue.higherPriority(name); len(higher) > 0 {
+			return fmt.Sprintf("Process %s is Pending, waiting for the higher priority processes %s to start",
+				name, strings.Join(higher, ", "))
+		}
+		return fmt.Sprintf("Process %s is Pending, waiting to start", name)
+	}
+	deps := make([]string, 0, len(procConf.DependsOn))
+	for dep := range procConf.DependsOn {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	waits := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		waits = append(waits, fmt.Sprintf("%s to be %s (%s)",
+			dep, procConf.DependsOn[dep].Condition, p.describeDependencyState(dep)))
+	}
+	return fmt.Sprintf("Process %s is Pending, waiting for its dependencies: %s", name, strings.Join(waits, ", "))
+}
+
+// describeDependencyState describes the state of a dependency, or of each of its replicas
+func (p *ProjectRunner) describeDependencyState(dep string) string {
+	procs, err := p.project.GetProcesses(dep)
+	if err != nil || len(procs) == 0 {
+		return dep + " isn't defined or is disabled"
+	}
+	sort.Slice(procs, func(i, j int) bool {
+		return procs[i].ReplicaName < procs[j].ReplicaName
+	})
+	descs := make([]string, 0, len(procs))
+	for _, proc := range procs {
+		state, err := p.GetProcessState(proc.ReplicaName)
+		if err != nil {
+			descs = append(descs, proc.ReplicaName+" has no state")
+			continue
+		}
+		desc := proc.ReplicaName + " is " + state.Status
+		switch state.Status {
+		case types.ProcessStateRunning, types.ProcessStateLaunched:
+			desc += fmt.Sprintf(" for %v", state.Age.Round(time.Second))
+			if proc.ReadinessProbe != nil || proc.ReadyLogLine != "" {
+				desc += ", " + state.Health
+			}
+		case types.ProcessStateCompleted, types.ProcessStateRestarting:
+			desc += fmt.Sprintf(" with exit code %d", state.ExitCode)
+		}
+		descs = append(descs, desc)
+	}
+	return strings.Join(descs, ", ")
+}
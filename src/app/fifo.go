@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const fifoBuffer = 1024
+
+// outputFIFO writes the process output lines to a named pipe, without blocking the process output handling.
+// While no one reads the pipe, the lines are dropped once the pipe and the queue are full
+type outputFIFO struct {
+	path   string
+	file   *os.File
+	lines  chan string
+	mtx    sync.Mutex
+	closed bool
+}
+
+// newOutputFIFO creates the named pipe if it doesn't exist and opens it
+func newOutputFIFO(path string) (*outputFIFO, error) {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		if err = mkfifo(path); err != nil {
+			return nil, fmt.Errorf("failed to create the output FIFO %s: %w", path, err)
+		}
+	case err != nil:
+		return nil, err
+	case info.Mode()&os.ModeNamedPipe == 0:
+		return nil, fmt.Errorf("%s exists and is not a named pipe", path)
+	}
+	// opened for reading as well, so the open doesn't block until a reader opens the pipe
+	// and the writes don't fail once a reader closes it
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the output FIFO %s: %w", path, err)
+	}
+	return &outputFIFO{
+		path:  path,
+		file:  file,
+		lines: make(chan string, fifoBuffer),
+	}, nil
+}
+
+// send queues the line for writing. The line is dropped if the queue is full
+func (f *outputFIFO) send(line string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.closed {
+		return
+	}
+	select {
+	case f.lines <- line:
+	default:
+	}
+}
+
+// close stops the writing and closes the pipe. The queued lines are dropped
+func (f *outputFIFO) close() {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if !f.closed {
+		f.closed = true
+		close(f.lines)
+		_ = f.file.Close()
+	}
+}
+
+// run writes the queued lines until the queue is closed
+func (f *outputFIFO) run() {
+	for line := range f.lines {
+		if _, err := f.file.WriteString(line + "\n"); err != nil {
+			if !f.isClosed() {
+				log.Err(err).Msgf("failed to write to the output FIFO %s", f.path)
+			}
+			return
+		}
+	}
+}
+
+func (f *outputFIFO) isClosed() bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.closed
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package app
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func mkfifo(path string) error {
+	return fmt.Errorf("named pipes are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,9 @@
+//go:build linux || darwin
+
+package app
+
+import "syscall"
+
+func mkfifo(path string) error {
+	return syscall.Mkfifo(path, 0600)
+}
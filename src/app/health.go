@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// GetUnhealthyCriticalProcesses returns the critical processes that failed or won't run, with the reason of each.
+// All the critical processes are healthy when it is empty. The processes that are starting, restarting or waiting
+// for their dependencies aren't unhealthy. The disabled and foreground processes aren't started with the project,
+// so they are not tracked
+func GetUnhealthyCriticalProcesses(project IProject) (map[string]string, error) {
+	states, err := project.GetProcessesState()
+	if err != nil {
+		return nil, err
+	}
+	unhealthy := map[string]string{}
+	for _, state := range states.States {
+		procConf, err := project.GetProcessInfo(state.Name)
+		if err != nil || !procConf.Critical || procConf.IsDeferred() {
+			continue
+		}
+		if reason := describeUnhealthy(procConf, &state); reason != "" {
+			unhealthy[state.Name] = reason
+		}
+	}
+	return unhealthy, nil
+}
+
+// describeUnhealthy returns why the process isn't healthy, or an empty string if it is
+func describeUnhealthy(procConf *types.ProcessConfig, state *types.ProcessState) string {
+	switch state.Status {
+	case types.ProcessStateError, types.ProcessStateSkipped:
+		return state.Status
+	case types.ProcessStateCompleted:
+		if procConf.IsSuccessExitCode(state.ExitCode) {
+			return ""
+		}
+		return fmt.Sprintf("%s with exit code %d", state.Status, state.ExitCode)
+	default:
+		return ""
+	}
+}
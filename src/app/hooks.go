@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/rs/zerolog/log"
+	"strconv"
+)
+
+const (
+	EnvExitCode     = "PC_EXIT_CODE"
+	EnvRestartCount = "PC_RESTART_COUNT"
+)
+
+// onStartHook runs the on_start hook, the started process has no exit code yet
+func (p *Process) onStartHook() {
+	p.runHook("on_start", p.procConf.OnStart, 0, p.getRestarts())
+}
+
+func (p *Process) onStopHook() {
+	p.runHook("on_stop", p.procConf.OnStop, p.getExitCode(), p.getRestarts())
+}
+
+func (p *Process) onFailureHook() {
+	p.runHook("on_failure", p.procConf.OnFailure, p.getExitCode(), p.getRestarts())
+}
+
+// runHook runs the hook command in the background, so it doesn't block the process state transitions
+func (p *Process) runHook(hook, cmd string, exitCode, restarts int) {
+	if cmd == "" {
+		return
+	}
+	env := append(p.getProcessEnvironment(),
+		EnvExitCode+"="+strconv.Itoa(exitCode),
+		EnvRestartCount+"="+strconv.Itoa(restarts),
+	)
+	hookCmd := command.BuildCommandShellArgContext(context.Background(), p.shellConfig, cmd)
+	hookCmd.SetEnv(env)
+	hookCmd.SetDir(p.procConf.WorkingDir)
+	name := p.getName()
+	go func() {
+		log.Debug().Msgf("running %s %s hook", name, hook)
+		if err := hookCmd.Run(); err != nil {
+			log.Error().Err(err).Msgf("%s %s hook failed", name, hook)
+		}
+	}()
+}
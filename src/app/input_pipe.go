@@ -0,0 +1,185 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// inputPipe connects the stdout of the input_from producers to the stdin of a consumer.
+// The producers block while the pipe is full, so no output is dropped
+type inputPipe struct {
+	reader  *os.File
+	writer  *os.File
+	mtx     sync.Mutex
+	writers int
+}
+
+// newInputPipe creates a pipe written by the given number of producers.
+// Its write end is closed once all of them are done
+func newInputPipe(writers int) (*inputPipe, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	return &inputPipe{reader: reader, writer: writer, writers: writers}, nil
+}
+
+// write returns an error once the consumer stopped reading
+func (ip *inputPipe) write(line string) error {
+	_, err := io.WriteString(ip.writer, line)
+	return err
+}
+
+// closeWriter sends EOF to the consumer once the last producer is done
+func (ip *inputPipe) closeWriter() {
+	ip.mtx.Lock()
+	defer ip.mtx.Unlock()
+	ip.writers--
+	if ip.writers == 0 {
+		_ = ip.writer.Close()
+	}
+}
+
+// closeReader releases the consumer end, so the blocked producers fail their writes instead of waiting for it
+func (ip *inputPipe) closeReader() {
+	_ = ip.reader.Close()
+}
+
+// createInputPipes creates the pipes of the input_from consumers before any process starts, so the output of the
+// producers is kept until the consumers read it
+func (p *ProjectRunner) createInputPipes(runOrder []types.ProcessConfig) error {
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	p.inputPipes = make(map[string]*inputPipe)
+	p.outputPipes = make(map[string][]*inputPipe)
+	for _, consumer := range runOrder {
+		if consumer.InputFrom == "" {
+			continue
+		}
+		producers := getInputProducers(runOrder, consumer.InputFrom)
+		if len(producers) == 0 {
+			// the consumer fails to start without its producer
+			continue
+		}
+		pipe, err := newInputPipe(len(producers))
+		if err != nil {
+			return fmt.Errorf("failed to create the input pipe of process %s: %w", consumer.ReplicaName, err)
+		}
+		p.inputPipes[consumer.ReplicaName] = pipe
+		for _, producer := range producers {
+			p.outputPipes[producer] = append(p.outputPipes[producer], pipe)
+		}
+	}
+	return nil
+}
+
+// getInputProducers returns the names of the process, or of all its replicas
+func getInputProducers(processes []types.ProcessConfig, name string) []string {
+	var producers []string
+	for _, proc := range processes {
+		if proc.Name == name || proc.ReplicaName == name {
+			producers = append(producers, proc.ReplicaName)
+		}
+	}
+	return producers
+}
+
+func (p *ProjectRunner) popOutputPipes(name string) []*inputPipe {
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	pipes := p.outputPipes[name]
+	delete(p.outputPipes, name)
+	return pipes
+}
+
+// getInputPipe returns the pipe created for the consumer on startup or, when it's started again, a new pipe from
+// its running producers
+func (p *ProjectRunner) getInputPipe(consumer *types.ProcessConfig) (*inputPipe, error) {
+	p.runProcMutex.Lock()
+	pipe, ok := p.inputPipes[consumer.ReplicaName]
+	delete(p.inputPipes, consumer.ReplicaName)
+	var producers []*Process
+	for _, proc := range p.runningProcesses {
+		if proc.procConf.Name == consumer.InputFrom || proc.getName() == consumer.InputFrom {
+			producers = append(producers, proc)
+		}
+	}
+	p.runProcMutex.Unlock()
+	if ok {
+		return pipe, nil
+	}
+	if len(producers) == 0 {
+		return nil, fmt.Errorf("process %s reads its input from %s, but it isn't running", consumer.ReplicaName, consumer.InputFrom)
+	}
+	pipe, err := newInputPipe(len(producers))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the input pipe of process %s: %w", consumer.ReplicaName, err)
+	}
+	for _, producer := range producers {
+		producer.addOutputPipe(pipe)
+	}
+	return pipe, nil
+}
+
+// discardInputPipe closes the pipe created for a consumer that won't run
+func (p *ProjectRunner) discardInputPipe(name string) {
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	if pipe, ok := p.inputPipes[name]; ok {
+		pipe.closeReader()
+		delete(p.inputPipes, name)
+	}
+}
+
+// addOutputPipe forwards the process stdout to the pipe, or closes its end of the pipe if the process has ended
+func (p *Process) addOutputPipe(pipe *inputPipe) {
+	p.outputPipesMtx.Lock()
+	defer p.outputPipesMtx.Unlock()
+	if p.outputPipesClosed {
+		pipe.closeWriter()
+		return
+	}
+	p.outputPipes = append(p.outputPipes, pipe)
+}
+
+// writeOutputPipes forwards the raw stdout line to the input_from consumers
+func (p *Process) writeOutputPipes(line string) {
+	p.outputPipesMtx.Lock()
+	pipes := slices.Clone(p.outputPipes)
+	p.outputPipesMtx.Unlock()
+	for _, pipe := range pipes {
+		if err := pipe.write(line); err != nil {
+			log.Debug().Err(err).Msgf("stopped forwarding %s output to its consumer", p.getName())
+			p.removeOutputPipe(pipe)
+		}
+	}
+}
+
+func (p *Process) removeOutputPipe(pipe *inputPipe) {
+	p.outputPipesMtx.Lock()
+	defer p.outputPipesMtx.Unlock()
+	if !slices.Contains(p.outputPipes, pipe) {
+		return
+	}
+	p.outputPipes = slices.DeleteFunc(p.outputPipes, func(op *inputPipe) bool {
+		return op == pipe
+	})
+	pipe.closeWriter()
+}
+
+// closeOutputPipes sends EOF to the input_from consumers
+func (p *Process) closeOutputPipes() {
+	p.outputPipesMtx.Lock()
+	defer p.outputPipesMtx.Unlock()
+	for _, pipe := range p.outputPipes {
+		pipe.closeWriter()
+	}
+	p.outputPipes = nil
+	p.outputPipesClosed = true
+}
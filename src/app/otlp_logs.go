@@ -0,0 +1,314 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	envOtlpEndpoint     = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOtlpLogsEndpoint = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
+	envOtlpHeaders      = "OTEL_EXPORTER_OTLP_HEADERS"
+	envOtlpLogsHeaders  = "OTEL_EXPORTER_OTLP_LOGS_HEADERS"
+	envOtlpProtocol     = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	envOtlpLogsProtocol = "OTEL_EXPORTER_OTLP_LOGS_PROTOCOL"
+	envOtlpTimeout      = "OTEL_EXPORTER_OTLP_TIMEOUT"
+	envOtlpLogsTimeout  = "OTEL_EXPORTER_OTLP_LOGS_TIMEOUT"
+	envOtelServiceName  = "OTEL_SERVICE_NAME"
+
+	otlpLogsPath          = "/v1/logs"
+	otlpDefaultService    = "process-compose"
+	otlpScopeName         = "github.com/f1bonacc1/process-compose"
+	otlpBatchSize         = 512
+	otlpQueueSize         = 2048
+	otlpFlushInterval     = time.Second
+	otlpDefaultTimeout    = 10 * time.Second
+	otlpSeverityInfo      = 9
+	otlpSeverityError     = 17
+	otlpAttrProcess       = "process_compose.process.name"
+	otlpAttrReplicaIndex  = "process_compose.process.replica_index"
+	otlpAttrRunID         = "process_compose.run_id"
+	otlpAttrServiceName   = "service.name"
+	otlpAttrLogStream     = "log.iostream"
+	otlpGrpcProtocol      = "grpc"
+	otlpHttpProtobufProto = "http/protobuf"
+	otlpHttpJsonProto     = "http/json"
+)
+
+// otlpLogsConfig is the OTLP logs exporter configuration, read from the standard OTEL_* environment variables
+type otlpLogsConfig struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	timeout     time.Duration
+}
+
+// newOtlpLogsConfig reads the exporter configuration. It returns nil when no OTLP endpoint is configured
+func newOtlpLogsConfig() *otlpLogsConfig {
+	endpoint := os.Getenv(envOtlpLogsEndpoint)
+	if endpoint == "" {
+		base := os.Getenv(envOtlpEndpoint)
+		if base == "" {
+			return nil
+		}
+		endpoint = strings.TrimSuffix(base, "/") + otlpLogsPath
+	}
+	// only OTLP/HTTP JSON is supported, also when the protocol is unset and the OpenTelemetry default is protobuf
+	protocol := getFirstEnv(envOtlpLogsProtocol, envOtlpProtocol)
+	switch protocol {
+	case "", otlpHttpJsonProto:
+	case otlpGrpcProtocol:
+		log.Warn().Msgf("OTLP %s protocol isn't supported, configure an OTLP/HTTP endpoint to export the process output", protocol)
+		return nil
+	default:
+		log.Warn().Msgf("OTLP %s protocol isn't supported, the process output is exported as %s", protocol, otlpHttpJsonProto)
+	}
+	headers := parseOtlpHeaders(os.Getenv(envOtlpHeaders))
+	for k, v := range parseOtlpHeaders(os.Getenv(envOtlpLogsHeaders)) {
+		headers[k] = v
+	}
+	serviceName := os.Getenv(envOtelServiceName)
+	if serviceName == "" {
+		serviceName = otlpDefaultService
+	}
+	return &otlpLogsConfig{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		timeout:     parseOtlpTimeout(getFirstEnv(envOtlpLogsTimeout, envOtlpTimeout)),
+	}
+}
+
+// parseOtlpTimeout parses the export timeout in milliseconds
+func parseOtlpTimeout(val string) time.Duration {
+	if val == "" {
+		return otlpDefaultTimeout
+	}
+	ms, err := strconv.Atoi(val)
+	if err != nil || ms <= 0 {
+		log.Warn().Msgf("invalid OTLP timeout '%s', defaulting to %v", val, otlpDefaultTimeout)
+		return otlpDefaultTimeout
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func getFirstEnv(names ...string) string {
+	for _, name := range names {
+		if val := os.Getenv(name); val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+// parseOtlpHeaders parses the W3C baggage style key1=value1,key2=value2 headers list
+func parseOtlpHeaders(list string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(list, ",") {
+		key, val, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(strings.TrimSpace(val)); err == nil {
+			val = unescaped
+		}
+		headers[key] = val
+	}
+	return headers
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+func otlpString(key, val string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &val}}
+}
+
+// otlpInt encodes an int attribute, the OTLP JSON encoding of 64-bit integers is a decimal string
+func otlpInt(key string, val int) otlpKeyValue {
+	str := strconv.Itoa(val)
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: &str}}
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// otlpLogExporter exports the output lines of a process as OTLP log records, in batches, without blocking the
+// process output handling
+type otlpLogExporter struct {
+	conf     *otlpLogsConfig
+	resource otlpResource
+	client   *http.Client
+	records  chan otlpLogRecord
+	done     chan struct{}
+	mtx      sync.Mutex
+	closed   bool
+}
+
+func newOtlpLogExporter(conf *otlpLogsConfig, process string, replica int, runID string) *otlpLogExporter {
+	return &otlpLogExporter{
+		conf: conf,
+		resource: otlpResource{Attributes: []otlpKeyValue{
+			otlpString(otlpAttrServiceName, conf.serviceName),
+			otlpString(otlpAttrProcess, process),
+			otlpInt(otlpAttrReplicaIndex, replica),
+			otlpString(otlpAttrRunID, runID),
+		}},
+		client:  &http.Client{Timeout: conf.timeout},
+		records: make(chan otlpLogRecord, otlpQueueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// send queues the line for export. The line is dropped if the queue is full
+func (e *otlpLogExporter) send(stream, line string) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.closed {
+		return
+	}
+	severity, severityText := otlpSeverityInfo, "INFO"
+	if stream == "stderr" {
+		severity, severityText = otlpSeverityError, "ERROR"
+	}
+	ts := strconv.FormatInt(time.Now().UnixNano(), 10)
+	select {
+	case e.records <- otlpLogRecord{
+		TimeUnixNano:         ts,
+		ObservedTimeUnixNano: ts,
+		SeverityNumber:       severity,
+		SeverityText:         severityText,
+		Body:                 otlpAnyValue{StringValue: &line},
+		Attributes:           []otlpKeyValue{otlpString(otlpAttrLogStream, stream)},
+	}:
+	default:
+		log.Warn().Msgf("OTLP logs queue is full, dropping a line")
+	}
+}
+
+// close stops the export and waits for the queued records to be flushed
+func (e *otlpLogExporter) close() {
+	e.mtx.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.records)
+	}
+	e.mtx.Unlock()
+	select {
+	case <-e.done:
+	case <-time.After(e.conf.timeout):
+		log.Warn().Msgf("timed out flushing the OTLP logs to %s", e.conf.endpoint)
+	}
+}
+
+// run exports the queued records in batches until the queue is closed
+func (e *otlpLogExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+	batch := make([]otlpLogRecord, 0, otlpBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.export(batch); err != nil {
+			log.Err(err).Msgf("failed to export %d log records to %s", len(batch), e.conf.endpoint)
+		}
+		batch = make([]otlpLogRecord, 0, otlpBatchSize)
+	}
+	for {
+		select {
+		case record, ok := <-e.records:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) == otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (e *otlpLogExporter) export(records []otlpLogRecord) error {
+	body, err := json.Marshal(otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: e.resource,
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: otlpScopeName, Version: config.Version},
+				LogRecords: records,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.conf.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.conf.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	outputRateInterval = time.Second
+	outputRateWindow   = 10 * time.Second
+)
+
+// outputRateMeter measures the output lines per second as an exponentially weighted moving average
+type outputRateMeter struct {
+	lines   atomic.Int64
+	dropped atomic.Int64
+	maxRate float64
+	rateMtx sync.Mutex
+	rate    float64
+}
+
+func newOutputRateMeter(maxRate float64) *outputRateMeter {
+	return &outputRateMeter{maxRate: maxRate}
+}
+
+// add counts an output line. It returns false if the line exceeds the max rate and should be dropped
+func (m *outputRateMeter) add() bool {
+	lines := m.lines.Add(1)
+	if m.maxRate > 0 && float64(lines) > m.maxRate*outputRateInterval.Seconds() {
+		m.dropped.Add(1)
+		return false
+	}
+	return true
+}
+
+// tick updates the rate with the lines counted since the previous tick and returns the lines dropped meanwhile
+func (m *outputRateMeter) tick(elapsed time.Duration) int64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	current := float64(m.lines.Swap(0)) / elapsed.Seconds()
+	alpha := 1 - math.Exp(-elapsed.Seconds()/outputRateWindow.Seconds())
+	m.rateMtx.Lock()
+	m.rate += alpha * (current - m.rate)
+	m.rateMtx.Unlock()
+	return m.dropped.Swap(0)
+}
+
+func (m *outputRateMeter) getRate() float64 {
+	m.rateMtx.Lock()
+	defer m.rateMtx.Unlock()
+	return m.rate
+}
+
+func (m *outputRateMeter) reset() {
+	m.lines.Store(0)
+	m.dropped.Store(0)
+	m.rateMtx.Lock()
+	m.rate = 0
+	m.rateMtx.Unlock()
+}
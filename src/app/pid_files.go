@@ -0,0 +1,139 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	puproc "github.com/shirou/gopsutil/v4/process"
+)
+
+const pidFileExt = ".pid"
+
+func getPidFilePath(dir, name string) string {
+	return filepath.Join(dir, strings.ReplaceAll(name, "/", "_")+pidFileExt)
+}
+
+// writePidFile records the process PID and creation time, so the PID can't be confused with a reused one
+func writePidFile(dir, name string, pid int) {
+	proc, err := puproc.NewProcess(int32(pid))
+	if err != nil {
+		log.Err(err).Msgf("failed to get the %s process info", name)
+		return
+	}
+	createTime, err := proc.CreateTime()
+	if err != nil {
+		log.Err(err).Msgf("failed to get the %s process creation time", name)
+		return
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		log.Err(err).Msgf("failed to create the PID directory %s", dir)
+		return
+	}
+	content := fmt.Sprintf("%d %d\n", pid, createTime)
+	if err = os.WriteFile(getPidFilePath(dir, name), []byte(content), 0600); err != nil {
+		log.Err(err).Msgf("failed to write the %s PID file", name)
+	}
+}
+
+func removePidFile(dir, name string) {
+	if err := os.Remove(getPidFilePath(dir, name)); err != nil && !os.IsNotExist(err) {
+		log.Err(err).Msgf("failed to remove the %s PID file", name)
+	}
+}
+
+// cleanupPidDir terminates the processes left running by a previous process-compose run and removes their PID files
+func cleanupPidDir(dir string) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+pidFileExt))
+	if err != nil {
+		log.Err(err).Msgf("failed to list the PID files in %s", dir)
+		return
+	}
+	for _, file := range files {
+		terminateOrphan(file)
+		if err = os.Remove(file); err != nil {
+			log.Err(err).Msgf("failed to remove the PID file %s", file)
+		}
+	}
+}
+
+func terminateOrphan(file string) {
+	proc := readPidFile(file)
+	if proc == nil {
+		return
+	}
+	log.Warn().Msgf("Terminating orphan process %d from %s", proc.Pid, file)
+	if err := proc.Terminate(); err != nil {
+		log.Err(err).Msgf("failed to terminate orphan process %d", proc.Pid)
+	}
+}
+
+// readPidFile returns the running process recorded in the PID file, or nil if it isn't running anymore
+func readPidFile(file string) *puproc.Process {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.Err(err).Msgf("failed to read the PID file %s", file)
+		return nil
+	}
+	var pid int32
+	var createTime int64
+	if _, err = fmt.Sscanf(string(data), "%d %d", &pid, &createTime); err != nil {
+		log.Warn().Msgf("ignoring the invalid PID file %s", file)
+		return nil
+	}
+	proc, err := puproc.NewProcess(pid)
+	if err != nil {
+		// not running anymore
+		return nil
+	}
+	if actual, err := proc.CreateTime(); err != nil || actual != createTime {
+		log.Debug().Msgf("PID %d from %s was reused by another process, not terminating it", pid, file)
+		return nil
+	}
+	return proc
+}
+
+// StopPidDir terminates the processes recorded in the PID directory, kills the ones that are still running
+// after the timeout and removes the PID files. It returns the number of processes that were running
+func StopPidDir(dir string, timeout time.Duration) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+pidFileExt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the PID files in %s: %w", dir, err)
+	}
+	var running []*puproc.Process
+	for _, file := range files {
+		if proc := readPidFile(file); proc != nil {
+			log.Info().Msgf("Terminating process %d from %s", proc.Pid, file)
+			if err = proc.Terminate(); err != nil {
+				log.Err(err).Msgf("failed to terminate process %d", proc.Pid)
+			}
+			running = append(running, proc)
+		}
+	}
+	deadline := time.Now().Add(timeout)
+	for _, proc := range running {
+		for isPidRunning(proc) && time.Now().Before(deadline) {
+			time.Sleep(stopPollInterval)
+		}
+		if isPidRunning(proc) {
+			log.Warn().Msgf("Process %d didn't exit within %v, killing it", proc.Pid, timeout)
+			if err = proc.Kill(); err != nil {
+				log.Err(err).Msgf("failed to kill process %d", proc.Pid)
+			}
+		}
+	}
+	for _, file := range files {
+		if err = os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Err(err).Msgf("failed to remove the PID file %s", file)
+		}
+	}
+	return len(running), nil
+}
+
+func isPidRunning(proc *puproc.Process) bool {
+	running, err := proc.IsRunning()
+	return err == nil && running
+}
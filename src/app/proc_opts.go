@@ -26,6 +26,21 @@ func withLogger(logger pclog.PcLogger) ProcOpts {
 	}
 }
 
+// withStdLoggers sets dedicated stdout and stderr loggers. A nil logger falls back to the process logger
+func withStdLoggers(stdoutLogger, stderrLogger pclog.PcLogger) ProcOpts {
+	return func(proc *Process) {
+		proc.stdoutLogger = stdoutLogger
+		proc.stderrLogger = stderrLogger
+	}
+}
+
+// withLineTemplate renders the process output lines with the log_line_template
+func withLineTemplate(tmpl *pclog.LineTemplate) ProcOpts {
+	return func(proc *Process) {
+		proc.lineTemplate = tmpl
+	}
+}
+
 func withProcConf(procConf *types.ProcessConfig) ProcOpts {
 	return func(proc *Process) {
 		proc.procConf = procConf
@@ -67,3 +82,51 @@ func withExtraArgs(extraArgs []string) ProcOpts {
 		proc.extraArgs = extraArgs
 	}
 }
+
+func withPidDir(pidDir string) ProcOpts {
+	return func(proc *Process) {
+		proc.pidDir = pidDir
+	}
+}
+
+func withStateChangeFn(fn func()) ProcOpts {
+	return func(proc *Process) {
+		proc.stateChangeFn = fn
+	}
+}
+
+func withOutputPipes(pipes []*inputPipe) ProcOpts {
+	return func(proc *Process) {
+		proc.outputPipes = pipes
+	}
+}
+
+func withEndFn(fn func(proc *Process)) ProcOpts {
+	return func(proc *Process) {
+		proc.endFn = fn
+	}
+}
+
+func withSecretEnvVarFn(fn func(string) bool) ProcOpts {
+	return func(proc *Process) {
+		proc.isSecretEnvVar = fn
+	}
+}
+
+func withRunID(runID string) ProcOpts {
+	return func(proc *Process) {
+		proc.runID = runID
+	}
+}
+
+func withIsolation(isIsolated bool) ProcOpts {
+	return func(proc *Process) {
+		proc.isIsolated = isIsolated
+	}
+}
+
+func withOtlpLogs(conf *otlpLogsConfig) ProcOpts {
+	return func(proc *Process) {
+		proc.otlpLogs = conf
+	}
+}
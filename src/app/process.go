@@ -2,13 +2,15 @@ package app
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,13 +33,23 @@ import (
 const (
 	UndefinedShutdownTimeoutSec = 0
 	DefaultShutdownTimeoutSec   = 10
+	DefaultGracePeriodSec       = 30
 	EnvReplicaNum               = "PC_REPLICA_NUM"
 	LogReplicaNum               = "{" + EnvReplicaNum + "}"
+	EnvRunID                    = "PROCESS_COMPOSE_RUN_ID"
+	EnvRestartID                = "PROCESS_COMPOSE_RESTART_ID"
+	outputBlockSize             = 64 * 1024
+	maxRestartBackoff           = 5 * time.Minute
+	restartRateWindow           = time.Minute
+	restartContextLines         = 10
+	minMaskedSecretLen          = 4
+	maskedSecret                = "[REDACTED]"
 )
 
 type Process struct {
 	sync.Mutex
 	globalEnv           []string
+	envFromProcess      []string
 	confMtx             sync.Mutex
 	procConf            *types.ProcessConfig
 	procState           *types.ProcessState
@@ -54,18 +66,26 @@ type Process struct {
 	waitForPassCtx      context.Context
 	waitForPassCancelFn context.CancelFunc
 	mtxStopFn           sync.Mutex
+	pendingEndMtx       sync.Mutex
 	waitForStoppedCtx   context.Context
 	waitForStoppedFn    context.CancelFunc
 	procColor           func(a ...interface{}) string
+	lineColor           func(a ...interface{}) string
 	noColor             func(a ...interface{}) string
 	redColor            func(a ...interface{}) string
 	logBuffer           *pclog.ProcessLogBuffer
 	logger              pclog.PcLogger
+	stdoutLogger        pclog.PcLogger
+	stderrLogger        pclog.PcLogger
+	lineTemplate        *pclog.LineTemplate
 	command             command.Commander
 	started             bool
+	endReason           string
 	done                bool
+	endedRuns           int
 	timeMutex           sync.Mutex
 	startTime           time.Time
+	endTime             time.Time
 	liveProber          *health.Prober
 	readyProber         *health.Prober
 	shellConfig         command.ShellConfig
@@ -73,32 +93,56 @@ type Process struct {
 	isMain              bool
 	extraArgs           []string
 	isStopped           atomic.Bool
+	isShutDown          atomic.Bool
+	isSecretEnvVar      func(string) bool
+	secretMasker        *strings.Replacer
 	stdin               io.WriteCloser
 	passProvided        bool
 	isTuiEnabled        bool
 	stdOutDone          chan struct{}
 	stdErrDone          chan struct{}
+	runID               string
+	restartID           string
+	lastOutputMtx       sync.Mutex
+	lastOutputLine      string
+	outputRate          *outputRateMeter
+	inputPipe           *inputPipe
+	restartTimes        []time.Time
+	webhook             *outputWebhook
+	fifo                *outputFIFO
+	otlpLogs            *otlpLogsConfig
+	otlpExporter        *otlpLogExporter
+	isIsolated          bool
+	pidDir              string
+	stateChangeFn       func()
+	endFn               func(proc *Process)
+	removed             chan struct{}
+	outputPipesMtx      sync.Mutex
+	outputPipes         []*inputPipe
+	outputPipesClosed   bool
 }
 
 func NewProcess(opts ...ProcOpts) *Process {
-	colNumeric := rand.Intn(int(color.FgHiWhite)-int(color.FgHiBlack)) + int(color.FgHiBlack)
-
 	proc := &Process{
-		procColor:     color.New(color.Attribute(colNumeric), color.Bold).SprintFunc(),
-		redColor:      color.New(color.FgHiRed).SprintFunc(),
+		redColor:      newColor(color.FgHiRed).SprintFunc(),
 		noColor:       color.New(color.Reset).SprintFunc(),
 		started:       false,
 		done:          false,
 		procStateChan: make(chan string, 1),
+		removed:       make(chan struct{}),
 	}
 
 	for _, opt := range opts {
 		opt(proc)
 	}
 
+	colorAttrs := processColorAttrs(proc.getName(), proc.procConf.Color)
+	proc.procColor = newColor(append(colorAttrs, color.Bold)...).SprintFunc()
+	proc.lineColor = newColor(colorAttrs...).SprintFunc()
 	proc.procReadyCtx, proc.readyCancelFn = context.WithCancel(context.Background())
 	proc.procLogReadyCtx, proc.readyLogCancelFn = context.WithCancelCause(context.Background())
 	proc.procRunCtx, proc.runCancelFn = context.WithCancel(context.Background())
+	proc.outputRate = newOutputRateMeter(proc.procConf.MaxOutputRate)
 	proc.setUpProbes()
 	proc.procCond = *sync.NewCond(proc)
 	proc.procStartedCond = *sync.NewCond(proc)
@@ -112,16 +156,46 @@ func (p *Process) run() int {
 
 	if err := p.validateProcess(); err != nil {
 		log.Error().Err(err).Msgf(`Failed to run command ["%v"] for process %s`, strings.Join(p.getCommand(), `" "`), p.getName())
+		p.logRunbook()
+		p.setEndReason(err)
 		p.onProcessEnd(types.ProcessStateError)
 		return 1
 	}
 
 	p.onProcessStart()
+	stopOutputRate := p.meterOutputRate()
+	defer stopOutputRate()
+	if p.procConf.OutputWebhook != "" {
+		p.webhook = newOutputWebhook(p.procConf.OutputWebhook)
+		go p.webhook.run()
+		defer p.webhook.close()
+	}
+	if p.otlpLogs != nil {
+		p.otlpExporter = newOtlpLogExporter(p.otlpLogs, p.procConf.Name, p.procConf.ReplicaNum, p.runID)
+		go p.otlpExporter.run()
+		defer p.otlpExporter.close()
+	}
+	if p.procConf.OutputFIFO != "" {
+		fifo, err := newOutputFIFO(p.getOutputFIFOPath())
+		if err != nil {
+			log.Err(err).Msgf("failed to create the output FIFO of %s", p.getName())
+		} else {
+			p.fifo = fifo
+			go p.fifo.run()
+			defer p.fifo.close()
+		}
+	}
+	p.secretMasker = p.newSecretMasker()
 	for {
+		p.restartID = pclog.GenerateUUID()
+		p.writeLogSeparator()
 		err := p.setStateAndRun(p.getStartingStateName(), p.getProcessStarter())
 		if err != nil {
 			log.Error().Err(err).Msgf(`Failed to run command ["%v"] for process %s`, strings.Join(p.getCommand(), `" "`), p.getName())
 			p.logBuffer.Write(err.Error())
+			p.logRunbook()
+			p.onFailureHook()
+			p.setEndReason(err)
 			p.onProcessEnd(types.ProcessStateError)
 			return 1
 		}
@@ -130,41 +204,66 @@ func (p *Process) run() int {
 		p.stateMtx.Lock()
 		p.procState.Pid = p.command.Pid()
 		p.stateMtx.Unlock()
+		if p.pidDir != "" {
+			writePidFile(p.pidDir, p.getName(), p.command.Pid())
+		}
 		log.Info().
 			Str("process", p.getName()).
 			Strs("command", p.getCommand()).
 			Msg("Started")
+		p.onStartHook()
 
 		p.startProbes()
 
 		p.waitForStdOutErr()
 		_ = p.command.Wait()
+		if p.pidDir != "" {
+			removePidFile(p.pidDir, p.getName())
+		}
 		p.Lock()
 		p.setExitCode(p.command.ExitCode())
+		p.endedRuns++
 		p.Unlock()
+		p.procCond.Broadcast()
 		log.Info().
 			Str("process", p.getName()).
 			Int("exit_code", p.getExitCode()).
 			Msg("Exited")
+		// a -1 exit code means the process was killed by a signal
+		if p.procConf.EnableCoreDump && p.getExitCode() == -1 && !p.isState(types.ProcessStateTerminating) {
+			p.reportCoreDump(p.command.Pid())
+		}
+		p.onStopHook()
+		if !p.procConf.IsSuccessExitCode(p.getExitCode()) {
+			p.logRunbook()
+			p.onFailureHook()
+		}
 
 		if p.isDaemonLaunched() {
 			p.setState(types.ProcessStateLaunched)
 			p.waitForDaemonCompletion()
 		}
 
+		if p.isStable() && p.getRestarts() > 0 {
+			log.Debug().Msgf("%s ran for at least %v, resetting its restarts", p.getName(), p.procConf.MinUptime)
+			p.setRestarts(0)
+		}
 		if !p.isRestartable() {
 			break
 		}
+		if !p.waitForRestartRate() {
+			break
+		}
 		p.setState(types.ProcessStateRestarting)
-		p.procState.Restarts += 1
-		log.Info().Msgf("Restarting %s in %v second(s)... Restarts: %d",
-			p.getName(), p.getBackoff().Seconds(), p.procState.Restarts)
+		p.setRestarts(p.getRestarts() + 1)
+		backoff := p.getRestartBackoff()
+		p.notifyRestart(backoff)
 
 		select {
 		case <-p.procRunCtx.Done():
 			log.Debug().Str("process", p.getName()).Msg("process stopped while waiting to restart")
 			break
-		case <-time.After(p.getBackoff()):
+		case <-time.After(backoff):
 			p.handleInfo("\n")
 			continue
 		}
@@ -173,6 +272,140 @@ func (p *Process) run() int {
 	return p.getExitCode()
 }
 
+// notifyRestart reports the restart with the context to diagnose a crash loop: the exit code, the uptime and the
+// last output lines of the process. It is also sent to the output webhook
+func (p *Process) notifyRestart(backoff time.Duration) {
+	uptime := time.Since(p.getStartTime()).Round(time.Millisecond)
+	lastLines := slices.Clone(p.logBuffer.GetLogRange(restartContextLines, 0))
+	log.Warn().
+		Str("process", p.getName()).
+		Int("restarts", p.procState.Restarts).
+		Int("exit_code", p.getExitCode()).
+		Str("uptime", uptime.String()).
+		Strs("last_lines", lastLines).
+		Msgf("Restarting %s in %v second(s)... Restarts: %d", p.getName(), backoff.Seconds(), p.procState.Restarts)
+	if p.webhook != nil {
+		p.webhook.sendRestart(&webhookRestart{
+			Process:   p.getName(),
+			Event:     webhookEventRestart,
+			Restarts:  p.procState.Restarts,
+			ExitCode:  p.getExitCode(),
+			Uptime:    uptime.String(),
+			LastLines: lastLines,
+			Ts:        time.Now().Format(time.RFC3339Nano),
+		})
+	}
+}
+
+// waitForRestartRate holds the restart while the process restarted max_restarts_per_minute times in the last minute.
+// It returns false if the process was stopped meanwhile
+func (p *Process) waitForRestartRate() bool {
+	limit := p.procConf.MaxRestartsPerMinute
+	if limit <= 0 {
+		return true
+	}
+	now := time.Now()
+	p.restartTimes = slices.DeleteFunc(p.restartTimes, func(t time.Time) bool {
+		return now.Sub(t) >= restartRateWindow
+	})
+	if len(p.restartTimes) >= limit {
+		wait := p.restartTimes[len(p.restartTimes)-limit].Add(restartRateWindow).Sub(now)
+		log.Warn().
+			Str("process", p.getName()).
+			Int("max_restarts_per_minute", limit).
+			Msgf("Restart rate limit reached, holding restarts for %v", wait.Round(time.Second))
+		p.setState(types.ProcessStateRateLimited)
+		select {
+		case <-p.procRunCtx.Done():
+			return false
+		case <-time.After(wait):
+		}
+	}
+	p.restartTimes = append(p.restartTimes, time.Now())
+	return true
+}
+
+// meterOutputRate updates the output rate every interval until the returned stop function is called
+func (p *Process) meterOutputRate() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(outputRateInterval)
+		defer ticker.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				p.outputRate.reset()
+				return
+			case now := <-ticker.C:
+				if dropped := p.outputRate.tick(now.Sub(last)); dropped > 0 {
+					msg := fmt.Sprintf("dropped %d output lines exceeding max_output_rate of %v lines/s", dropped, p.procConf.MaxOutputRate)
+					log.Warn().Str("process", p.getName()).Msg(msg)
+					p.logBuffer.Write(msg)
+				}
+				last = now
+			}
+		}
+	}()
+	return cancel
+}
+
+// runDetached launches the process in its own session with no output capture.
+// The process isn't supervised and can outlive process-compose
+func (p *Process) runDetached() int {
+	// detached processes are not tracked once launched, their startup is over either way
+	defer p.setStarted()
+	if err := p.validateProcess(); err != nil {
+		log.Error().Err(err).Msgf(`Failed to run command ["%v"] for process %s`, strings.Join(p.getCommand(), `" "`), p.getName())
+		p.setState(types.ProcessStateError)
+		return 1
+	}
+	p.restartID = pclog.GenerateUUID()
+	cmd := command.BuildCommand(p.procConf.Executable, p.mergeExtraArgs())
+	cmd.SetEnv(p.getProcessEnvironment())
+	cmd.SetDir(p.procConf.WorkingDir)
+	cmd.Detach()
+	if p.isProcessIsolated() {
+		cmd.Isolate()
+	}
+	if err := cmd.Start(); err != nil {
+		log.Error().Err(err).Msgf(`Failed to run command ["%v"] for process %s`, strings.Join(p.getCommand(), `" "`), p.getName())
+		p.logRunbook()
+		p.setState(types.ProcessStateError)
+		return 1
+	}
+	p.command = cmd
+	p.setStartTime(time.Now())
+	p.stateMtx.Lock()
+	p.procState.Pid = cmd.Pid()
+	p.stateMtx.Unlock()
+	p.setState(types.ProcessStateLaunched)
+	// reap the process if it ends before process-compose
+	go cmd.Wait()
+	log.Info().
+		Str("process", p.getName()).
+		Int("pid", cmd.Pid()).
+		Strs("command", p.getCommand()).
+		Msg("Detached")
+	return 0
+}
+
+// logRunbook points to the process runbook when the process fails
+func (p *Process) logRunbook() {
+	// updated by updateInfo
+	p.stateMtx.Lock()
+	owner, runbookURL := p.procConf.Owner, p.procConf.RunbookURL
+	p.stateMtx.Unlock()
+	if !isStringDefined(runbookURL) {
+		return
+	}
+	log.Error().
+		Str("process", p.getName()).
+		Str("owner", owner).
+		Str("runbook_url", runbookURL).
+		Msg("Process failed, see runbook")
+}
+
 func (p *Process) waitForStdOutErr() {
 	if p.stdOutDone != nil {
 		<-p.stdOutDone
@@ -189,6 +422,9 @@ func (p *Process) getProcessStarter() func() error {
 		p.command = p.getCommander()
 		p.command.SetEnv(p.getProcessEnvironment())
 		p.command.SetDir(p.procConf.WorkingDir)
+		if p.isProcessIsolated() {
+			p.command.Isolate()
+		}
 
 		if p.isMain || (p.procConf.IsElevated && !p.isTuiEnabled) {
 			p.command.AttachIo()
@@ -196,11 +432,11 @@ func (p *Process) getProcessStarter() func() error {
 			p.command.SetCmdArgs()
 			stdout, _ := p.command.StdoutPipe()
 			p.stdOutDone = make(chan struct{})
-			go p.handleOutput(stdout, "stdout", p.handleInfo, p.stdOutDone)
+			go p.handleOutput(stdout, "stdout", p.handleInfo, p.writeOutputPipes, p.stdOutDone)
 			if !p.procConf.IsTty {
 				stderr, _ := p.command.StderrPipe()
 				p.stdErrDone = make(chan struct{})
-				go p.handleOutput(stderr, "stderr", p.handleError, p.stdErrDone)
+				go p.handleOutput(stderr, "stderr", p.handleError, nil, p.stdErrDone)
 			}
 		}
 
@@ -210,10 +446,54 @@ func (p *Process) getProcessStarter() func() error {
 				log.Error().Err(err).Msg("Failed to get stdin pipe")
 			}
 			p.stdin = stdin
+		} else if p.procConf.StdinFile != "" {
+			stdinFile, err := os.Open(p.getStdinPath())
+			if err != nil {
+				return fmt.Errorf("failed to open stdin file: %w", err)
+			}
+			// the child holds its own copy of the descriptor once started
+			defer stdinFile.Close()
+			p.command.SetStdin(stdinFile)
+		} else if p.procConf.StdinData != "" {
+			// stdin is closed once the data is written
+			p.command.SetStdin(strings.NewReader(p.procConf.StdinData))
+		} else if p.procConf.KeepStdinOpen && p.isTuiEnabled {
+			// the TUI owns the terminal, the input is forwarded by writeStdin
+			stdin, err := p.command.StdinPipe()
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to get stdin pipe")
+			}
+			p.stdin = stdin
+		} else if p.procConf.KeepStdinOpen {
+			p.command.SetStdin(os.Stdin)
+		} else if p.inputPipe != nil {
+			p.command.SetStdin(p.inputPipe.reader)
 		}
 
-		return p.command.Start()
+		return startWithUlimits(p.procConf.Ulimits, p.getName(), p.command)
+	}
+}
+
+// getStdinPath resolves a relative stdin_file against the process working directory
+func (p *Process) getStdinPath() string {
+	if filepath.IsAbs(p.procConf.StdinFile) || p.procConf.WorkingDir == "" {
+		return p.procConf.StdinFile
+	}
+	return filepath.Join(p.procConf.WorkingDir, p.procConf.StdinFile)
+}
+
+// getOutputFIFOPath resolves a relative output_fifo against the process working directory
+func (p *Process) getOutputFIFOPath() string {
+	if filepath.IsAbs(p.procConf.OutputFIFO) || p.procConf.WorkingDir == "" {
+		return p.procConf.OutputFIFO
 	}
+	return filepath.Join(p.procConf.WorkingDir, p.procConf.OutputFIFO)
+}
+
+// isProcessIsolated reports whether the process runs in its own PID and mount namespaces, for all the processes
+// (--isolate) or for this one
+func (p *Process) isProcessIsolated() bool {
+	return p.isIsolated || p.procConf.Isolated
 }
 
 func (p *Process) getCommander() command.Commander {
@@ -255,17 +535,98 @@ func (p *Process) getBackoff() time.Duration {
 	return time.Duration(backoff) * time.Second
 }
 
+// isStable returns true if the process ran for at least min_uptime (if defined)
+func (p *Process) isStable() bool {
+	return p.procConf.MinUptime > 0 && time.Since(p.getStartTime()) >= p.procConf.MinUptime
+}
+
+// getRestartBackoff doubles the backoff for each restart of a process that doesn't reach min_uptime
+func (p *Process) getRestartBackoff() time.Duration {
+	backoff := p.getBackoff()
+	if p.procConf.MinUptime <= 0 {
+		return backoff
+	}
+	for i := 1; i < p.procState.Restarts && backoff < maxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	return max(min(backoff, maxRestartBackoff), p.getBackoff())
+}
+
 func (p *Process) getProcessEnvironment() []string {
+	env := buildProcessEnvironment(p.procConf, p.globalEnv, p.runID, p.restartID)
+	return append(env, p.envFromProcess...)
+}
+
+// buildProcessEnvironment returns the environment of the process configuration, without the env_from_process variables
+func buildProcessEnvironment(procConf *types.ProcessConfig, globalEnv []string, runID, restartID string) []string {
 	env := []string{
-		"PC_PROC_NAME=" + p.procConf.Name,
-		EnvReplicaNum + "=" + strconv.Itoa(p.procConf.ReplicaNum),
+		"PC_PROC_NAME=" + procConf.Name,
+		EnvReplicaNum + "=" + strconv.Itoa(procConf.ReplicaNum),
+		EnvRunID + "=" + runID,
+		EnvRestartID + "=" + restartID,
 	}
 	env = append(env, os.Environ()...)
-	env = append(env, p.globalEnv...)
-	env = append(env, p.procConf.Environment...)
+	env = append(env, globalEnv...)
+	env = append(env, procConf.Environment...)
 	return env
 }
 
+// validateRequiredEnv checks the env_required variables are not empty in the process environment
+func (p *Process) validateRequiredEnv() error {
+	if len(p.procConf.EnvRequired) == 0 {
+		return nil
+	}
+	env := map[string]string{}
+	for _, kv := range p.getProcessEnvironment() {
+		key, value, _ := strings.Cut(kv, "=")
+		env[key] = value
+	}
+	var missing []string
+	for _, name := range p.procConf.EnvRequired {
+		if env[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("process %s requires the environment variables: %s", p.getName(), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// newSecretMasker returns a replacer of the secret environment variables values, nil if there are none.
+// Values shorter than minMaskedSecretLen are not masked, to keep the output readable
+func (p *Process) newSecretMasker() *strings.Replacer {
+	if p.isSecretEnvVar == nil {
+		return nil
+	}
+	var secrets []string
+	for _, kv := range p.getProcessEnvironment() {
+		key, value, _ := strings.Cut(kv, "=")
+		if len(value) >= minMaskedSecretLen && p.isSecretEnvVar(key) && !slices.Contains(secrets, value) {
+			secrets = append(secrets, value)
+		}
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	// the longest first, when a secret contains another one
+	slices.SortFunc(secrets, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	oldNew := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		oldNew = append(oldNew, secret, maskedSecret)
+	}
+	return strings.NewReplacer(oldNew...)
+}
+
+func (p *Process) maskSecrets(line string) string {
+	if p.secretMasker == nil {
+		return line
+	}
+	return p.secretMasker.Replace(line)
+}
+
 func (p *Process) isRestartable() bool {
 	p.Lock()
 	exitCode := p.getExitCode()
@@ -278,11 +639,17 @@ func (p *Process) isRestartable() bool {
 		return false
 	}
 
-	if exitCode != 0 && p.procConf.RestartPolicy.Restart == types.RestartPolicyExitOnFailure {
+	if slices.Contains(p.procConf.NoRestartExitCodes, exitCode) {
+		return false
+	}
+
+	failed := !p.procConf.IsSuccessExitCode(exitCode)
+	if failed && p.procConf.RestartPolicy.Restart == types.RestartPolicyExitOnFailure {
 		return false
 	}
 
-	if exitCode != 0 && p.procConf.RestartPolicy.Restart == types.RestartPolicyOnFailure {
+	restartOnCode := slices.Contains(p.procConf.RestartExitCodes, exitCode)
+	if (failed || restartOnCode) && p.procConf.RestartPolicy.Restart == types.RestartPolicyOnFailure {
 		if p.procConf.RestartPolicy.MaxRestarts == 0 {
 			return true
 		}
@@ -300,30 +667,70 @@ func (p *Process) isRestartable() bool {
 	return false
 }
 
-func (p *Process) waitForStarted() {
+// waitForStarted waits until the process has started or the context is canceled
+func (p *Process) waitForStarted(ctx context.Context) {
 	p.Lock()
 	defer p.Unlock()
 
-	for !p.started {
-		p.procStartedCond.Wait()
-	}
+	p.waitCond(ctx, &p.procStartedCond, func() bool {
+		return p.started
+	})
 }
 
-func (p *Process) waitForCompletion() int {
+// waitForStartup waits until the process has started or ended without starting, or the context is canceled
+func (p *Process) waitForStartup(ctx context.Context) {
 	p.Lock()
 	defer p.Unlock()
 
-	for !p.done {
-		p.procCond.Wait()
-	}
+	p.waitCond(ctx, &p.procStartedCond, func() bool {
+		return p.started || p.done
+	})
+}
+
+// waitForCompletion waits until the process is done or the context is canceled
+func (p *Process) waitForCompletion(ctx context.Context) int {
+	p.Lock()
+	defer p.Unlock()
+
+	p.waitCond(ctx, &p.procCond, func() bool {
+		return p.done
+	})
 	return p.getExitCode()
 }
 
-func (p *Process) waitUntilReady() bool {
+// waitForRunEnd waits until a run of the process ends after the given number of ended runs, the process is done,
+// or the context is canceled. It returns the number of ended runs, the last exit code and whether the process is done
+func (p *Process) waitForRunEnd(ctx context.Context, endedRuns int) (int, int, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.waitCond(ctx, &p.procCond, func() bool {
+		return p.done || p.endedRuns > endedRuns
+	})
+	return p.endedRuns, p.getExitCode(), p.done
+}
+
+// waitCond waits on the process condition, with the process locked, until isMet returns true or the context
+// is canceled
+func (p *Process) waitCond(ctx context.Context, cond *sync.Cond, isMet func() bool) {
+	stop := context.AfterFunc(ctx, func() {
+		p.Lock()
+		defer p.Unlock()
+		cond.Broadcast()
+	})
+	defer stop()
+	for !isMet() && ctx.Err() == nil {
+		cond.Wait()
+	}
+}
+
+func (p *Process) waitUntilReady(ctx context.Context) bool {
 	for {
 		select {
+		case <-ctx.Done():
+			return false
 		case <-p.procReadyCtx.Done():
-			if p.procState.Health == types.ProcessHealthReady {
+			if p.getHealth() == types.ProcessHealthReady {
 				return true
 			}
 			log.Error().Msgf("Process %s was aborted and won't become ready", p.getName())
@@ -340,10 +747,34 @@ func (p *Process) waitUntilReady() bool {
 	}
 }
 
-func (p *Process) wontRun() {
+func (p *Process) wontRun(reason error) {
+	p.setEndReason(reason)
 	p.onProcessEnd(types.ProcessStateSkipped)
 }
 
+// setEndReason records why the process won't run or failed to start
+func (p *Process) setEndReason(reason error) {
+	p.Lock()
+	defer p.Unlock()
+	p.endReason = reason.Error()
+}
+
+func (p *Process) getEndReason() string {
+	p.Lock()
+	defer p.Unlock()
+	return p.endReason
+}
+
+// endPending ends the process stopped before it started, once, as both the shutdown and the stopped dependency
+// waiter end it
+func (p *Process) endPending() {
+	p.pendingEndMtx.Lock()
+	defer p.pendingEndMtx.Unlock()
+	if p.isOneOfStates(types.ProcessStatePending) {
+		p.onProcessEnd(types.ProcessStateTerminating)
+	}
+}
+
 // perform graceful process shutdown if defined in configuration
 func (p *Process) shutDownNoRestart() error {
 	p.prepareForShutDown()
@@ -361,13 +792,14 @@ func (p *Process) internalStop() error {
 }
 
 func (p *Process) stopProcess(cancelReadinessFuncs bool) error {
-	p.runCancelFn()
+	if cancelReadinessFuncs {
+		// an internal stop leaves the restarts to the restart policy
+		p.runCancelFn()
+	}
 	if !p.isRunning() {
 		log.Debug().Msgf("process %s is in state %s not shutting down", p.getName(), p.getStatusName())
 		// prevent pending process from running
-		if p.isOneOfStates(types.ProcessStatePending) {
-			p.onProcessEnd(types.ProcessStateTerminating)
-		}
+		p.endPending()
 		return nil
 	}
 	p.setState(types.ProcessStateTerminating)
@@ -395,7 +827,8 @@ func (p *Process) stopProcess(cancelReadinessFuncs bool) error {
 		case errors.Is(err, context.Canceled):
 			return nil
 		case errors.Is(err, context.DeadlineExceeded):
-			return p.command.Stop(int(syscall.SIGKILL), p.procConf.ShutDownParams.ParentOnly)
+			log.Debug().Msgf("%s didn't stop within %d seconds, killing it", p.getName(), p.procConf.ShutDownParams.ShutDownTimeout)
+			return p.command.Stop(p.getKillSignal(), p.procConf.ShutDownParams.ParentOnly)
 		default:
 			log.Error().Err(err).Msgf("terminating %s with timeout %d failed", p.getName(), p.procConf.ShutDownParams.ShutDownTimeout)
 			return err
@@ -420,27 +853,98 @@ func (p *Process) doConfiguredStop(params types.ShutDownParams) error {
 	if err := cmd.Run(); err != nil {
 		// the process termination timedout and it will be killed
 		log.Error().Msgf("terminating %s with timeout %d failed - %s", p.getName(), timeout, err.Error())
-		return p.command.Stop(int(syscall.SIGKILL), false)
+		return p.command.Stop(p.getKillSignal(), false)
 	}
 	return nil
 }
 
+// kill sends SIGKILL to the process and its children, without waiting for the shutdown timeout
+func (p *Process) kill() error {
+	if !p.isOneOfStates(types.ProcessStateRunning, types.ProcessStateLaunched, types.ProcessStateTerminating) {
+		return nil
+	}
+	log.Warn().Msgf("killing %s", p.getName())
+	return p.command.Stop(int(syscall.SIGKILL), false)
+}
+
+// getKillSignal returns the signal used to kill the process once the shutdown timeout expires
+func (p *Process) getKillSignal() int {
+	if p.procConf.ShutDownParams.KillSignal == "" {
+		return int(syscall.SIGKILL)
+	}
+	sig, err := command.ParseSignal(p.procConf.ShutDownParams.KillSignal)
+	if err != nil {
+		log.Err(err).Msgf("invalid kill signal for %s, using SIGKILL", p.getName())
+		return int(syscall.SIGKILL)
+	}
+	return sig
+}
+
+// reload sends the reload signal (SIGHUP by default) to a running process
+func (p *Process) reload() error {
+	if !p.isRunning() {
+		return fmt.Errorf("process %s is not running", p.getName())
+	}
+	sig := int(syscall.SIGHUP)
+	if p.procConf.ReloadSignal != "" {
+		var err error
+		if sig, err = command.ParseSignal(p.procConf.ReloadSignal); err != nil {
+			return fmt.Errorf("invalid reload signal for %s: %w", p.getName(), err)
+		}
+	}
+	log.Info().Str("process", p.getName()).Int("signal", sig).Msg("Reloading")
+	return p.command.Signal(sig, p.procConf.ShutDownParams.ParentOnly)
+}
+
 func (p *Process) isRunning() bool {
 	return p.isOneOfStates(types.ProcessStateRunning, types.ProcessStateLaunched)
 }
 
+// wasStopped reports whether the process was stopped by a user request or a project shutdown
+func (p *Process) wasStopped() bool {
+	return p.isShutDown.Load()
+}
+
 func (p *Process) prepareForShutDown() {
 	// prevent restart during global shutdown or scale down
 	//p.procConf.RestartPolicy.Restart = types.RestartPolicyNo
 	p.isStopped.Store(true)
-
+	p.isShutDown.Store(true)
 }
 
 func (p *Process) onProcessStart() {
 	if isStringDefined(p.procConf.LogLocation) {
 		p.logger.Open(p.getLogPath(), p.procConf.LoggerConfig)
 	}
+	if p.stdoutLogger != nil {
+		p.stdoutLogger.Open(resolveLogPath(p.procConf.StdoutLogLocation, p.procConf), p.procConf.LoggerConfig)
+	}
+	if p.stderrLogger != nil {
+		p.stderrLogger.Open(resolveLogPath(p.procConf.StderrLogLocation, p.procConf), p.procConf.LoggerConfig)
+	}
+
+	p.setStarted()
+}
+
+// writeLogSeparator delimits the runs of a process in its appended log files
+func (p *Process) writeLogSeparator() {
+	if !p.procConf.IsLogAppend() {
+		return
+	}
+	separator := fmt.Sprintf("----- %s started at %s, restarts: %d -----",
+		p.getName(), time.Now().Format(time.RFC3339), p.procState.Restarts)
+	if isStringDefined(p.procConf.LogLocation) {
+		p.logger.Info(separator, p.getLogMetadata())
+	}
+	if p.stdoutLogger != nil {
+		p.stdoutLogger.Info(separator, p.getLogMetadata())
+	}
+	if p.stderrLogger != nil {
+		p.stderrLogger.Info(separator, p.getLogMetadata())
+	}
+}
 
+func (p *Process) setStarted() {
 	p.Lock()
 	p.started = true
 	p.Unlock()
@@ -451,6 +955,12 @@ func (p *Process) onProcessEnd(state string) {
 	if isStringDefined(p.procConf.LogLocation) {
 		p.logger.Close()
 	}
+	if p.stdoutLogger != nil {
+		p.stdoutLogger.Close()
+	}
+	if p.stderrLogger != nil {
+		p.stderrLogger.Close()
+	}
 	p.mtxStopFn.Lock()
 	if p.waitForStoppedFn != nil {
 		p.waitForStoppedFn()
@@ -461,23 +971,36 @@ func (p *Process) onProcessEnd(state string) {
 	if p.readyProber != nil {
 		p.readyCancelFn()
 	}
+	p.closeOutputPipes()
+	p.setEndTime(time.Now())
 	p.setState(state)
 	p.updateProcState()
+	if p.endFn != nil {
+		p.endFn(p)
+	}
 
 	p.Lock()
 	p.done = true
 	p.Unlock()
 	p.procCond.Broadcast()
+	p.procStartedCond.Broadcast()
 }
 
 func (p *Process) getLogPath() string {
-	logLocation := p.procConf.LogLocation
+	return getLogPath(p.procConf)
+}
+
+func getLogPath(procConf *types.ProcessConfig) string {
+	return resolveLogPath(procConf.LogLocation, procConf)
+}
 
+// resolveLogPath makes the log location unique per replica
+func resolveLogPath(logLocation string, procConf *types.ProcessConfig) string {
 	if strings.Contains(logLocation, LogReplicaNum) {
-		replicaStr := strconv.Itoa(p.procConf.ReplicaNum)
+		replicaStr := strconv.Itoa(procConf.ReplicaNum)
 		logLocation = strings.Replace(logLocation, LogReplicaNum, replicaStr, -1)
-	} else if p.procConf.Replicas > 1 {
-		logLocation = fmt.Sprintf("%s.%d", logLocation, p.procConf.ReplicaNum)
+	} else if procConf.Replicas > 1 {
+		logLocation = fmt.Sprintf("%s.%d", logLocation, procConf.ReplicaNum)
 	}
 
 	return logLocation
@@ -517,6 +1040,7 @@ func (p *Process) updateProcState() {
 		p.procState.Mem = p.getMemUsage()
 	}
 	p.procState.IsRunning = isRunning
+	p.procState.OutputRate = p.outputRate.getRate()
 	p.procState.IsElevated = p.procConf.IsElevated
 	p.procState.PasswordProvided = p.passProvided
 
@@ -534,6 +1058,18 @@ func (p *Process) getStartTime() time.Time {
 	return p.startTime
 }
 
+func (p *Process) setEndTime(endTime time.Time) {
+	p.timeMutex.Lock()
+	defer p.timeMutex.Unlock()
+	p.endTime = endTime
+}
+
+func (p *Process) getEndTime() time.Time {
+	p.timeMutex.Lock()
+	defer p.timeMutex.Unlock()
+	return p.endTime
+}
+
 func (p *Process) getMemUsage() int64 {
 	if p.procConf.IsDaemon {
 		return 0
@@ -568,10 +1104,14 @@ func (p *Process) handleInput(pipe io.WriteCloser) {
 	}
 }
 
-func (p *Process) handleOutput(pipe io.ReadCloser, output string, handler func(message string), done chan struct{}) {
-	reader := bufio.NewReader(pipe)
+// handleOutput reads the process output, passing its lines to the handler and their raw content to forward
+func (p *Process) handleOutput(pipe io.ReadCloser, output string, handler, forward func(message string), done chan struct{}) {
+	read := newLineReader(pipe)
+	if p.procConf.OutputBuffering == types.OutputBufferingBlock {
+		read = newBlockReader(pipe)
+	}
 	for {
-		line, err := reader.ReadString('\n')
+		block, err := read()
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -586,45 +1126,149 @@ func (p *Process) handleOutput(pipe io.ReadCloser, output string, handler func(m
 				Msgf("error reading from %s", output)
 			break
 		}
-		if p.procConf.ReadyLogLine != "" && p.procState.Health == types.ProcessHealthUnknown && strings.Contains(line, p.procConf.ReadyLogLine) {
-			p.procState.Health = types.ProcessHealthReady
-			p.readyLogCancelFn(nil)
-		}
-		if p.procConf.IsElevated &&
-			!p.passProvided &&
-			p.waitForPassCancelFn != nil {
-			if isWrongPasswordEntered(line) {
-				log.Warn().
-					Str("process", p.getName()).
-					Msgf("Password rejected %s", line)
-			} else {
-				log.Info().
-					Str("process", p.getName()).
-					Msg("Password accepted")
-				p.passProvided = true
+		for _, line := range strings.SplitAfter(block, "\n") {
+			if line != "" {
+				p.handleOutputLine(line, handler, forward)
 			}
-			p.waitForPassCancelFn()
-			p.waitForPassCancelFn = nil
 		}
-		handler(strings.TrimSuffix(line, "\n"))
 	}
 	close(done)
 }
 
+// handleOutputLine checks a single line of the process output for readiness and password prompts and passes it to the handler
+func (p *Process) handleOutputLine(line string, handler, forward func(message string)) {
+	if p.procConf.ReadyLogLine != "" && strings.Contains(line, p.procConf.ReadyLogLine) &&
+		p.swapHealth(types.ProcessHealthUnknown, types.ProcessHealthReady) {
+		p.readyLogCancelFn(nil)
+	}
+	if p.procConf.IsElevated &&
+		!p.passProvided &&
+		p.waitForPassCancelFn != nil {
+		if isWrongPasswordEntered(line) {
+			log.Warn().
+				Str("process", p.getName()).
+				Msgf("Password rejected %s", line)
+		} else {
+			log.Info().
+				Str("process", p.getName()).
+				Msg("Password accepted")
+			p.passProvided = true
+		}
+		p.waitForPassCancelFn()
+		p.waitForPassCancelFn = nil
+	}
+	if forward != nil {
+		forward(line)
+	}
+	if !p.outputRate.add() {
+		return
+	}
+	handler(p.maskSecrets(strings.TrimSuffix(line, "\n")))
+}
+
+func (p *Process) getLogMetadata() pclog.LogMetadata {
+	return pclog.LogMetadata{
+		Process:   p.getName(),
+		Replica:   p.procConf.ReplicaNum,
+		Replicas:  p.procConf.Replicas,
+		RestartID: p.restartID,
+	}
+}
+
+// newLineReader returns a reader of the pipe output, one line at a time
+func newLineReader(pipe io.Reader) func() (string, error) {
+	reader := bufio.NewReader(pipe)
+	return func() (string, error) {
+		return reader.ReadString('\n')
+	}
+}
+
+// newBlockReader returns a reader of the pipe output in blocks of complete lines, as they are read from the OS.
+// The incomplete remainder is returned when the pipe is closed
+func newBlockReader(pipe io.Reader) func() (string, error) {
+	buf := make([]byte, outputBlockSize)
+	var pending []byte
+	return func() (string, error) {
+		for {
+			n, err := pipe.Read(buf)
+			pending = append(pending, buf[:n]...)
+			if err != nil {
+				if len(pending) == 0 {
+					return "", err
+				}
+				block := string(pending)
+				pending = nil
+				return block, nil
+			}
+			idx := bytes.LastIndexByte(pending, '\n')
+			if idx < 0 && len(pending) < outputBlockSize {
+				continue
+			}
+			if idx < 0 {
+				idx = len(pending) - 1
+			}
+			block := string(pending[:idx+1])
+			pending = append([]byte{}, pending[idx+1:]...)
+			return block, nil
+		}
+	}
+}
+
 func (p *Process) handleInfo(message string) {
-	p.logger.Info(message, p.getName(), p.procConf.ReplicaNum)
+	p.logger.Info(message, p.getLogMetadata())
+	if p.stdoutLogger != nil {
+		p.stdoutLogger.Info(message, p.getLogMetadata())
+	}
 	if p.printLogs {
-		fmt.Printf("[%s\t] %s\n", p.procColor(p.getName()), message)
+		if p.lineTemplate != nil {
+			fmt.Println(p.lineColor(p.lineTemplate.Render(message, p.getLogMetadata(), false, time.Now())))
+		} else {
+			fmt.Printf("[%s\t] %s\n", p.procColor(p.getName()), p.lineColor(message))
+		}
 	}
 	p.logBuffer.Write(message)
+	if p.webhook != nil {
+		p.webhook.send(p.getName(), "stdout", message)
+	}
+	if p.otlpExporter != nil {
+		p.otlpExporter.send("stdout", message)
+	}
+	if p.fifo != nil {
+		p.fifo.send(message)
+	}
+	if strings.TrimSpace(message) != "" {
+		p.lastOutputMtx.Lock()
+		p.lastOutputLine = message
+		p.lastOutputMtx.Unlock()
+	}
+}
+
+// getLastOutputLine returns the last non-empty line the process wrote to stdout
+func (p *Process) getLastOutputLine() string {
+	p.lastOutputMtx.Lock()
+	defer p.lastOutputMtx.Unlock()
+	return p.lastOutputLine
 }
 
 func (p *Process) handleError(message string) {
-	p.logger.Error(message, p.getName(), p.procConf.ReplicaNum)
+	p.logger.Error(message, p.getLogMetadata())
+	if p.stderrLogger != nil {
+		p.stderrLogger.Error(message, p.getLogMetadata())
+	}
 	if p.printLogs {
-		fmt.Printf("[%s\t] %s\n", p.procColor(p.getName()), p.redColor(message))
+		if p.lineTemplate != nil {
+			fmt.Println(p.redColor(p.lineTemplate.Render(message, p.getLogMetadata(), true, time.Now())))
+		} else {
+			fmt.Printf("[%s\t] %s\n", p.procColor(p.getName()), p.redColor(message))
+		}
 	}
 	p.logBuffer.Write(message)
+	if p.webhook != nil {
+		p.webhook.send(p.getName(), "stderr", message)
+	}
+	if p.otlpExporter != nil {
+		p.otlpExporter.send("stderr", message)
+	}
 }
 
 func (p *Process) isState(state string) bool {
@@ -647,6 +1291,9 @@ func (p *Process) isOneOfStates(states ...string) bool {
 func (p *Process) setState(state string) {
 	p.stateMtx.Lock()
 	defer p.stateMtx.Unlock()
+	if !checkStateTransition(p.getName(), p.procState.Status, state) {
+		return
+	}
 	p.procState.Status = state
 	p.onStateChange(state)
 }
@@ -658,6 +1305,18 @@ func (p *Process) getState() *types.ProcessState {
 	return p.procState
 }
 
+func (p *Process) getRestarts() int {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	return p.procState.Restarts
+}
+
+func (p *Process) setRestarts(restarts int) {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	p.procState.Restarts = restarts
+}
+
 func (p *Process) getStatusName() string {
 	p.updateProcState()
 	p.stateMtx.Lock()
@@ -668,12 +1327,18 @@ func (p *Process) getStatusName() string {
 func (p *Process) setStateAndRun(state string, runnable func() error) error {
 	p.stateMtx.Lock()
 	defer p.stateMtx.Unlock()
+	if !checkStateTransition(p.getName(), p.procState.Status, state) {
+		return fmt.Errorf("process %s can't be started in state %s", p.getName(), p.procState.Status)
+	}
 	p.procState.Status = state
 	p.onStateChange(state)
 	return runnable()
 }
 
 func (p *Process) onStateChange(state string) {
+	if p.stateChangeFn != nil {
+		p.stateChangeFn()
+	}
 	switch state {
 	case types.ProcessStateSkipped:
 		p.setExitCode(1)
@@ -744,26 +1409,65 @@ func (p *Process) onLivenessCheckEnd(_, isFatal bool, err string) {
 	if isFatal {
 		log.Info().Msgf("%s is not alive anymore - %s", p.getName(), err)
 		p.logBuffer.Write("Error: liveness check fail - " + err)
-		p.notifyDaemonStopped()
+		if p.procConf.IsDaemon {
+			p.notifyDaemonStopped()
+			return
+		}
+		// stopped as if it crashed, the restart policy decides if it's restarted
+		_ = p.internalStop()
 	}
 }
 
 func (p *Process) onReadinessCheckEnd(isOk, isFatal bool, err string) {
 	if isFatal {
-		p.procState.Health = types.ProcessHealthNotReady
+		p.setHealth(types.ProcessHealthNotReady)
 		log.Info().Msgf("%s is not ready anymore - %s", p.getName(), err)
 		p.logBuffer.Write("Error: readiness check fail - " + err)
 		_ = p.internalStop()
 	} else if isOk {
-		p.procState.Health = types.ProcessHealthReady
+		if p.setHealth(types.ProcessHealthReady) != types.ProcessHealthReady {
+			log.Info().Msgf("%s is ready", p.getName())
+		}
 		p.readyCancelFn()
 	} else {
-		p.procState.Health = types.ProcessHealthNotReady
+		log.Debug().Msgf("%s readiness check failed - %s", p.getName(), err)
+		p.setHealth(types.ProcessHealthNotReady)
+	}
+}
+
+func (p *Process) getHealth() string {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	return p.procState.Health
+}
+
+// setHealth sets the process health and returns the previous one
+func (p *Process) setHealth(health string) string {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	previous := p.procState.Health
+	p.procState.Health = health
+	return previous
+}
+
+// swapHealth sets the process health to the new one only if it is the old one
+func (p *Process) swapHealth(old, health string) bool {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	if p.procState.Health != old {
+		return false
 	}
+	p.procState.Health = health
+	return true
 }
 
 func (p *Process) validateProcess() error {
 	if isStringDefined(p.procConf.WorkingDir) {
+		if p.procConf.CreateWorkingDir {
+			if err := os.MkdirAll(p.procConf.WorkingDir, 0755); err != nil {
+				return err
+			}
+		}
 		stat, err := os.Stat(p.procConf.WorkingDir)
 		if err != nil {
 			return err
@@ -792,6 +1496,18 @@ func (p *Process) getOpenPorts(ports *types.ProcessPorts) error {
 	return nil
 }
 
+// updateInfo applies the informational fields of procConf to the process, under stateMtx as its state
+func (p *Process) updateInfo(procConf *types.ProcessConfig) {
+	p.stateMtx.Lock()
+	defer p.stateMtx.Unlock()
+	p.procConf.Description = procConf.Description
+	p.procConf.Owner = procConf.Owner
+	p.procConf.RunbookURL = procConf.RunbookURL
+	p.procConf.Labels = procConf.Labels
+	p.procConf.MaxOutputLines = procConf.MaxOutputLines
+	p.procState.Description = procConf.Description
+}
+
 func (p *Process) getExitCode() int {
 	defer p.confMtx.Unlock()
 	p.confMtx.Lock()
@@ -823,6 +1539,18 @@ func (p *Process) setPassword(password string) error {
 	return nil
 }
 
+// writeStdin sends an input line to a process that keeps its stdin open
+func (p *Process) writeStdin(input string) error {
+	if !p.procConf.KeepStdinOpen || p.stdin == nil {
+		return fmt.Errorf("process %s doesn't accept input", p.getName())
+	}
+	_, err := p.stdin.Write([]byte(input + "\n"))
+	if err != nil {
+		log.Error().Err(err).Msgf(`Failed to write to stdin pipe for process %s`, p.getName())
+	}
+	return err
+}
+
 func (p *Process) waitForPass() error {
 	select {
 	case <-p.waitForPassCtx.Done():
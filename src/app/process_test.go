@@ -1,6 +1,19 @@
 package app
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -72,3 +85,406 @@ func TestDurationToString(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("line1\nline2\npart"))
+		_, _ = pw.Write([]byte("ial\nno newline"))
+		_ = pw.Close()
+	}()
+	read := newBlockReader(pr)
+	var blocks []string
+	for {
+		block, err := read()
+		if err != nil {
+			if err != io.EOF {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	want := []string{"line1\nline2\n", "partial\n", "no newline"}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("newBlockReader() = %q, want %q", blocks, want)
+	}
+}
+
+func TestGetRestartBackoff(t *testing.T) {
+	tests := []struct {
+		name      string
+		minUptime time.Duration
+		restarts  int
+		want      time.Duration
+	}{
+		{name: "no min uptime", restarts: 5, want: 2 * time.Second},
+		{name: "first restart", minUptime: time.Minute, restarts: 1, want: 2 * time.Second},
+		{name: "third restart", minUptime: time.Minute, restarts: 3, want: 8 * time.Second},
+		{name: "capped", minUptime: time.Minute, restarts: 20, want: maxRestartBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcess(
+				withProcConf(&types.ProcessConfig{
+					MinUptime: tt.minUptime,
+					RestartPolicy: types.RestartPolicyConfig{
+						BackoffSeconds: 2,
+					},
+				}),
+				withProcState(&types.ProcessState{Restarts: tt.restarts}),
+			)
+			if got := p.getRestartBackoff(); got != tt.want {
+				t.Errorf("getRestartBackoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputRateMeter(t *testing.T) {
+	m := newOutputRateMeter(5)
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		if m.add() {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("allowed %d lines, want 5", allowed)
+	}
+	if dropped := m.tick(time.Second); dropped != 15 {
+		t.Errorf("tick() dropped = %d, want 15", dropped)
+	}
+	// a single tick moves the average by 1-e^(-1/10) of the current rate
+	want := 20 * (1 - math.Exp(-0.1))
+	if got := m.getRate(); math.Abs(got-want) > 0.001 {
+		t.Errorf("getRate() = %f, want %f", got, want)
+	}
+	if !m.add() {
+		t.Errorf("add() after tick should be allowed")
+	}
+	m.reset()
+	if got := m.getRate(); got != 0 {
+		t.Errorf("getRate() after reset = %f, want 0", got)
+	}
+}
+
+func TestWaitForRestartRate(t *testing.T) {
+	state := types.ProcessState{}
+	proc := NewProcess(
+		withProcConf(&types.ProcessConfig{Name: "crasher", ReplicaName: "crasher", MaxRestartsPerMinute: 2}),
+		withProcState(&state),
+	)
+	now := time.Now()
+	proc.restartTimes = []time.Time{now.Add(-2 * time.Minute), now.Add(-30 * time.Second)}
+	if !proc.waitForRestartRate() {
+		t.Fatalf("waitForRestartRate() should allow the restart below the limit")
+	}
+	if len(proc.restartTimes) != 2 {
+		t.Errorf("expected the restarts outside the window to be pruned, got %v", proc.restartTimes)
+	}
+	proc.runCancelFn()
+	if proc.waitForRestartRate() {
+		t.Errorf("waitForRestartRate() should hold the restart above the limit until stopped")
+	}
+	if state.Status != types.ProcessStateRateLimited {
+		t.Errorf("status = %s, want %s", state.Status, types.ProcessStateRateLimited)
+	}
+}
+
+func TestOutputWebhook(t *testing.T) {
+	received := make(chan webhookLine, 10)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			// the first delivery attempt fails and should be retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var line webhookLine
+		if err := json.NewDecoder(r.Body).Decode(&line); err != nil {
+			t.Errorf("failed to decode the webhook body: %v", err)
+		}
+		received <- line
+	}))
+	defer server.Close()
+
+	webhook := newOutputWebhook(server.URL)
+	go webhook.run()
+	webhook.send("api", "stderr", "failed to connect")
+	webhook.close()
+	webhook.send("api", "stdout", "dropped after close")
+
+	select {
+	case line := <-received:
+		if line.Process != "api" || line.Stream != "stderr" || line.Line != "failed to connect" || line.Ts == "" {
+			t.Errorf("unexpected webhook line %+v", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the webhook wasn't delivered")
+	}
+}
+
+func TestOtlpLogExporter(t *testing.T) {
+	received := make(chan otlpLogsRequest, 10)
+	auth := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != otlpLogsPath {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req otlpLogsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode the OTLP request: %v", err)
+		}
+		auth <- r.Header.Get("Authorization")
+		received <- req
+	}))
+	defer server.Close()
+
+	t.Setenv(envOtlpLogsEndpoint, "")
+	t.Setenv(envOtlpEndpoint, server.URL+"/")
+	t.Setenv(envOtlpHeaders, "Authorization=Bearer%20token,x-team=dev")
+	t.Setenv(envOtelServiceName, "")
+	t.Setenv(envOtlpProtocol, "")
+	t.Setenv(envOtlpTimeout, "2500")
+	conf := newOtlpLogsConfig()
+	if conf == nil {
+		t.Fatal("expected an OTLP logs config")
+	}
+	if conf.timeout != 2500*time.Millisecond {
+		t.Errorf("expected a 2.5s timeout, got %v", conf.timeout)
+	}
+	exporter := newOtlpLogExporter(conf, "api", 2, "run-1")
+	go exporter.run()
+	exporter.send("stdout", "listening on 8080")
+	exporter.send("stderr", "")
+	exporter.close()
+	exporter.send("stdout", "dropped after close")
+
+	if got := <-auth; got != "Bearer token" {
+		t.Errorf("expected the Authorization header to be sent, got %q", got)
+	}
+	req := <-received
+	if len(req.ResourceLogs) != 1 || len(req.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("unexpected OTLP request %+v", req)
+	}
+	attrs := map[string]otlpAnyValue{}
+	for _, attr := range req.ResourceLogs[0].Resource.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	if *attrs[otlpAttrServiceName].StringValue != otlpDefaultService ||
+		*attrs[otlpAttrProcess].StringValue != "api" ||
+		*attrs[otlpAttrReplicaIndex].IntValue != "2" ||
+		*attrs[otlpAttrRunID].StringValue != "run-1" {
+		t.Errorf("unexpected resource attributes %+v", attrs)
+	}
+	records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d", len(records))
+	}
+	if *records[0].Body.StringValue != "listening on 8080" || records[0].SeverityNumber != otlpSeverityInfo {
+		t.Errorf("unexpected stdout record %+v", records[0])
+	}
+	if records[1].Body.StringValue == nil || *records[1].Body.StringValue != "" || records[1].SeverityText != "ERROR" {
+		t.Errorf("unexpected stderr record %+v", records[1])
+	}
+
+	t.Setenv(envOtlpEndpoint, "")
+	if newOtlpLogsConfig() != nil {
+		t.Errorf("expected no config without an endpoint")
+	}
+	t.Setenv(envOtlpLogsEndpoint, server.URL+otlpLogsPath)
+	t.Setenv(envOtlpTimeout, "soon")
+	if conf = newOtlpLogsConfig(); conf == nil || conf.timeout != otlpDefaultTimeout {
+		t.Errorf("expected the default timeout for an invalid timeout, got %+v", conf)
+	}
+	t.Setenv(envOtlpLogsEndpoint, server.URL+otlpLogsPath)
+	t.Setenv(envOtlpProtocol, otlpGrpcProtocol)
+	if newOtlpLogsConfig() != nil {
+		t.Errorf("expected no config with the grpc protocol")
+	}
+}
+
+func TestCleanupPidDir(t *testing.T) {
+	dir := t.TempDir()
+	orphan := exec.Command("sleep", "30")
+	if err := orphan.Start(); err != nil {
+		t.Skipf("failed to start sleep: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = orphan.Wait()
+		close(exited)
+	}()
+	writePidFile(dir, "ns/orphan", orphan.Process.Pid)
+	// a reused PID has a different creation time and must be left alone
+	reused := getPidFilePath(dir, "reused")
+	if err := os.WriteFile(reused, []byte(fmt.Sprintf("%d 1\n", os.Getpid())), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cleanupPidDir(dir)
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = orphan.Process.Kill()
+		t.Fatalf("the orphan process wasn't terminated")
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("expected the PID files to be removed, got %v", files)
+	}
+}
+
+func TestStopPidDir(t *testing.T) {
+	dir := t.TempDir()
+	proc := exec.Command("sh", "-c", "trap '' TERM; while :; do sleep 0.1; done")
+	if err := proc.Start(); err != nil {
+		t.Skipf("failed to start sh: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = proc.Wait()
+		close(exited)
+	}()
+	writePidFile(dir, "stubborn", proc.Process.Pid)
+
+	stopped, err := StopPidDir(dir, 500*time.Millisecond)
+	if err != nil || stopped != 1 {
+		t.Fatalf("expected 1 stopped process, got %d, %v", stopped, err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = proc.Process.Kill()
+		t.Fatalf("the process ignoring SIGTERM wasn't killed")
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("expected the PID files to be removed, got %v", files)
+	}
+}
+
+func TestCollectCoreDump(t *testing.T) {
+	workDir := t.TempDir()
+	dumpDir := filepath.Join(t.TempDir(), "cores")
+	now := time.Now()
+	if path, err := collectCoreDump("ns/proc", 42, workDir, dumpDir, now); path != "" || err != nil {
+		t.Fatalf("expected no core file, got %s, %v", path, err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "core.42"), []byte("core"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path, err := collectCoreDump("ns/proc", 42, workDir, dumpDir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dumpDir, fmt.Sprintf("ns_proc-42-%d.core", now.Unix()))
+	if path != want {
+		t.Errorf("expected core file %s, got %s", want, path)
+	}
+	if _, err = os.Stat(want); err != nil {
+		t.Errorf("expected the core file to be moved: %v", err)
+	}
+}
+
+func TestIsRestartableExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		exitCode int
+		want     bool
+	}{
+		{"on_failure restart code", types.RestartPolicyOnFailure, 0, true},
+		{"on_failure success", types.RestartPolicyOnFailure, 1, false},
+		{"on_failure failure", types.RestartPolicyOnFailure, 2, true},
+		{"always no restart code", types.RestartPolicyAlways, 3, false},
+		{"always", types.RestartPolicyAlways, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := types.ProcessState{ExitCode: tt.exitCode}
+			proc := NewProcess(
+				withProcConf(&types.ProcessConfig{
+					Name:               "proc",
+					ReplicaName:        "proc",
+					RestartPolicy:      types.RestartPolicyConfig{Restart: tt.policy},
+					SuccessExitCodes:   []int{0, 1},
+					RestartExitCodes:   []int{0},
+					NoRestartExitCodes: []int{3},
+				}),
+				withProcState(&state),
+			)
+			if got := proc.isRestartable(); got != tt.want {
+				t.Errorf("isRestartable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputFIFO(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), "output.fifo")
+	fifo, err := newOutputFIFO(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go fifo.run()
+	defer fifo.close()
+	if info, err := os.Stat(path); err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		t.Fatalf("expected %s to be a named pipe: %v", path, err)
+	}
+	reader, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	fifo.send("line 1")
+	fifo.send("line 2")
+	_ = reader.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(reader)
+	for _, want := range []string{"line 1", "line 2"} {
+		if !scanner.Scan() {
+			t.Fatalf("failed to read %q from the FIFO: %v", want, scanner.Err())
+		}
+		if got := scanner.Text(); got != want {
+			t.Errorf("read %q, want %q", got, want)
+		}
+	}
+
+	if _, err = newOutputFIFO(filepath.Join(t.TempDir())); err == nil {
+		t.Errorf("expected an error for an existing path that is not a named pipe")
+	}
+}
+
+func TestIsValidStateTransition(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{types.ProcessStatePending, types.ProcessStateRunning, true},
+		{types.ProcessStatePending, types.ProcessStateSkipped, true},
+		{types.ProcessStateRunning, types.ProcessStateCompleted, true},
+		{types.ProcessStateRunning, types.ProcessStateRunning, true},
+		{types.ProcessStateRestarting, types.ProcessStateRunning, true},
+		{types.ProcessStateCompleted, types.ProcessStateRunning, true},
+		{types.ProcessStateCompleted, types.ProcessStateTerminating, false},
+		{types.ProcessStateCompleted, types.ProcessStateRestarting, false},
+		{types.ProcessStatePending, types.ProcessStateCompleted, false},
+		{types.ProcessStateLaunched, types.ProcessStateRunning, false},
+		{types.ProcessStateSkipped, types.ProcessStateLaunched, true},
+	}
+	for _, tt := range tests {
+		if got := isValidStateTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("isValidStateTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPaletteColor(t *testing.T) {
+	if paletteColor("web") != paletteColor("web") {
+		t.Errorf("paletteColor() should be stable for the same process name")
+	}
+}
@@ -3,11 +3,13 @@ package app
 import (
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
+	"time"
 )
 
 // IProject holds all the functions from the project struct that are being consumed by the tui package
 type IProject interface {
 	ShutDownProject() error
+	ShutDownProjectWithTimeout(timeout time.Duration) error
 	IsRemote() bool
 	ErrorForSecs() int
 	GetHostName() (string, error)
@@ -17,17 +19,23 @@ type IProject interface {
 	GetLogsAndSubscribe(name string, observer pclog.LogObserver) error
 	UnSubscribeLogger(name string, observer pclog.LogObserver) error
 	GetProcessLog(name string, offsetFromEnd, limit int) ([]string, error)
+	SearchProcessLog(name string, query *types.LogSearchQuery) ([]string, error)
 
 	GetLexicographicProcessNames() ([]string, error)
 	GetProcessInfo(name string) (*types.ProcessConfig, error)
 	GetProcessState(name string) (*types.ProcessState, error)
 	GetProcessesState() (*types.ProcessesState, error)
-	StopProcess(name string) error
+	StopProcess(name string, timeout time.Duration) error
 	StopProcesses(names []string) (map[string]string, error)
 	StartProcess(name string) error
 	RestartProcess(name string) error
 	ScaleProcess(name string, scale int) error
 	GetProcessPorts(name string) (*types.ProcessPorts, error)
+	ReloadProcess(name string) error
+	ExplainProcess(name string) (string, error)
 	SetProcessPassword(name string, password string) error
+	WriteProcessStdin(name string, input string) error
 	UpdateProject(project *types.Project) (map[string]string, error)
+	UpdateProcessConfig(name string, procConf *types.ProcessConfig) error
+	Audit(client, command string, names ...string)
 }
@@ -10,6 +10,8 @@ type ProjectOpts struct {
 	mainProcessArgs   []string
 	isTuiOn           bool
 	isOrderedShutDown bool
+	isWatchMode       bool
+	isIsolated        bool
 }
 
 func (p *ProjectOpts) WithProject(project *types.Project) *ProjectOpts {
@@ -45,3 +47,13 @@ func (p *ProjectOpts) WithOrderedShutDown(isOrderedShutDown bool) *ProjectOpts {
 	p.isOrderedShutDown = isOrderedShutDown
 	return p
 }
+
+func (p *ProjectOpts) WithWatchMode(isWatchMode bool) *ProjectOpts {
+	p.isWatchMode = isWatchMode
+	return p
+}
+
+func (p *ProjectOpts) WithIsolation(isIsolated bool) *ProjectOpts {
+	p.isIsolated = isIsolated
+	return p
+}
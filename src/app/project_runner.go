@@ -5,18 +5,28 @@ import (
 	"errors"
 	"fmt"
 	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/health"
 	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"os"
 	"os/user"
+	"regexp"
 	"runtime"
 	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	waitProgressInterval = 30 * time.Second
+	stopPollInterval     = 10 * time.Millisecond
+)
+
 type ExitError struct {
 	Code int
 }
@@ -32,37 +42,75 @@ type ProjectRunner struct {
 	processLogs       map[string]*pclog.ProcessLogBuffer
 	statesMutex       sync.Mutex
 	processStates     map[string]*types.ProcessState
+	endReasons        map[string]string
+	lastOutputs       map[string]string
+	validationErrors  map[string]error
 	runProcMutex      sync.Mutex
 	runningProcesses  map[string]*Process
+	endedProcesses    map[string]*Process
+	inputPipes        map[string]*inputPipe
+	outputPipes       map[string][]*inputPipe
 	logger            pclog.PcLogger
+	auditMtx          sync.Mutex
+	auditLog          pclog.PcLogger
 	waitGroup         sync.WaitGroup
 	exitCode          int
+	failedExitCodes   map[*Process]int
+	supersededRuns    map[*Process]bool
+	isExitCodeSet     bool
+	exitCodeMtx       sync.Mutex
 	projectState      *types.ProjectState
 	mainProcess       string
 	mainProcessArgs   []string
 	isTuiOn           bool
 	isOrderedShutDown bool
+	isWatchMode       bool
+	isIsolated        bool
 	ctxApp            context.Context
 	cancelAppFn       context.CancelFunc
+	runID             string
+	lastStateChange   atomic.Int64
+	shutdownMtx       sync.Mutex
+	shutdownProcs     []*Process
+	isKilled          bool
+	startQueue        *startQueue
+	otlpLogs          *otlpLogsConfig
+	// ctxWait stops the dependency and startup waiters once the shutdown starts
+	ctxWait      context.Context
+	cancelWaitFn context.CancelFunc
+	// dependencyRestarts are the restarts of the failed dependencies, shared by their dependents
+	restartsMtx        sync.Mutex
+	dependencyRestarts map[*Process]*dependencyRestart
 }
 
 func (p *ProjectRunner) GetLexicographicProcessNames() ([]string, error) {
 	return p.project.GetLexicographicProcessNames()
 }
 
-func (p *ProjectRunner) WithProcesses(names []string, fn func(process types.ProcessConfig) error) error {
-	return p.project.WithProcesses(names, fn)
+func (p *ProjectRunner) WithProcesses(ctx context.Context, names []string, fn func(process types.ProcessConfig) error) error {
+	return p.project.WithProcesses(ctx, names, fn)
+}
+
+// WithProcessesParallel runs fn on the processes of each dependency layer concurrently, see Project.WithProcessesParallel
+func (p *ProjectRunner) WithProcessesParallel(ctx context.Context, names []string, fn func(process types.ProcessConfig) error) error {
+	return p.project.WithProcessesParallel(ctx, names, fn)
 }
 
 func (p *ProjectRunner) init() {
 	p.initProcessStates()
 	p.initProcessLogs()
+	p.failedExitCodes = make(map[*Process]int)
+	p.supersededRuns = make(map[*Process]bool)
+	p.dependencyRestarts = make(map[*Process]*dependencyRestart)
 }
 
-func (p *ProjectRunner) Run() error {
+// Run starts the project processes and blocks until all of them are done.
+// Cancelling ctx shuts down the project
+func (p *ProjectRunner) Run(ctx context.Context) error {
 	p.runningProcesses = make(map[string]*Process)
+	p.endedProcesses = make(map[string]*Process)
 	runOrder := []types.ProcessConfig{}
-	err := p.project.WithProcesses([]string{}, func(process types.ProcessConfig) error {
+	err := p.project.WithProcesses(ctx, []string{}, func(process types.ProcessConfig) error {
 		runOrder = append(runOrder, process)
 		return nil
 	})
@@ -73,30 +121,156 @@ func (p *ProjectRunner) Run() error {
 	for _, v := range runOrder {
 		nameOrder = append(nameOrder, v.ReplicaName)
 	}
+	p.runID = p.project.RunID
+	if p.runID == "" {
+		p.runID = pclog.GenerateUUID()
+	}
 	p.logger = pclog.NewNilLogger()
 	if isStringDefined(p.project.LogLocation) {
-		p.logger = pclog.NewLogger()
+		p.logger = pclog.NewAggregateLogger(p.runID).WithTimestampFormat(p.project.LogTimestampFormat)
 		p.logger.Open(p.project.LogLocation, p.project.LoggerConfig)
 		defer p.logger.Close()
 	}
+	p.setAuditLog(p.logger)
+	defer p.setAuditLog(nil)
 	//zerolog.SetGlobalLevel(zerolog.PanicLevel)
-	log.Debug().Msgf("Spinning up %d processes. Order: %q", len(runOrder), nameOrder)
+	if p.project.CleanupPIDDir != "" {
+		cleanupPidDir(p.project.CleanupPIDDir)
+	}
+	if err = p.runBootstrapCommand(ctx); err != nil {
+		return err
+	}
+	if err = p.checkEnvSchemas(runOrder); err != nil {
+		return err
+	}
+	if err = p.runValidateCommands(ctx, runOrder); err != nil {
+		return err
+	}
+	if err = p.createInputPipes(runOrder); err != nil {
+		return err
+	}
+	if p.project.StateDir != "" {
+		log.Info().Msgf("Project state directory: %s", p.project.StateDir)
+	}
+	if p.otlpLogs = newOtlpLogsConfig(); p.otlpLogs != nil {
+		log.Info().Msgf("Exporting the process output as OTLP logs to %s", p.otlpLogs.endpoint)
+	}
+	log.Debug().Str("run_id", p.runID).Msgf("Spinning up %d processes. Order: %q", len(runOrder), nameOrder)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p.forwardSignals(runCtx)
+	go func() {
+		<-runCtx.Done()
+		if ctx.Err() != nil {
+			log.Info().Msg("Project context cancelled - shutting down the running processes...")
+			_ = p.ShutDownProject()
+		}
+	}()
+	startTime := time.Now()
+	p.onStateChange()
+	if p.project.DeadlockTimeout > 0 {
+		go p.watchDeadlock(runCtx)
+	}
+	// the dependencies of the processes without depends_on are met, they start by priority
+	for _, proc := range runOrder {
+		if len(proc.DependsOn) == 0 {
+			p.startQueue.push(proc.ReplicaName, proc.Priority)
+		}
+	}
+	processes := make([]*Process, 0, len(runOrder))
 	for _, proc := range runOrder {
 		newConf := proc
-		p.runProcess(&newConf)
+		processes = append(processes, p.runProcess(&newConf))
+		if len(proc.WatchPaths) > 0 {
+			go p.watchProcess(runCtx, proc)
+		}
 	}
+	summaryDone := make(chan struct{})
+	go func() {
+		defer close(summaryDone)
+		logStartupSummary(startTime, processes)
+	}()
 	p.waitGroup.Wait()
+	<-summaryDone
 	log.Info().Msg("Project completed")
-	if p.exitCode != 0 {
-		err = &ExitError{p.exitCode}
+	exitCode := p.getExitCode()
+	if p.project.SummaryFile != "" {
+		writeRunSummary(p.project.SummaryFile, startTime, processes, exitCode)
+	}
+	if exitCode != 0 {
+		err = &ExitError{exitCode}
 	}
 	return err
 }
 
-func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
+// logStartupSummary logs the project startup result once all the processes have started or won't run
+func logStartupSummary(startTime time.Time, processes []*Process) {
+	started, wontRun, failed := 0, 0, 0
+	for _, proc := range processes {
+		proc.waitForStartup(context.Background())
+		switch proc.getStatusName() {
+		case types.ProcessStateSkipped:
+			wontRun++
+		case types.ProcessStateError:
+			failed++
+		default:
+			started++
+		}
+	}
+	log.Info().
+		Int("total", len(processes)).
+		Int("started", started).
+		Int("wont_run", wontRun).
+		Int("failed", failed).
+		Dur("startup_duration", time.Since(startTime)).
+		Msg("Project startup completed")
+}
+
+// getLogTimestampFormat returns the format of the timestamps the process logs add
+func (p *ProjectRunner) getLogTimestampFormat(config *types.ProcessConfig) string {
+	if config.LogTimestampFormat != "" {
+		return config.LogTimestampFormat
+	}
+	// the project default formats the timestamps the process logs add, it doesn't add them
+	logConf := config.LoggerConfig
+	if logConf != nil && logConf.AddTimestamp && logConf.TimestampFormat == "" {
+		return p.project.LogTimestampFormat
+	}
+	return ""
+}
+
+func (p *ProjectRunner) runProcess(config *types.ProcessConfig) *Process {
+	timestampFormat := p.getLogTimestampFormat(config)
+	var lineTemplate *pclog.LineTemplate
+	if config.LogLineTemplate != "" {
+		templateTimestampFormat := config.LogTimestampFormat
+		if templateTimestampFormat == "" {
+			templateTimestampFormat = p.project.LogTimestampFormat
+		}
+		var err error
+		lineTemplate, err = pclog.NewLineTemplate(config.LogLineTemplate, templateTimestampFormat)
+		if err != nil {
+			// validated by the loader
+			log.Err(err).Msgf("invalid log_line_template of %s", config.ReplicaName)
+		}
+	}
+	// the template applies to the process own logs only, the project log is shared by all the processes
+	newProcLogger := func() *pclog.PCLog {
+		return pclog.NewLogger().
+			WithTimestampFormat(timestampFormat).
+			WithAppend(config.IsLogAppend()).
+			WithLineTemplate(lineTemplate)
+	}
 	procLogger := p.logger
 	if isStringDefined(config.LogLocation) {
-		procLogger = pclog.NewLogger()
+		procLogger = newProcLogger()
+	}
+	var stdoutLogger, stderrLogger pclog.PcLogger
+	if isStringDefined(config.StdoutLogLocation) {
+		stdoutLogger = newProcLogger()
+	}
+	if isStringDefined(config.StderrLogLocation) {
+		stderrLogger = newProcLogger()
 	}
 	procLog, err := p.getProcessLog(config.ReplicaName)
 	if err != nil {
@@ -112,11 +286,14 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 	if isMain {
 		extraArgs = p.mainProcessArgs
 		config.RestartPolicy.ExitOnEnd = true
+		config.RestartPolicy.ExitOnSkipped = true
 	}
 	process := NewProcess(
 		withTuiOn(p.isTuiOn),
 		withGlobalEnv(p.project.Environment),
 		withLogger(procLogger),
+		withStdLoggers(stdoutLogger, stderrLogger),
+		withLineTemplate(lineTemplate),
 		withProcConf(config),
 		withProcState(procState),
 		withProcLog(procLog),
@@ -124,74 +301,441 @@ func (p *ProjectRunner) runProcess(config *types.ProcessConfig) {
 		withPrintLogs(printLogs),
 		withIsMain(isMain),
 		withExtraArgs(extraArgs),
+		withRunID(p.runID),
+		withOtlpLogs(p.otlpLogs),
+		withIsolation(p.isIsolated),
+		withPidDir(p.project.CleanupPIDDir),
+		withStateChangeFn(p.onStateChange),
+		withEndFn(p.cacheLastOutput),
+		withOutputPipes(p.popOutputPipes(config.ReplicaName)),
+		withSecretEnvVarFn(p.project.IsSecretEnvVar),
 	)
+	if config.Detach {
+		p.runDetachedProcess(process)
+		return process
+	}
 	p.addRunningProcess(process)
 	p.waitGroup.Add(1)
 	go func(proc *Process) {
+		defer close(proc.removed)
 		defer p.removeRunningProcess(proc)
 		defer p.waitGroup.Done()
-		if err = p.waitIfNeeded(proc.procConf); err != nil {
-			log.Error().Msgf("Error: %s", err.Error())
-			log.Error().Msgf("Error: process %s won't run", proc.getName())
-			proc.wontRun()
-			p.onProcessSkipped(proc.procConf)
+		if err = p.popValidationError(proc.getName()); err == nil {
+			err = p.waitIfNeeded(proc)
+		}
+		if err == nil {
+			err = proc.validateRequiredEnv()
+		}
+		if err == nil && proc.procConf.InputFrom != "" {
+			proc.inputPipe, err = p.getInputPipe(proc.procConf)
+		}
+		if proc.inputPipe != nil {
+			defer proc.inputPipe.closeReader()
+		} else if proc.procConf.InputFrom != "" {
+			p.discardInputPipe(proc.getName())
+		}
+		if err != nil {
+			p.skipProcess(proc, err)
 		} else {
+			go p.dequeueOnStartup(proc)
 			exitCode := proc.run()
+			if !proc.wasStopped() {
+				p.trackExitCode(proc, exitCode)
+			}
 			p.onProcessEnd(exitCode, proc.procConf)
 		}
 	}(process)
+	return process
 }
 
-func (p *ProjectRunner) waitIfNeeded(process *types.ProcessConfig) error {
+// runDetachedProcess launches a detached process once its dependencies are met.
+// Detached processes are not tracked as running and are not stopped on shutdown
+func (p *ProjectRunner) runDetachedProcess(proc *Process) {
+	p.waitGroup.Add(1)
+	go func() {
+		defer p.waitGroup.Done()
+		err := p.popValidationError(proc.getName())
+		if err == nil {
+			err = p.waitIfNeeded(proc)
+		}
+		if err == nil {
+			err = proc.validateRequiredEnv()
+		}
+		if err != nil {
+			p.skipProcess(proc, err)
+			return
+		}
+		go p.dequeueOnStartup(proc)
+		proc.runDetached()
+	}()
+}
+
+// skipProcess marks the process as one that won't run, as waiting for its dependencies failed.
+// The waits stopped by the project shutdown are not failures
+func (p *ProjectRunner) skipProcess(proc *Process, err error) {
+	p.startQueue.done(proc.getName())
+	if errors.Is(err, context.Canceled) {
+		log.Debug().Msgf("process %s won't run, the project is shutting down: %s", proc.getName(), err.Error())
+		proc.endPending()
+		return
+	}
+	log.Error().Msgf("Error: %s", err.Error())
+	log.Error().Msgf("Error: process %s won't run", proc.getName())
+	proc.wontRun(err)
+	p.onProcessSkipped(proc.procConf)
+}
+
+// dequeueOnStartup lets the lower priority processes start once the process has started, or ended without starting
+func (p *ProjectRunner) dequeueOnStartup(proc *Process) {
+	proc.waitForStartup(p.ctxWait)
+	p.startQueue.done(proc.getName())
+}
+
+func (p *ProjectRunner) waitIfNeeded(proc *Process) error {
+	ctx := p.ctxWait
+	process := proc.procConf
 	for k := range process.DependsOn {
-		if runningProc := p.getRunningProcess(k); runningProc != nil {
-
-			switch process.DependsOn[k].Condition {
-			case types.ProcessConditionCompleted:
-				runningProc.waitForCompletion()
-			case types.ProcessConditionCompletedSuccessfully:
-				log.Info().Msgf("%s is waiting for %s to complete successfully", process.ReplicaName, k)
-				exitCode := runningProc.waitForCompletion()
-				if exitCode != 0 {
-					return fmt.Errorf("process %s depended on %s to complete successfully, but it exited with status %d",
-						process.ReplicaName, k, exitCode)
-				}
-			case types.ProcessConditionHealthy:
-				log.Info().Msgf("%s is waiting for %s to be healthy", process.ReplicaName, k)
-				ready := runningProc.waitUntilReady()
-				if !ready {
-					return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, k)
-				}
-			case types.ProcessConditionLogReady:
-				log.Info().Msgf("%s is waiting for %s log line %s", process.ReplicaName, k, runningProc.procConf.ReadyLogLine)
-				ready := runningProc.waitUntilReady()
-				if !ready {
-					return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, k)
-				}
-			case types.ProcessConditionStarted:
-				log.Info().Msgf("%s is waiting for %s to start", process.ReplicaName, k)
-				runningProc.waitForStarted()
+		if runningProc := p.getDependencyProcess(k); runningProc != nil {
+			stopProgress := logWaitProgress(process.ReplicaName, k, process.DependsOn[k], runningProc)
+			err := p.waitForDependency(ctx, process, k, runningProc)
+			stopProgress()
+			if err != nil {
+				return err
+			}
+			if k == process.EnvFromProcess {
+				proc.envFromProcess = parseEnvFromOutput(k, p.getLastOutput(k))
+			}
+		} else if replicas := p.getRunningReplicas(k); len(replicas) > 0 {
+			if err := p.waitForReplicas(ctx, process, k, replicas); err != nil {
+				return err
 			}
 		} else {
 			log.Error().Msgf("Error: process %s depends on %s, but it isn't running", process.ReplicaName, k)
 		}
 
 	}
+	return p.startQueue.waitForTurn(ctx, process.ReplicaName, process.Priority)
+}
+
+// waitForReplicas waits until ready_replicas (default: all) replicas of the dependency meet the dependency condition.
+// The waits for the other replicas are canceled once enough of them are ready, or too many have failed
+func (p *ProjectRunner) waitForReplicas(ctx context.Context, process *types.ProcessConfig, name string, replicas []*Process) error {
+	dependency := process.DependsOn[name]
+	required := len(replicas)
+	if dependency.ReadyReplicas > 0 && dependency.ReadyReplicas < required {
+		required = dependency.ReadyReplicas
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan error, len(replicas))
+	for _, replica := range replicas {
+		go func(replica *Process) {
+			stopProgress := logWaitProgress(process.ReplicaName, replica.getName(), dependency, replica)
+			defer stopProgress()
+			results <- p.waitForDependency(ctx, process, name, replica)
+		}(replica)
+	}
+	ready, failed := 0, 0
+	for range replicas {
+		err := <-results
+		if err == nil {
+			ready++
+			if ready == required {
+				log.Info().Msgf("%d of %d %s replicas are ready for %s", ready, len(replicas), name, process.ReplicaName)
+				return nil
+			}
+			continue
+		}
+		failed++
+		if failed > len(replicas)-required {
+			return fmt.Errorf("process %s depended on %d of %d %s replicas, but %d failed: %w",
+				process.ReplicaName, required, len(replicas), name, failed, err)
+		}
+	}
+	return nil
+}
+
+// parseEnvFromOutput parses the whitespace separated KEY=VALUE pairs of an env_from_process output line
+func parseEnvFromOutput(name, line string) []string {
+	env := []string{}
+	for _, pair := range strings.Fields(line) {
+		if key, _, found := strings.Cut(pair, "="); !found || key == "" {
+			log.Warn().Msgf("ignoring '%s' from %s output: not a KEY=VALUE pair", pair, name)
+			continue
+		}
+		env = append(env, pair)
+	}
+	return env
+}
+
+// waitForDependency waits until the dependency meets its condition, or the context is canceled
+func (p *ProjectRunner) waitForDependency(ctx context.Context, process *types.ProcessConfig, k string, runningProc *Process) error {
+	err := p.waitForCondition(ctx, process, k, runningProc)
+	if ctx.Err() != nil {
+		return fmt.Errorf("process %s stopped waiting for %s: %w", process.ReplicaName, k, ctx.Err())
+	}
+	return err
+}
+
+func (p *ProjectRunner) waitForCondition(ctx context.Context, process *types.ProcessConfig, k string, runningProc *Process) error {
+	switch process.DependsOn[k].Condition {
+	case types.ProcessConditionCompleted:
+		runningProc.waitForCompletion(ctx)
+	case types.ProcessConditionCompletedSuccessfully:
+		log.Info().Msgf("%s is waiting for %s to complete successfully", process.ReplicaName, k)
+		if process.DependsOn[k].AttemptLimit > 1 {
+			return p.waitForSuccessWithRetries(ctx, process, k, runningProc)
+		}
+		exitCode := runningProc.waitForCompletion(ctx)
+		succeeded := runningProc.procConf.IsSuccessExitCode(exitCode)
+		if !succeeded && runningProc.procConf.Shadow {
+			log.Warn().Msgf("shadow process %s exited with status %d, %s will run anyway", k, exitCode, process.ReplicaName)
+		} else if !succeeded {
+			return fmt.Errorf("process %s depended on %s to complete successfully, but it exited with status %d",
+				process.ReplicaName, k, exitCode)
+		}
+	case types.ProcessConditionHealthy:
+		log.Info().Msgf("%s is waiting for %s to be healthy", process.ReplicaName, k)
+		if dependency := process.DependsOn[k]; dependency.HasReadinessRetries() {
+			return waitUntilHealthyWithRetries(ctx, process.ReplicaName, k, &dependency, runningProc)
+		}
+		ready := runningProc.waitUntilReady(ctx)
+		if !ready {
+			return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, k)
+		}
+	case types.ProcessConditionLogReady:
+		log.Info().Msgf("%s is waiting for %s log line %s", process.ReplicaName, k, runningProc.procConf.ReadyLogLine)
+		ready := runningProc.waitUntilReady(ctx)
+		if !ready {
+			return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process.ReplicaName, k)
+		}
+	case types.ProcessConditionStarted:
+		log.Info().Msgf("%s is waiting for %s to start", process.ReplicaName, k)
+		runningProc.waitForStarted(ctx)
+	case types.ProcessConditionStartedNonBlocking:
+		// the dependency was already launched, it only defines the start order
+		log.Debug().Msgf("%s is started after %s", process.ReplicaName, k)
+	}
 	return nil
 }
 
+// waitForSuccessWithRetries waits for the dependency to complete successfully in up to attempt_limit runs. After a
+// failed run, the dependency is restarted by its restart policy, or if it isn't, it is restarted after retry_delay
+func (p *ProjectRunner) waitForSuccessWithRetries(ctx context.Context, process *types.ProcessConfig, k string, runningProc *Process) error {
+	dependency := process.DependsOn[k]
+	name := runningProc.getName()
+	runs, attempt := 0, 0
+	for {
+		ended, exitCode, done := runningProc.waitForRunEnd(ctx, runs)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// a process that failed to start is done without an ended run
+		if ended > runs || ended == 0 {
+			runs = ended
+			attempt++
+			if runningProc.procConf.IsSuccessExitCode(exitCode) {
+				return nil
+			}
+			if attempt >= dependency.AttemptLimit {
+				if runningProc.procConf.Shadow {
+					log.Warn().Msgf("shadow process %s exited with status %d, %s will run anyway", name, exitCode, process.ReplicaName)
+					return nil
+				}
+				return fmt.Errorf("process %s depended on %s to complete successfully, but it exited with status %d in %d attempts",
+					process.ReplicaName, name, exitCode, attempt)
+			}
+			log.Warn().Msgf("%s exited with status %d, attempt %d of %d for %s", name, exitCode, attempt, dependency.AttemptLimit, process.ReplicaName)
+		}
+		if !done {
+			// restarted by its restart policy
+			continue
+		}
+		if runningProc.wasStopped() {
+			return fmt.Errorf("process %s depended on %s to complete successfully, but it was stopped", process.ReplicaName, name)
+		}
+		restarted, err := p.restartDependency(runningProc, dependency.RetryDelay)
+		// the running processes are locked while the project shuts down
+		if dependent := p.getRunningProcess(process.ReplicaName); dependent != nil && dependent.wasStopped() {
+			return fmt.Errorf("process %s was stopped while waiting for %s", process.ReplicaName, name)
+		}
+		if err != nil {
+			return fmt.Errorf("process %s depended on %s to complete successfully, but it didn't restart: %w", process.ReplicaName, name, err)
+		}
+		runningProc = restarted
+		runs = 0
+	}
+}
+
+// waitUntilHealthyWithRetries probes the dependency readiness with the dependency's own interval and per-attempt timeout.
+// The dependency is considered failed after ReadinessProbeMaxRetries failed probes (0 retries indefinitely)
+func waitUntilHealthyWithRetries(ctx context.Context, process, name string, dependency *types.ProcessDependency, runningProc *Process) error {
+	probe := runningProc.procConf.ReadinessProbe
+	if probe == nil {
+		return fmt.Errorf("process %s depended on %s to become ready, but it has no readiness probe", process, name)
+	}
+	defaults := *probe
+	defaults.ValidateAndSetDefaults()
+	interval := dependency.ReadinessProbeInterval
+	if interval <= 0 {
+		interval = time.Duration(defaults.PeriodSeconds) * time.Second
+	}
+	timeout := dependency.ReadinessProbeTimeout
+	if timeout <= 0 {
+		timeout = time.Duration(defaults.TimeoutSeconds) * time.Second
+	}
+	runningProc.waitForStarted(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-runningProc.procReadyCtx.Done():
+			if runningProc.getHealth() == types.ProcessHealthReady {
+				return nil
+			}
+			return fmt.Errorf("process %s depended on %s to become ready, but it was terminated", process, name)
+		case <-ticker.C:
+			err := health.CheckOnce(*probe, timeout)
+			if err == nil {
+				return nil
+			}
+			failures++
+			log.Debug().Msgf("%s readiness probe for %s failed (%d) - %s", name, process, failures, err.Error())
+			if dependency.ReadinessProbeMaxRetries > 0 && failures >= dependency.ReadinessProbeMaxRetries {
+				return fmt.Errorf("process %s depended on %s to become ready, but its readiness probe failed %d times",
+					process, name, failures)
+			}
+		}
+	}
+}
+
+// logWaitProgress periodically logs that process is still waiting for its dependency.
+// The returned function stops the logging
+func logWaitProgress(process, name string, dependency types.ProcessDependency, runningProc *Process) func() {
+	interval := dependency.WaitWarningInterval
+	if interval <= 0 {
+		interval = waitProgressInterval
+	}
+	done := make(chan struct{})
+	go func() {
+		start := time.Now()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				log.Warn().Msgf("%s still waiting for %s (%s) for %s: %s",
+					process, name, dependency.Condition, formatWaitDuration(time.Since(start)),
+					describeDependencyWait(dependency.Condition, runningProc))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+// formatWaitDuration formats the wait duration as Xm Ys
+func formatWaitDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%dm %ds", seconds/60, seconds%60)
+}
+
+// describeDependencyWait describes the dependency state, with a hint if the condition is unlikely to be met
+func describeDependencyWait(condition string, runningProc *Process) string {
+	state := runningProc.getState()
+	desc := fmt.Sprintf("%s is %s", runningProc.getName(), state.Status)
+	switch state.Status {
+	case types.ProcessStateCompleted, types.ProcessStateRestarting:
+		desc += fmt.Sprintf(" with exit code %d", state.ExitCode)
+	}
+	switch {
+	case state.Status == types.ProcessStateCompleted &&
+		condition != types.ProcessConditionCompleted && condition != types.ProcessConditionCompletedSuccessfully:
+		desc += fmt.Sprintf(" - hint: it has already completed, did you mean condition %s?", types.ProcessConditionCompleted)
+	case state.Status == types.ProcessStateRestarting && condition == types.ProcessConditionCompletedSuccessfully &&
+		!runningProc.procConf.IsSuccessExitCode(state.ExitCode):
+		desc += " - hint: it exited with a failure and is being restarted, it might never complete successfully"
+	case state.Status == types.ProcessStateDisabled:
+		desc += " - hint: a disabled process has to be started manually"
+	}
+	return desc
+}
+
 func (p *ProjectRunner) onProcessEnd(exitCode int, procConf *types.ProcessConfig) {
+	if procConf.Shadow || procConf.IsSuccessExitCode(exitCode) {
+		// shadow processes and success exit codes don't affect the project exit code
+		exitCode = 0
+	}
 	if (exitCode != 0 && procConf.RestartPolicy.Restart == types.RestartPolicyExitOnFailure) ||
 		procConf.RestartPolicy.ExitOnEnd {
 		p.ShutDownProject()
-		p.exitCode = exitCode
+		p.setExitCode(exitCode)
 	}
 }
 
+// trackExitCode keeps the highest failure exit code of the processes, which is the project exit code
+// unless it was set by an exit_on_failure, exit_on_end, exit_on_skipped or a deadlock
+func (p *ProjectRunner) trackExitCode(proc *Process, exitCode int) {
+	if proc.procConf.Shadow || proc.procConf.IsSuccessExitCode(exitCode) {
+		return
+	}
+	if exitCode < 1 {
+		// killed by a signal
+		exitCode = 1
+	}
+	p.exitCodeMtx.Lock()
+	defer p.exitCodeMtx.Unlock()
+	if p.supersededRuns[proc] {
+		return
+	}
+	p.failedExitCodes[proc] = exitCode
+}
+
+// supersedeExitCode leaves the failed run of a process out of the project exit code, as it's retried
+func (p *ProjectRunner) supersedeExitCode(proc *Process) {
+	p.exitCodeMtx.Lock()
+	defer p.exitCodeMtx.Unlock()
+	p.supersededRuns[proc] = true
+	delete(p.failedExitCodes, proc)
+}
+
+// setExitCode sets the project exit code, instead of the worst exit code of the processes
+func (p *ProjectRunner) setExitCode(exitCode int) {
+	p.exitCodeMtx.Lock()
+	defer p.exitCodeMtx.Unlock()
+	p.exitCode = exitCode
+	p.isExitCodeSet = true
+}
+
+// getExitCode returns the project exit code
+func (p *ProjectRunner) getExitCode() int {
+	p.exitCodeMtx.Lock()
+	defer p.exitCodeMtx.Unlock()
+	if p.isExitCodeSet {
+		return p.exitCode
+	}
+	return p.getWorstExitCode()
+}
+
+// getWorstExitCode returns the highest failure exit code of the processes, requires exitCodeMtx
+func (p *ProjectRunner) getWorstExitCode() int {
+	worst := 0
+	for _, exitCode := range p.failedExitCodes {
+		worst = max(worst, exitCode)
+	}
+	return worst
+}
+
 func (p *ProjectRunner) onProcessSkipped(procConf *types.ProcessConfig) {
 	if procConf.RestartPolicy.ExitOnSkipped {
 		p.ShutDownProject()
-		p.exitCode = 1
+		p.setExitCode(1)
 	}
 }
 
@@ -199,6 +743,8 @@ func (p *ProjectRunner) initProcessStates() {
 	p.statesMutex.Lock()
 	defer p.statesMutex.Unlock()
 	p.processStates = make(map[string]*types.ProcessState)
+	p.endReasons = make(map[string]string)
+	p.lastOutputs = make(map[string]string)
 	for name, proc := range p.project.Processes {
 		p.processStates[name] = types.NewProcessState(&proc)
 	}
@@ -212,10 +758,19 @@ func (p *ProjectRunner) initProcessLogs() {
 }
 
 func (p *ProjectRunner) initProcessLog(name string) {
-	p.processLogs[name] = pclog.NewLogBuffer(p.project.LogLength)
+	p.processLogs[name] = pclog.NewLogBuffer(p.getLogLength(p.project.Processes[name]))
+}
+
+// getLogLength returns the number of output lines kept for the process, its max_output_lines or the project log_length
+func (p *ProjectRunner) getLogLength(procConf types.ProcessConfig) int {
+	if procConf.MaxOutputLines > 0 {
+		return procConf.MaxOutputLines
+	}
+	return p.project.LogLength
 }
 
 func (p *ProjectRunner) GetProcessState(name string) (*types.ProcessState, error) {
+	name = p.project.ResolveProcessName(name)
 	proc := p.getRunningProcess(name)
 	if proc != nil {
 		return proc.getState(), nil
@@ -261,13 +816,73 @@ func (p *ProjectRunner) getRunningProcess(name string) *Process {
 	return nil
 }
 
+// getRunningReplicas returns the running replicas of a process with multiple replicas, sorted by replica name
+func (p *ProjectRunner) getRunningReplicas(name string) []*Process {
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	var replicas []*Process
+	for _, runningProc := range p.runningProcesses {
+		if runningProc.procConf.Name == name && runningProc.procConf.Replicas > 1 {
+			replicas = append(replicas, runningProc)
+		}
+	}
+	slices.SortFunc(replicas, func(a, b *Process) int {
+		return strings.Compare(a.getName(), b.getName())
+	})
+	return replicas
+}
+
+// GetRunningProcessNames returns the sorted names of the running processes
+func (p *ProjectRunner) GetRunningProcessNames() []string {
+	p.runProcMutex.Lock()
+	names := make([]string, 0, len(p.runningProcesses))
+	for name := range p.runningProcesses {
+		names = append(names, name)
+	}
+	p.runProcMutex.Unlock()
+	sort.Strings(names)
+	return names
+}
+
+// cacheLastOutput caches the last output line of the ended process, before its dependents are released, to be read
+// by the env_from_process dependents even after the process is removed or restarted
+func (p *ProjectRunner) cacheLastOutput(process *Process) {
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	p.lastOutputs[process.getName()] = process.getLastOutputLine()
+}
+
+func (p *ProjectRunner) getLastOutput(name string) string {
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	return p.lastOutputs[name]
+}
+
 func (p *ProjectRunner) removeRunningProcess(process *Process) {
+	if reason := process.getEndReason(); reason != "" {
+		p.statesMutex.Lock()
+		p.endReasons[process.getName()] = reason
+		p.statesMutex.Unlock()
+	}
 	p.runProcMutex.Lock()
 	delete(p.runningProcesses, process.getName())
+	p.endedProcesses[process.getName()] = process
 	p.runProcMutex.Unlock()
 }
 
+// getDependencyProcess returns the running process, or its last run if it already ended, so a dependency that
+// ended before its dependent looked it up is still waited for by its final state
+func (p *ProjectRunner) getDependencyProcess(name string) *Process {
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	if runningProc, ok := p.runningProcesses[name]; ok {
+		return runningProc
+	}
+	return p.endedProcesses[name]
+}
+
 func (p *ProjectRunner) StartProcess(name string) error {
+	name = p.project.ResolveProcessName(name)
 	proc := p.getRunningProcess(name)
 	if proc != nil {
 		log.Error().Msgf("Process %s is already running", name)
@@ -282,9 +897,45 @@ func (p *ProjectRunner) StartProcess(name string) error {
 	return nil
 }
 
-func (p *ProjectRunner) StopProcess(name string) error {
-	log.Info().Msgf("Stopping %s", name)
+// StopProcess stops the process and waits for it to exit.
+// A non-positive timeout waits until the process exits
+func (p *ProjectRunner) StopProcess(name string, timeout time.Duration) error {
+	name = p.project.ResolveProcessName(name)
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
 	proc := p.getRunningProcess(name)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- p.stopRunningProcess(name, proc)
+	}()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			return err
+		}
+	case <-timeoutCh:
+		return fmt.Errorf("process %s didn't exit within %v", name, timeout)
+	}
+	// the process is removed from the running processes only after its exit is handled
+	select {
+	case <-proc.removed:
+		return nil
+	case <-timeoutCh:
+		return fmt.Errorf("process %s didn't exit within %v", name, timeout)
+	}
+}
+
+func (p *ProjectRunner) stopProcess(name string) error {
+	return p.stopRunningProcess(name, p.getRunningProcess(name))
+}
+
+// stopRunningProcess stops proc, the running process of name, or fails if the process isn't running
+func (p *ProjectRunner) stopRunningProcess(name string, proc *Process) error {
+	log.Info().Msgf("Stopping %s", name)
 	if proc == nil {
 		if _, ok := p.project.Processes[name]; !ok {
 			log.Error().Msgf("Process %s does not exist", name)
@@ -304,7 +955,7 @@ func (p *ProjectRunner) StopProcesses(names []string) (map[string]string, error)
 	stopped := make(map[string]string)
 	successes := 0
 	for _, name := range names {
-		if err := p.StopProcess(name); err == nil {
+		if err := p.stopProcess(p.project.ResolveProcessName(name)); err == nil {
 			stopped[name] = "ok"
 			successes++
 		} else {
@@ -322,6 +973,7 @@ func (p *ProjectRunner) StopProcesses(names []string) (map[string]string, error)
 }
 
 func (p *ProjectRunner) RestartProcess(name string) error {
+	name = p.project.ResolveProcessName(name)
 	log.Debug().Msgf("Restarting %s", name)
 	proc := p.getRunningProcess(name)
 	if proc != nil {
@@ -341,7 +993,23 @@ func (p *ProjectRunner) RestartProcess(name string) error {
 	return nil
 }
 
+// ReloadProcess signals a running process to reload its configuration, instead of restarting it
+func (p *ProjectRunner) ReloadProcess(name string) error {
+	name = p.project.ResolveProcessName(name)
+	proc := p.getRunningProcess(name)
+	if proc == nil {
+		log.Error().Msgf("Process %s is not running", name)
+		return fmt.Errorf("process %s is not running", name)
+	}
+	err := proc.reload()
+	if err != nil {
+		log.Err(err).Msgf("failed to reload process %s", name)
+	}
+	return err
+}
+
 func (p *ProjectRunner) GetProcessInfo(name string) (*types.ProcessConfig, error) {
+	name = p.project.ResolveProcessName(name)
 	p.runProcMutex.Lock()
 	defer p.runProcMutex.Unlock()
 	if processConfig, ok := p.project.Processes[name]; ok {
@@ -351,7 +1019,60 @@ func (p *ProjectRunner) GetProcessInfo(name string) (*types.ProcessConfig, error
 	}
 }
 
+// UpdateProcessConfig applies the process informational fields (description, owner, runbook URL and labels) and
+// its max output lines without restarting it. Changes to any other field are rejected as they require a restart
+func (p *ProjectRunner) UpdateProcessConfig(name string, procConf *types.ProcessConfig) error {
+	name = p.project.ResolveProcessName(name)
+	p.runProcMutex.Lock()
+	defer p.runProcMutex.Unlock()
+	p.procConfMutex.Lock()
+	current, ok := p.project.Processes[name]
+	p.procConfMutex.Unlock()
+	if !ok {
+		return fmt.Errorf("no such process: %s", name)
+	}
+	requested := *procConf
+	if requested.Name == "" {
+		requested.Name = current.Name
+	}
+	// fields calculated by the loader
+	requested.ReplicaNum = current.ReplicaNum
+	requested.ReplicaName = current.ReplicaName
+	requested.Executable = current.Executable
+	requested.Args = current.Args
+
+	updated := current
+	updated.Description = requested.Description
+	updated.Owner = requested.Owner
+	updated.RunbookURL = requested.RunbookURL
+	updated.Labels = requested.Labels
+	updated.MaxOutputLines = requested.MaxOutputLines
+	if !updated.Compare(&requested) || !slices.Equal(current.Entrypoint, requested.Entrypoint) {
+		return fmt.Errorf("changes to process %s require a restart, update the project to apply them", name)
+	}
+
+	p.procConfMutex.Lock()
+	p.project.Processes[name] = updated
+	p.procConfMutex.Unlock()
+	if logs, ok := p.processLogs[name]; ok {
+		logs.SetSize(p.getLogLength(updated))
+	}
+	if proc, ok := p.runningProcesses[name]; ok {
+		// the running process shares its state with processStates, guarded by its stateMtx
+		proc.updateInfo(&updated)
+	} else {
+		p.statesMutex.Lock()
+		if state, ok := p.processStates[name]; ok {
+			state.Description = updated.Description
+		}
+		p.statesMutex.Unlock()
+	}
+	log.Info().Msgf("Process %s config updated", name)
+	return nil
+}
+
 func (p *ProjectRunner) GetProcessPorts(name string) (*types.ProcessPorts, error) {
+	name = p.project.ResolveProcessName(name)
 	proc := p.getRunningProcess(name)
 	if proc == nil {
 		return nil, fmt.Errorf("can't get ports: process %s is not running", name)
@@ -399,6 +1120,16 @@ func (p *ProjectRunner) SetProcessPassword(name, pass string) error {
 
 }
 
+// WriteProcessStdin sends an input line to a running process with keep_stdin_open
+func (p *ProjectRunner) WriteProcessStdin(name, input string) error {
+	name = p.project.ResolveProcessName(name)
+	proc := p.getRunningProcess(name)
+	if proc == nil {
+		return fmt.Errorf("process %s is not running", name)
+	}
+	return proc.writeStdin(input)
+}
+
 func (p *ProjectRunner) runningProcessesReverseDependencies() map[string]map[string]*Process {
 	reverseDependencies := make(map[string]map[string]*Process)
 
@@ -432,7 +1163,7 @@ func (p *ProjectRunner) shutDownInOrder(wg *sync.WaitGroup, shutdownOrder []*Pro
 				for _, runningProc := range revDeps {
 					waitForDepsWg.Add(1)
 					go func(pr *Process) {
-						pr.waitForCompletion()
+						pr.waitForCompletion(context.Background())
 						waitForDepsWg.Done()
 					}(runningProc)
 				}
@@ -445,7 +1176,7 @@ func (p *ProjectRunner) shutDownInOrder(wg *sync.WaitGroup, shutdownOrder []*Pro
 				log.Err(err).Msgf("failed to shutdown %s", proc.getName())
 				return
 			}
-			proc.waitForCompletion()
+			proc.waitForCompletion(context.Background())
 		}(process)
 	}
 }
@@ -463,7 +1194,7 @@ func (p *ProjectRunner) shutDownAndWait(shutdownOrder []*Process) {
 			}
 			wg.Add(1)
 			go func(pr *Process) {
-				pr.waitForCompletion()
+				pr.waitForCompletion(context.Background())
 				wg.Done()
 			}(proc)
 		}
@@ -473,12 +1204,13 @@ func (p *ProjectRunner) shutDownAndWait(shutdownOrder []*Process) {
 }
 
 func (p *ProjectRunner) ShutDownProject() error {
+	p.cancelWaitFn()
 	p.runProcMutex.Lock()
 	defer p.runProcMutex.Unlock()
 
 	shutdownOrder := []*Process{}
 	if p.isOrderedShutDown {
-		err := p.project.WithProcesses([]string{}, func(process types.ProcessConfig) error {
+		err := p.project.WithProcesses(context.Background(), []string{}, func(process types.ProcessConfig) error {
 			if runningProc, ok := p.runningProcesses[process.ReplicaName]; ok {
 				shutdownOrder = append(shutdownOrder, runningProc)
 			}
@@ -502,12 +1234,56 @@ func (p *ProjectRunner) ShutDownProject() error {
 	for _, proc := range shutdownOrder {
 		proc.prepareForShutDown()
 	}
+	p.shutdownMtx.Lock()
+	p.shutdownProcs = shutdownOrder
+	if p.isKilled {
+		// KillProject was called before the shutdown started
+		p.killShutdownProcs()
+	}
+	p.shutdownMtx.Unlock()
 
 	p.shutDownAndWait(shutdownOrder)
 	p.cancelAppFn()
 	return nil
 }
 
+// ShutDownProjectWithTimeout shuts down the project, and kills the processes that are still running after the timeout
+func (p *ProjectRunner) ShutDownProjectWithTimeout(timeout time.Duration) error {
+	killTimer := time.AfterFunc(timeout, p.KillProject)
+	defer killTimer.Stop()
+	return p.ShutDownProject()
+}
+
+// KillProject immediately kills the processes that are still shutting down,
+// without waiting for their shutdown timeouts. If the shutdown hasn't started yet, they are killed once it starts
+func (p *ProjectRunner) KillProject() {
+	p.shutdownMtx.Lock()
+	defer p.shutdownMtx.Unlock()
+	p.isKilled = true
+	p.killShutdownProcs()
+}
+
+func (p *ProjectRunner) killShutdownProcs() {
+	for _, proc := range p.shutdownProcs {
+		if err := proc.kill(); err != nil {
+			log.Err(err).Msgf("failed to kill %s", proc.getName())
+		}
+	}
+}
+
+// GetGracePeriod returns how long the processes are given to shut down gracefully before they are killed
+func (p *ProjectRunner) GetGracePeriod() time.Duration {
+	return GetGracePeriod(p.project)
+}
+
+// GetGracePeriod returns the project grace_period_seconds, or the default grace period
+func GetGracePeriod(project *types.Project) time.Duration {
+	if project.GracePeriodSeconds > 0 {
+		return time.Duration(project.GracePeriodSeconds) * time.Second
+	}
+	return DefaultGracePeriodSec * time.Second
+}
+
 func (p *ProjectRunner) WaitForProjectShutdown() {
 	if p.ctxApp != nil {
 		if !p.isTuiOn {
@@ -537,7 +1313,30 @@ func (p *ProjectRunner) getProcessLog(name string) (*pclog.ProcessLogBuffer, err
 	return nil, fmt.Errorf("process %s doesn't exist", name)
 }
 
+// GetProcessEnvironment returns the environment and the working directory
+// the process would be started with, without starting it
+func (p *ProjectRunner) GetProcessEnvironment(name string) ([]string, string, error) {
+	name = p.project.ResolveProcessName(name)
+	procConf, ok := p.project.Processes[name]
+	if !ok {
+		log.Error().Msgf("Error: process %s doesn't exist", name)
+		return nil, "", fmt.Errorf("can't get environment of process %s: no such process", name)
+	}
+	proc := NewProcess(
+		withGlobalEnv(p.project.Environment),
+		withProcConf(&procConf),
+		withShellConfig(*p.project.ShellConfig),
+	)
+	return proc.getProcessEnvironment(), procConf.WorkingDir, nil
+}
+
+// IsSecretEnvVar returns true if the environment variable value must not be shown
+func (p *ProjectRunner) IsSecretEnvVar(key string) bool {
+	return p.project.IsSecretEnvVar(key)
+}
+
 func (p *ProjectRunner) GetProcessLog(name string, offsetFromEnd, limit int) ([]string, error) {
+	name = p.project.ResolveProcessName(name)
 	logs, err := p.getProcessLog(name)
 	if err != nil {
 		return nil, err
@@ -545,7 +1344,42 @@ func (p *ProjectRunner) GetProcessLog(name string, offsetFromEnd, limit int) ([]
 	return logs.GetLogRange(offsetFromEnd, limit), nil
 }
 
+// SearchProcessLog returns the process log lines matching the query.
+// The process log file is searched if defined, otherwise the in-memory log buffer
+func (p *ProjectRunner) SearchProcessLog(name string, search *types.LogSearchQuery) ([]string, error) {
+	name = p.project.ResolveProcessName(name)
+	pattern, err := regexp.Compile(search.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern %q: %w", search.Pattern, err)
+	}
+	query := &pclog.LogQuery{
+		Pattern: pattern,
+		Since:   search.Since,
+		Until:   search.Until,
+		Limit:   search.Limit,
+	}
+	p.procConfMutex.Lock()
+	procConf, ok := p.project.Processes[name]
+	p.procConfMutex.Unlock()
+	if ok && isStringDefined(procConf.LogLocation) {
+		logPath := getLogPath(&procConf)
+		if _, err = os.Stat(logPath); err == nil {
+			query.TimestampFormat = p.getLogTimestampFormat(&procConf)
+			if query.TimestampFormat == "" && procConf.LoggerConfig != nil {
+				query.TimestampFormat = procConf.LoggerConfig.TimestampFormat
+			}
+			return pclog.SearchLogFile(logPath, query)
+		}
+	}
+	logs, err := p.getProcessLog(name)
+	if err != nil {
+		return nil, err
+	}
+	return logs.Search(query), nil
+}
+
 func (p *ProjectRunner) GetProcessLogLength(name string) int {
+	name = p.project.ResolveProcessName(name)
 	logs, err := p.getProcessLog(name)
 	if err != nil {
 		return 0
@@ -554,6 +1388,7 @@ func (p *ProjectRunner) GetProcessLogLength(name string) int {
 }
 
 func (p *ProjectRunner) GetLogsAndSubscribe(name string, observer pclog.LogObserver) error {
+	name = p.project.ResolveProcessName(name)
 	logs, err := p.getProcessLog(name)
 	if err != nil {
 		log.Err(err).Msgf("can't subscribe to process %s", name)
@@ -564,6 +1399,7 @@ func (p *ProjectRunner) GetLogsAndSubscribe(name string, observer pclog.LogObser
 }
 
 func (p *ProjectRunner) UnSubscribeLogger(name string, observer pclog.LogObserver) error {
+	name = p.project.ResolveProcessName(name)
 	logs, err := p.getProcessLog(name)
 	if err != nil {
 		return err
@@ -573,6 +1409,7 @@ func (p *ProjectRunner) UnSubscribeLogger(name string, observer pclog.LogObserve
 }
 
 func (p *ProjectRunner) ScaleProcess(name string, scale int) error {
+	name = p.project.ResolveProcessName(name)
 	if scale < 1 {
 		err := fmt.Errorf("cannot scale process %s to a negative or zero value %d", name, scale)
 		log.Err(err).Msg("scale failed")
@@ -696,7 +1533,7 @@ func (p *ProjectRunner) removeProcess(name string) error {
 			log.Err(err).Msgf("failed to remove process %s", name)
 			return err
 		} else {
-			running.waitForCompletion()
+			running.waitForCompletion(context.Background())
 		}
 	}
 	return nil
@@ -716,7 +1553,7 @@ func (p *ProjectRunner) selectRunningProcesses(procList []string) error {
 		return nil
 	}
 	newProcMap := types.Processes{}
-	err := p.project.WithProcesses(procList, func(process types.ProcessConfig) error {
+	err := p.project.WithProcesses(context.Background(), procList, func(process types.ProcessConfig) error {
 		newProcMap[process.ReplicaName] = process
 		return nil
 	})
@@ -740,7 +1577,7 @@ func (p *ProjectRunner) selectRunningProcessesNoDeps(procList []string) error {
 	for name, proc := range p.project.Processes {
 		found := false
 		for _, procName := range procList {
-			if proc.Name == procName {
+			if proc.Name == p.project.ResolveProcessName(procName) {
 				found = true
 				break
 			}
@@ -816,10 +1653,13 @@ func NewProjectRunner(opts *ProjectOpts) (*ProjectRunner, error) {
 	}
 	runner := &ProjectRunner{
 		project:           opts.project,
-		mainProcess:       opts.mainProcess,
+		mainProcess:       opts.project.ResolveProcessName(opts.mainProcess),
 		mainProcessArgs:   opts.mainProcessArgs,
 		isTuiOn:           opts.isTuiOn,
 		isOrderedShutDown: opts.isOrderedShutDown,
+		isWatchMode:       opts.isWatchMode,
+		isIsolated:        opts.isIsolated,
+		startQueue:        newStartQueue(),
 		projectState: &types.ProjectState{
 			FileNames: opts.project.FileNames,
 			StartTime: time.Now(),
@@ -840,6 +1680,7 @@ func NewProjectRunner(opts *ProjectOpts) (*ProjectRunner, error) {
 	runner.projectState.ProcessNum = len(runner.project.Processes)
 	runner.init()
 	runner.ctxApp, runner.cancelAppFn = context.WithCancel(context.Background())
+	runner.ctxWait, runner.cancelWaitFn = context.WithCancel(runner.ctxApp)
 	return runner, nil
 }
 
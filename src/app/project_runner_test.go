@@ -1,9 +1,14 @@
 package app
 
 import (
+	"context"
+	"github.com/f1bonacc1/process-compose/src/command"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"reflect"
+	"runtime"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestProject_GetDependenciesOrderNames(t *testing.T) {
@@ -252,3 +257,136 @@ func TestProject_GetDependenciesOrderNames(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectRunner_GetProcessEnvironment(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project: &types.Project{
+			Processes: map[string]types.ProcessConfig{
+				"api": {
+					Name:        "api",
+					ReplicaName: "api",
+					WorkingDir:  "/tmp",
+					Environment: []string{"DATABASE_URL=postgres://localhost"},
+				},
+			},
+			Environment: []string{"GLOBAL=42"},
+			ShellConfig: shell,
+		},
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	env, dir, err := runner.GetProcessEnvironment("api")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if dir != "/tmp" {
+		t.Errorf("GetProcessEnvironment() dir = %s, want /tmp", dir)
+	}
+	for _, want := range []string{"PC_PROC_NAME=api", "GLOBAL=42", "DATABASE_URL=postgres://localhost"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("GetProcessEnvironment() env is missing %s", want)
+		}
+	}
+	if _, _, err = runner.GetProcessEnvironment("missing"); err == nil {
+		t.Errorf("GetProcessEnvironment() expected an error for a missing process")
+	}
+}
+
+func TestDescribeDependencyWait(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		state     types.ProcessState
+		want      string
+	}{
+		{
+			name:      "Running",
+			condition: types.ProcessConditionHealthy,
+			state:     types.ProcessState{Status: types.ProcessStateRunning},
+			want:      "dep is Running",
+		},
+		{
+			name:      "CompletedWrongCondition",
+			condition: types.ProcessConditionHealthy,
+			state:     types.ProcessState{Status: types.ProcessStateCompleted, ExitCode: 0},
+			want:      "dep is Completed with exit code 0 - hint: it has already completed, did you mean condition process_completed?",
+		},
+		{
+			name:      "RestartingOnFailure",
+			condition: types.ProcessConditionCompletedSuccessfully,
+			state:     types.ProcessState{Status: types.ProcessStateRestarting, ExitCode: 1},
+			want:      "dep is Restarting with exit code 1 - hint: it exited with a failure and is being restarted, it might never complete successfully",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.state
+			proc := NewProcess(
+				withProcConf(&types.ProcessConfig{Name: "dep", ReplicaName: "dep"}),
+				withProcState(&state),
+			)
+			if got := describeDependencyWait(tt.condition, proc); got != tt.want {
+				t.Errorf("describeDependencyWait() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWaitDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "0m 0s"},
+		{30 * time.Second, "0m 30s"},
+		{59*time.Second + 600*time.Millisecond, "1m 0s"},
+		{90 * time.Second, "1m 30s"},
+		{2*time.Hour + 5*time.Second, "120m 5s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatWaitDuration(tt.duration); got != tt.want {
+				t.Errorf("formatWaitDuration(%s) = %q, want %q", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectRunner_WaitForReplicas(t *testing.T) {
+	dependent := &types.ProcessConfig{
+		Name:        "api",
+		ReplicaName: "api",
+		DependsOn: types.DependsOnConfig{
+			"worker": {Condition: types.ProcessConditionStarted, ReadyReplicas: 1},
+		},
+	}
+	var replicas []*Process
+	for _, name := range []string{"worker-0", "worker-1"} {
+		replicas = append(replicas, NewProcess(
+			withProcConf(&types.ProcessConfig{Name: "worker", ReplicaName: name}),
+			withProcState(&types.ProcessState{}),
+		))
+	}
+	replicas[0].started = true
+	goroutines := runtime.NumGoroutine()
+	p := &ProjectRunner{}
+	if err := p.waitForReplicas(context.Background(), dependent, "worker", replicas); err != nil {
+		t.Fatalf("waitForReplicas() = %v, want 1 of 2 replicas to be ready", err)
+	}
+	// the wait for the replica that never starts is canceled
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("%d goroutines are still waiting for the replicas", n-goroutines)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.waitForDependency(ctx, dependent, "worker", replicas[1]); err == nil {
+		t.Errorf("waitForDependency() should fail once the context is canceled")
+	}
+}
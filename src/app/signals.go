@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"syscall"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/rs/zerolog/log"
+)
+
+// getForwardedSignals returns the project forward_signals, validated by the loader
+func (p *ProjectRunner) getForwardedSignals() []os.Signal {
+	sigs := make([]os.Signal, 0, len(p.project.ForwardSignals))
+	for _, name := range p.project.ForwardSignals {
+		num, err := command.ParseSignal(name)
+		if err != nil {
+			log.Err(err).Msgf("invalid forward signal %s", name)
+			continue
+		}
+		sigs = append(sigs, syscall.Signal(num))
+	}
+	return sigs
+}
+
+// ShutdownSignals returns the signals that shut down the project: SIGTERM, SIGINT and SIGHUP, unless it is
+// forwarded to the processes
+func (p *ProjectRunner) ShutdownSignals() []os.Signal {
+	sigs := []os.Signal{syscall.SIGTERM, os.Interrupt}
+	if !slices.Contains(p.getForwardedSignals(), os.Signal(syscall.SIGHUP)) {
+		sigs = append(sigs, syscall.SIGHUP)
+	}
+	return sigs
+}
+
+// forwardSignals sends the forward_signals received by process-compose to all the running processes, until the
+// context is done
+func (p *ProjectRunner) forwardSignals(ctx context.Context) {
+	sigs := p.getForwardedSignals()
+	if len(sigs) == 0 {
+		return
+	}
+	if runtime.GOOS == "windows" {
+		log.Warn().Msg("forward_signals isn't supported on Windows")
+		return
+	}
+	sigChan := make(chan os.Signal, len(sigs))
+	signal.Notify(sigChan, sigs...)
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case sig := <-sigChan:
+				p.signalRunningProcesses(sig.(syscall.Signal))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (p *ProjectRunner) signalRunningProcesses(sig syscall.Signal) {
+	p.runProcMutex.Lock()
+	procs := make([]*Process, 0, len(p.runningProcesses))
+	for _, proc := range p.runningProcesses {
+		procs = append(procs, proc)
+	}
+	p.runProcMutex.Unlock()
+	log.Info().Msgf("Forwarding %v to %d running processes", sig, len(procs))
+	for _, proc := range procs {
+		if !proc.isRunning() {
+			continue
+		}
+		if err := proc.command.Signal(int(sig), proc.procConf.ShutDownParams.ParentOnly); err != nil {
+			log.Err(err).Msgf("failed to forward %v to %s", sig, proc.getName())
+		}
+	}
+}
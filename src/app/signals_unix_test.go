@@ -0,0 +1,77 @@
+//go:build !windows
+
+package app
+
+import (
+	"context"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestSystem_TestForwardSignals(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	trap := "trap 'echo reloaded; exit 0' USR1; echo ready; while true; do sleep 1 & wait; done"
+	scripts := map[string]string{
+		"proc1": trap,
+		"proc2": trap,
+		// the signal is sent to the process group, so it reaches the child
+		"proc3": "trap : USR1; " + shell.ShellCommand + " -c \"" + trap + "\"",
+	}
+	procs := []string{"proc1", "proc2", "proc3"}
+	project := &types.Project{
+		Processes:      map[string]types.ProcessConfig{},
+		ForwardSignals: []string{"SIGUSR1", "HUP"},
+		ShellConfig:    shell,
+	}
+	for _, name := range procs {
+		project.Processes[name] = types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, scripts[name]},
+		}
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if slices.Contains(runner.ShutdownSignals(), os.Signal(syscall.SIGHUP)) {
+		t.Errorf("ShutdownSignals() = %v, want SIGHUP to be forwarded", runner.ShutdownSignals())
+	}
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(context.Background())
+	}()
+	defer runner.ShutDownProject()
+	for _, name := range procs {
+		deadline := time.Now().Add(5 * time.Second)
+		for !slices.Contains(getProcessLogLines(t, runner, name), "ready") {
+			if time.Now().After(deadline) {
+				t.Fatalf("process %s didn't start", name)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	if err = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("%s", err)
+	}
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the processes didn't exit on the forwarded signal")
+	}
+	for _, name := range procs {
+		if logs := getProcessLogLines(t, runner, name); !slices.Contains(logs, "reloaded") {
+			t.Errorf("process %s log = %v, want the forwarded signal trap output", name, logs)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// startQueue orders the starts of the processes whose dependencies are met by their priority: a process starts
+// once the queued processes with a higher priority have started, or won't run
+type startQueue struct {
+	mtx    sync.Mutex
+	cond   *sync.Cond
+	queued map[string]int
+}
+
+func newStartQueue() *startQueue {
+	q := &startQueue{queued: map[string]int{}}
+	q.cond = sync.NewCond(&q.mtx)
+	return q
+}
+
+// push queues the process, its dependencies are met
+func (q *startQueue) push(name string, priority int) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.queued[name] = priority
+}
+
+// waitForTurn queues the process and waits until no queued process has a higher priority, or the context is canceled
+func (q *startQueue) waitForTurn(ctx context.Context, name string, priority int) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.queued[name] = priority
+	stop := context.AfterFunc(ctx, func() {
+		q.mtx.Lock()
+		defer q.mtx.Unlock()
+		q.cond.Broadcast()
+	})
+	defer stop()
+	for len(q.getHigherPriority(priority)) > 0 && ctx.Err() == nil {
+		q.cond.Wait()
+	}
+	return ctx.Err()
+}
+
+// done removes the process that has started, or won't run, from the queue
+func (q *startQueue) done(name string) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if _, ok := q.queued[name]; !ok {
+		return
+	}
+	delete(q.queued, name)
+	q.cond.Broadcast()
+}
+
+// higherPriority returns the sorted names of the queued processes with a higher priority than the process
+func (q *startQueue) higherPriority(name string) []string {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	priority, ok := q.queued[name]
+	if !ok {
+		return nil
+	}
+	return q.getHigherPriority(priority)
+}
+
+func (q *startQueue) getHigherPriority(priority int) []string {
+	var names []string
+	for name, other := range q.queued {
+		if other > priority {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
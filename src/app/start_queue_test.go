@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStartQueue(t *testing.T) {
+	q := newStartQueue()
+	q.push("logs", 10)
+	q.push("metrics", 5)
+	if err := q.waitForTurn(context.Background(), "logs", 10); err != nil {
+		t.Fatalf("waitForTurn(logs) = %v, want the highest priority process to start", err)
+	}
+	if got, want := q.higherPriority("metrics"), []string{"logs"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("higherPriority(metrics) = %v, want %v", got, want)
+	}
+	started := make(chan error)
+	go func() {
+		started <- q.waitForTurn(context.Background(), "app", 0)
+	}()
+	select {
+	case <-started:
+		t.Fatal("app started before the higher priority processes")
+	case <-time.After(50 * time.Millisecond):
+	}
+	q.done("logs")
+	q.done("metrics")
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Errorf("waitForTurn(app) = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("app didn't start after the higher priority processes")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.push("logs", 10)
+	go func() {
+		started <- q.waitForTurn(ctx, "web", 0)
+	}()
+	cancel()
+	select {
+	case err := <-started:
+		if err == nil {
+			t.Errorf("waitForTurn(web) should fail once the context is canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waitForTurn(web) didn't return once the context was canceled")
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// RemoveProjectState removes the files written by process-compose for the project: the log files of its processes,
+// its summary file and PID files, and its state directory once it's empty. A configured state directory outside the
+// process-compose state home is never removed
+func RemoveProjectState(project *types.Project) error {
+	var files []string
+	for _, proc := range project.Processes {
+		files = append(files,
+			getLogPath(&proc),
+			resolveLogPath(proc.StdoutLogLocation, &proc),
+			resolveLogPath(proc.StderrLogLocation, &proc))
+	}
+	files = append(files, project.LogLocation, project.SummaryFile)
+	if project.CleanupPIDDir != "" {
+		pidFiles, err := filepath.Glob(filepath.Join(project.CleanupPIDDir, "*"+pidFileExt))
+		if err != nil {
+			return err
+		}
+		files = append(files, pidFiles...)
+	}
+	var errs []error
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+			continue
+		}
+		log.Debug().Msgf("Removed %s", file)
+	}
+	if project.StateDir == "" {
+		return errors.Join(errs...)
+	}
+	if !project.IsDefaultStateDir && !isWithinDir(config.GetStateHome(), project.StateDir) {
+		log.Warn().Msgf("Not removing the state directory %s, it's outside of %s", project.StateDir, config.GetStateHome())
+		return errors.Join(errs...)
+	}
+	dirs := []string{project.StateDir}
+	if project.CleanupPIDDir != "" && isWithinDir(project.StateDir, project.CleanupPIDDir) {
+		dirs = append([]string{project.CleanupPIDDir}, dirs...)
+	}
+	for _, dir := range dirs {
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Msgf("Not removing the directory %s, it has files that weren't written by process-compose", dir)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isWithinDir reports whether path is dir or one of its descendants
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
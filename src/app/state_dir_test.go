@@ -0,0 +1,64 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestRemoveProjectState(t *testing.T) {
+	tests := []struct {
+		name          string
+		isDefault     bool
+		extraFile     bool
+		wantDirExists bool
+	}{
+		{name: "default state dir", isDefault: true, wantDirExists: false},
+		{name: "default state dir with other files", isDefault: true, extraFile: true, wantDirExists: true},
+		{name: "configured state dir", isDefault: false, wantDirExists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateDir := filepath.Join(t.TempDir(), "state")
+			pidDir := filepath.Join(stateDir, "pids")
+			if err := os.MkdirAll(pidDir, 0700); err != nil {
+				t.Fatalf("%s", err)
+			}
+			summary := filepath.Join(stateDir, "summary.json")
+			pidFile := getPidFilePath(pidDir, "proc1")
+			written := []string{summary, pidFile}
+			if tt.extraFile {
+				written = append(written, filepath.Join(stateDir, "user.txt"))
+			}
+			for _, file := range written {
+				if err := os.WriteFile(file, []byte("data"), 0600); err != nil {
+					t.Fatalf("%s", err)
+				}
+			}
+			project := &types.Project{
+				StateDir:          stateDir,
+				IsDefaultStateDir: tt.isDefault,
+				SummaryFile:       summary,
+				CleanupPIDDir:     pidDir,
+			}
+			if err := RemoveProjectState(project); err != nil {
+				t.Fatalf("%s", err)
+			}
+			for _, file := range []string{summary, pidFile} {
+				if _, err := os.Stat(file); !os.IsNotExist(err) {
+					t.Errorf("expected %s to be removed", file)
+				}
+			}
+			if _, err := os.Stat(stateDir); (err == nil) != tt.wantDirExists {
+				t.Errorf("state dir exists = %v, want %v", err == nil, tt.wantDirExists)
+			}
+			if tt.extraFile {
+				if _, err := os.Stat(written[2]); err != nil {
+					t.Errorf("expected %s to be kept: %s", written[2], err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"os"
+	"slices"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// startStates are the states a process enters when it's started, or when it won't run
+var startStates = []string{
+	types.ProcessStateRunning,
+	types.ProcessStateLaunching,
+	types.ProcessStateLaunched,
+	types.ProcessStateSkipped,
+	types.ProcessStateError,
+}
+
+// processStateTransitions are the allowed transitions of each process state.
+// The end states can be started again, by a user request
+var processStateTransitions = map[string][]string{
+	types.ProcessStatePending: append(slices.Clip(startStates),
+		// stopped before it started
+		types.ProcessStateTerminating),
+	types.ProcessStateRunning: {
+		types.ProcessStateTerminating,
+		types.ProcessStateRestarting,
+		types.ProcessStateRateLimited,
+		types.ProcessStateCompleted,
+		types.ProcessStateError,
+	},
+	types.ProcessStateLaunching: {
+		types.ProcessStateLaunched,
+		types.ProcessStateTerminating,
+		types.ProcessStateRestarting,
+		types.ProcessStateRateLimited,
+		types.ProcessStateCompleted,
+		types.ProcessStateError,
+	},
+	types.ProcessStateLaunched: {
+		types.ProcessStateTerminating,
+		types.ProcessStateRestarting,
+		types.ProcessStateRateLimited,
+		types.ProcessStateCompleted,
+	},
+	types.ProcessStateRestarting: {
+		types.ProcessStateRunning,
+		types.ProcessStateLaunching,
+		types.ProcessStateTerminating,
+		types.ProcessStateCompleted,
+		types.ProcessStateError,
+	},
+	types.ProcessStateRateLimited: {
+		types.ProcessStateRestarting,
+		types.ProcessStateTerminating,
+		types.ProcessStateCompleted,
+	},
+	types.ProcessStateTerminating: append(slices.Clip(startStates),
+		types.ProcessStateRestarting,
+		types.ProcessStateRateLimited,
+		types.ProcessStateCompleted),
+	types.ProcessStateCompleted:  startStates,
+	types.ProcessStateSkipped:    startStates,
+	types.ProcessStateError:      startStates,
+	types.ProcessStateDisabled:   startStates,
+	types.ProcessStateForeground: startStates,
+}
+
+// isValidStateTransition reports whether a process can move from one state to another.
+// A process without a state yet can move to any state
+func isValidStateTransition(from, to string) bool {
+	return from == "" || from == to || slices.Contains(processStateTransitions[from], to)
+}
+
+// checkStateTransition reports an invalid transition of the process name. It is a fatal error in
+// debug mode, otherwise the transition is ignored
+func checkStateTransition(name, from, to string) bool {
+	if isValidStateTransition(from, to) {
+		return true
+	}
+	if os.Getenv(config.EnvVarDebugMode) != "" {
+		log.Fatal().Msgf("invalid state transition of %s from %s to %s", name, from, to)
+	}
+	log.Error().Msgf("ignoring an invalid state transition of %s from %s to %s", name, from, to)
+	return false
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunSummary is the JSON summary of a project run, written to summary_file
+type RunSummary struct {
+	Timestamp      string           `json:"timestamp"`
+	RuntimeSeconds float64          `json:"runtime_seconds"`
+	ExitCode       int              `json:"exit_code"`
+	Processes      []ProcessSummary `json:"processes"`
+}
+
+// ProcessSummary is the result of a single process in the RunSummary
+type ProcessSummary struct {
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	ExitCode        int     `json:"exit_code"`
+	Restarts        int     `json:"restarts"`
+	StartTime       string  `json:"start_time,omitempty"`
+	EndTime         string  `json:"end_time,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+}
+
+func newRunSummary(startTime time.Time, processes []*Process, exitCode int) *RunSummary {
+	now := time.Now()
+	summary := &RunSummary{
+		Timestamp:      now.Format(time.RFC3339),
+		RuntimeSeconds: now.Sub(startTime).Seconds(),
+		ExitCode:       exitCode,
+		Processes:      make([]ProcessSummary, 0, len(processes)),
+	}
+	for _, proc := range processes {
+		state := proc.getState()
+		procSummary := ProcessSummary{
+			Name:     proc.getName(),
+			Status:   state.Status,
+			ExitCode: state.ExitCode,
+			Restarts: state.Restarts,
+		}
+		start, end := proc.getStartTime(), proc.getEndTime()
+		if !start.IsZero() {
+			procSummary.StartTime = start.Format(time.RFC3339Nano)
+		}
+		if !end.IsZero() {
+			procSummary.EndTime = end.Format(time.RFC3339Nano)
+		}
+		if !start.IsZero() && end.After(start) {
+			procSummary.DurationSeconds = end.Sub(start).Seconds()
+		}
+		summary.Processes = append(summary.Processes, procSummary)
+	}
+	return summary
+}
+
+// writeRunSummary writes the JSON summary of the project run, once all the processes ended
+func writeRunSummary(path string, startTime time.Time, processes []*Process, exitCode int) {
+	data, err := json.MarshalIndent(newRunSummary(startTime, processes, exitCode), "", "  ")
+	if err != nil {
+		log.Err(err).Msg("failed to marshal the run summary")
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Err(err).Msgf("failed to create the run summary directory of %s", path)
+		return
+	}
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		log.Err(err).Msgf("failed to write the run summary to %s", path)
+		return
+	}
+	log.Info().Msgf("Run summary written to %s", path)
+}
+
+// ReadRunSummary reads a run summary written to summary_file
+func ReadRunSummary(path string) (*RunSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	summary := &RunSummary{}
+	if err = json.Unmarshal(data, summary); err != nil {
+		return nil, fmt.Errorf("failed to parse the run summary %s: %w", path, err)
+	}
+	return summary, nil
+}
@@ -2,17 +2,31 @@ package app
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/health"
 	"github.com/f1bonacc1/process-compose/src/loader"
 	"github.com/f1bonacc1/process-compose/src/types"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
 func getFixtures() []string {
@@ -46,7 +60,7 @@ func TestSystem_TestFixtures(t *testing.T) {
 				t.Errorf(err.Error())
 				return
 			}
-			runner.Run()
+			runner.Run(context.Background())
 		})
 	}
 }
@@ -70,7 +84,7 @@ func TestSystem_TestComposeWithLog(t *testing.T) {
 			t.Errorf(err.Error())
 			return
 		}
-		runner.Run()
+		runner.Run(context.Background())
 		if _, err := os.Stat(runner.project.LogLocation); err != nil {
 			t.Errorf("log file %s not found", runner.project.LogLocation)
 		}
@@ -147,7 +161,7 @@ func TestSystem_TestComposeChainExit(t *testing.T) {
 			t.Errorf(err.Error())
 			return
 		}
-		err = runner.Run()
+		err = runner.Run(context.Background())
 		want := "project non-zero exit code: 42"
 		if want != err.Error() {
 			t.Errorf("Project.Run() = %v, want %v", err, want)
@@ -196,7 +210,7 @@ func TestSystem_TestComposeScale(t *testing.T) {
 			t.Errorf(err.Error())
 			return
 		}
-		go runner.Run()
+		go runner.Run(context.Background())
 		time.Sleep(200 * time.Millisecond)
 		states, err := runner.GetProcessesState()
 		if err != nil {
@@ -303,7 +317,7 @@ func TestSystem_TestTransitiveDependency(t *testing.T) {
 			processesToRun:  []string{},
 			mainProcessArgs: []string{},
 		})
-		runner.Run()
+		runner.Run(context.Background())
 
 		states, err := runner.GetProcessesState()
 		for _, state := range states.States {
@@ -454,7 +468,7 @@ func TestSystem_TestProcListShutsDownInOrder(t *testing.T) {
 			FlushEachLine:   true,
 			NoColor:         true,
 		}
-		go runner.Run()
+		go runner.Run(context.Background())
 		time.Sleep(10 * time.Millisecond)
 		states, err := runner.GetProcessesState()
 		if err != nil {
@@ -538,7 +552,7 @@ func TestSystem_TestProcShutDownNoRestart(t *testing.T) {
 		t.Errorf(err.Error())
 		return
 	}
-	go runner.Run()
+	go runner.Run(context.Background())
 	time.Sleep(100 * time.Millisecond)
 	state, err := runner.GetProcessState(restarting)
 	if err != nil {
@@ -549,7 +563,7 @@ func TestSystem_TestProcShutDownNoRestart(t *testing.T) {
 		t.Errorf("process %s is not running", restarting)
 		return
 	}
-	err = runner.StopProcess(restarting)
+	err = runner.StopProcess(restarting, 0)
 	if err != nil {
 		t.Errorf(err.Error())
 		return
@@ -574,7 +588,7 @@ func TestSystem_TestProcShutDownNoRestart(t *testing.T) {
 		t.Errorf("process %s is not running", notRestarting)
 		return
 	}
-	err = runner.StopProcess(notRestarting)
+	err = runner.StopProcess(notRestarting, 0)
 	if err != nil {
 		t.Errorf(err.Error())
 		return
@@ -625,7 +639,7 @@ func TestSystem_TestReadyLine(t *testing.T) {
 		t.Errorf(err.Error())
 		return
 	}
-	go runner.Run()
+	go runner.Run(context.Background())
 	time.Sleep(100 * time.Millisecond)
 	state := runner.getRunningProcess(proc2).getStatusName()
 
@@ -677,7 +691,7 @@ func TestUpdateProject(t *testing.T) {
 		t.Errorf(err.Error())
 		return
 	}
-	go p.Run()
+	go p.Run(context.Background())
 	time.Sleep(100 * time.Millisecond)
 
 	// Test when no changes are made
@@ -847,7 +861,7 @@ func TestSystem_TestProcShutDownWithConfiguredTimeOut(t *testing.T) {
 		if err != nil {
 			t.Fatalf("%s", err)
 		}
-		go runner.Run()
+		go runner.Run(context.Background())
 		time.Sleep(100 * time.Millisecond)
 		proc := runner.getRunningProcess(ignoresSigTerm)
 		assertProcessStatus(t, proc, ignoresSigTerm, types.ProcessStateRunning)
@@ -856,7 +870,7 @@ func TestSystem_TestProcShutDownWithConfiguredTimeOut(t *testing.T) {
 		defer proc.command.Stop(int(syscall.SIGKILL), true)
 
 		go func() {
-			err = runner.StopProcess(ignoresSigTerm)
+			err = runner.StopProcess(ignoresSigTerm, 0)
 			if err != nil {
 				t.Fatalf("%s", err)
 			}
@@ -879,12 +893,12 @@ func TestSystem_TestProcShutDownWithConfiguredTimeOut(t *testing.T) {
 		if err != nil {
 			t.Fatalf("%s", err)
 		}
-		go runner.Run()
+		go runner.Run(context.Background())
 		time.Sleep(100 * time.Millisecond)
 		proc := runner.getRunningProcess(ignoresSigTerm)
 		assertProcessStatus(t, proc, ignoresSigTerm, types.ProcessStateRunning)
 		go func() {
-			err = runner.StopProcess(ignoresSigTerm)
+			err = runner.StopProcess(ignoresSigTerm, 0)
 			if err != nil {
 				t.Fatalf("%s", err)
 			}
@@ -894,3 +908,2719 @@ func TestSystem_TestProcShutDownWithConfiguredTimeOut(t *testing.T) {
 	})
 
 }
+
+func TestSystem_TestRunID(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	shell := command.DefaultShellConfig()
+	echoIDs := "echo $" + EnvRunID + " $" + EnvRestartID
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, echoIDs},
+			},
+			proc2: {
+				Name:        proc2,
+				ReplicaName: proc2,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, echoIDs},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	ids := map[string][]string{}
+	for _, name := range []string{proc1, proc2} {
+		logs, err := runner.GetProcessLog(name, 1, 0)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if len(logs) != 1 {
+			t.Fatalf("process %s: expected 1 log line, got %d", name, len(logs))
+		}
+		ids[name] = strings.Fields(logs[0])
+		if len(ids[name]) != 2 {
+			t.Fatalf("process %s: expected run and restart IDs, got %q", name, logs[0])
+		}
+	}
+	if ids[proc1][0] != runner.runID || ids[proc2][0] != runner.runID {
+		t.Errorf("expected run ID %s, got %s and %s", runner.runID, ids[proc1][0], ids[proc2][0])
+	}
+	if ids[proc1][1] == ids[proc2][1] {
+		t.Errorf("expected unique restart IDs, got %s", ids[proc1][1])
+	}
+}
+
+func TestSystem_TestAggregateLogFormat(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	logLocation := filepath.Join(t.TempDir(), "pc.log")
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo out && sleep 0.1 && echo err >&2"},
+				Replicas:    1,
+			},
+		},
+		ShellConfig: shell,
+		LogLocation: logLocation,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	file, err := os.Open(logLocation)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer file.Close()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := map[string]any{}
+		if err = json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid log line %q: %s", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+	for i, want := range []map[string]string{
+		{"stream": "stdout", "line": "out"},
+		{"stream": "stderr", "line": "err"},
+	} {
+		for k, v := range want {
+			if lines[i][k] != v {
+				t.Errorf("line %d: expected %s=%s, got %v", i, k, v, lines[i][k])
+			}
+		}
+		if lines[i]["process"] != proc1 || lines[i]["run_id"] != runner.runID {
+			t.Errorf("line %d: unexpected metadata %v", i, lines[i])
+		}
+		if _, ok := lines[i]["replica"]; ok {
+			t.Errorf("line %d: unexpected replica for a single replica process", i)
+		}
+		if _, err = time.Parse(time.RFC3339, lines[i]["ts"].(string)); err != nil {
+			t.Errorf("line %d: %s", i, err)
+		}
+	}
+}
+
+func TestSystem_TestDetachedProcess(t *testing.T) {
+	detached := "detached"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			detached: {
+				Name:        detached,
+				ReplicaName: detached,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+				Detach:      true,
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	start := time.Now()
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("project waited for the detached process to complete")
+	}
+	if runner.getRunningProcess(detached) != nil {
+		t.Errorf("detached process %s shouldn't be tracked as running", detached)
+	}
+	state, err := runner.GetProcessState(detached)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateLaunched {
+		t.Errorf("process %s is %s want %s", detached, state.Status, types.ProcessStateLaunched)
+	}
+	proc, err := os.FindProcess(state.Pid)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = proc.Signal(syscall.SIGKILL); err != nil {
+		t.Errorf("detached process %d isn't running: %s", state.Pid, err)
+	}
+}
+
+func TestSystem_TestShadowProcess(t *testing.T) {
+	linter := "linter"
+	build := "build"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			linter: {
+				Name:        linter,
+				ReplicaName: linter,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "exit 3"},
+				Shadow:      true,
+				RestartPolicy: types.RestartPolicyConfig{
+					Restart: types.RestartPolicyExitOnFailure,
+				},
+			},
+			build: {
+				Name:        build,
+				ReplicaName: build,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 0.2"},
+				DependsOn: map[string]types.ProcessDependency{
+					linter: {
+						Condition: types.ProcessConditionCompletedSuccessfully,
+					},
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Errorf("Project.Run() = %v, want nil", err)
+	}
+	state, err := runner.GetProcessState(build)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateCompleted {
+		t.Errorf("process %s is %s want %s", build, state.Status, types.ProcessStateCompleted)
+	}
+}
+
+func TestSystem_TestRunContextCancel(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_ = runner.Run(ctx)
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("project wasn't shut down on context cancellation")
+	}
+	state, err := runner.GetProcessState(proc1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateCompleted {
+		t.Errorf("process %s is %s want %s", proc1, state.Status, types.ProcessStateCompleted)
+	}
+}
+
+func TestSystem_TestMainProcessSkipped(t *testing.T) {
+	dependency := "dependency"
+	daemon := "daemon"
+	main := "main"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			dependency: {
+				Name:        dependency,
+				ReplicaName: dependency,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "exit 1"},
+			},
+			daemon: {
+				Name:        daemon,
+				ReplicaName: daemon,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+			},
+			main: {
+				Name:        main,
+				ReplicaName: main,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo main"},
+				DependsOn: map[string]types.ProcessDependency{
+					dependency: {
+						Condition: types.ProcessConditionCompletedSuccessfully,
+					},
+					daemon: {
+						Condition: types.ProcessConditionStarted,
+					},
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{
+		project:        project,
+		processesToRun: []string{main},
+		mainProcess:    main,
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	start := time.Now()
+	err = runner.Run(context.Background())
+	want := "project non-zero exit code: 1"
+	if err == nil || err.Error() != want {
+		t.Errorf("Project.Run() = %v, want %v", err, want)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("project wasn't shut down after the main process was skipped")
+	}
+}
+
+func TestSystem_TestSearchProcessLog(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	shell := command.DefaultShellConfig()
+	output := "echo 'GET /health 200' && echo 'GET /api 500' && echo 'POST /api 500'"
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, output},
+			},
+			proc2: {
+				Name:        proc2,
+				ReplicaName: proc2,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, output},
+				LogLocation: filepath.Join(t.TempDir(), "proc2.log"),
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, name := range []string{proc1, proc2} {
+		logs, err := runner.SearchProcessLog(name, &types.LogSearchQuery{Pattern: "500$"})
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		want := []string{"GET /api 500", "POST /api 500"}
+		if !reflect.DeepEqual(logs, want) {
+			t.Errorf("%s: SearchProcessLog() = %q, want %q", name, logs, want)
+		}
+		logs, err = runner.SearchProcessLog(name, &types.LogSearchQuery{Pattern: "500$", Limit: 1})
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if !reflect.DeepEqual(logs, want[1:]) {
+			t.Errorf("%s: SearchProcessLog() with limit = %q, want %q", name, logs, want[1:])
+		}
+	}
+	logs, err := runner.SearchProcessLog(proc1, &types.LogSearchQuery{Since: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(logs) != 0 {
+		t.Errorf("SearchProcessLog() since = %q, want none", logs)
+	}
+	if _, err = runner.SearchProcessLog(proc1, &types.LogSearchQuery{Pattern: "("}); err == nil {
+		t.Errorf("SearchProcessLog() expected an error for an invalid pattern")
+	}
+}
+
+func TestSystem_TestUpdateProcessConfig(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Command:     "sleep 2",
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 2"},
+				Description: "old description",
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(100 * time.Millisecond)
+	proc := runner.getRunningProcess(proc1)
+	assertProcessStatus(t, proc, proc1, types.ProcessStateRunning)
+	pid := proc.getState().Pid
+
+	procConf, err := runner.GetProcessInfo(proc1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	procConf.Description = "new description"
+	procConf.Owner = "backend-team"
+	procConf.Labels = map[string]string{"tier": "backend"}
+	procConf.MaxOutputLines = 50
+	if err = runner.UpdateProcessConfig(proc1, procConf); err != nil {
+		t.Fatalf("%s", err)
+	}
+	state, _ := runner.GetProcessState(proc1)
+	if state.Description != "new description" || state.Pid != pid {
+		t.Errorf("process %s description is %q pid %d, want %q pid %d", proc1, state.Description, state.Pid, "new description", pid)
+	}
+	if procConf, _ = runner.GetProcessInfo(proc1); procConf.Owner != "backend-team" {
+		t.Errorf("process %s owner is %q, want %q", proc1, procConf.Owner, "backend-team")
+	}
+	if procConf.Labels["tier"] != "backend" || procConf.MaxOutputLines != 50 {
+		t.Errorf("process %s labels are %v and max output lines %d, want the updated ones", proc1, procConf.Labels, procConf.MaxOutputLines)
+	}
+
+	procConf.Command = "sleep 3"
+	if err = runner.UpdateProcessConfig(proc1, procConf); err == nil {
+		t.Errorf("UpdateProcessConfig() expected an error for a command change")
+	}
+	if err = runner.UpdateProcessConfig("missing", procConf); err == nil {
+		t.Errorf("UpdateProcessConfig() expected an error for a missing process")
+	}
+}
+
+func TestSystem_TestCreateWorkingDir(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	workingDir := filepath.Join(t.TempDir(), "nested", "dir")
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:             proc1,
+				ReplicaName:      proc1,
+				Executable:       shell.ShellCommand,
+				Args:             []string{shell.ShellArgument, "pwd"},
+				WorkingDir:       workingDir,
+				CreateWorkingDir: true,
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if stat, err := os.Stat(workingDir); err != nil || !stat.IsDir() {
+		t.Errorf("working dir %s wasn't created: %v", workingDir, err)
+	}
+	state, _ := runner.GetProcessState(proc1)
+	if state.Status != types.ProcessStateCompleted || state.ExitCode != 0 {
+		t.Errorf("process %s is %s with exit code %d, want %s with 0", proc1, state.Status, state.ExitCode, types.ProcessStateCompleted)
+	}
+}
+
+func TestSystem_TestSuccessExitCodes(t *testing.T) {
+	differ := "differ"
+	build := "build"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			differ: {
+				Name:             differ,
+				ReplicaName:      differ,
+				Executable:       shell.ShellCommand,
+				Args:             []string{shell.ShellArgument, "exit 1"},
+				SuccessExitCodes: []int{0, 1},
+				RestartPolicy: types.RestartPolicyConfig{
+					Restart: types.RestartPolicyExitOnFailure,
+				},
+			},
+			build: {
+				Name:        build,
+				ReplicaName: build,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 0.2"},
+				DependsOn: map[string]types.ProcessDependency{
+					differ: {
+						Condition: types.ProcessConditionCompletedSuccessfully,
+					},
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Errorf("Project.Run() = %v, want nil", err)
+	}
+	state, err := runner.GetProcessState(build)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateCompleted {
+		t.Errorf("process %s is %s want %s", build, state.Status, types.ProcessStateCompleted)
+	}
+}
+
+func TestSystem_TestStdinFile(t *testing.T) {
+	proc1 := "proc1"
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("from stdin file\n"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "cat"},
+				WorkingDir:  dir,
+				StdinFile:   "input.txt",
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	logs, err := runner.GetProcessLog(proc1, 1, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(logs) != 1 || logs[0] != "from stdin file" {
+		t.Errorf("process %s log = %v, want [from stdin file]", proc1, logs)
+	}
+}
+
+func TestSystem_TestValidateCommand(t *testing.T) {
+	valid := "valid"
+	invalid := "invalid"
+	replicated := "replicated"
+	shell := command.DefaultShellConfig()
+	newProject := func(projectValidation string) *types.Project {
+		project := &types.Project{
+			Processes: map[string]types.ProcessConfig{
+				valid: {
+					Name:            valid,
+					ReplicaName:     valid,
+					Executable:      shell.ShellCommand,
+					Args:            []string{shell.ShellArgument, "echo started"},
+					ValidateCommand: "test -n \"$CONFIG\" && test \"$PC_PROC_NAME\" = " + valid,
+					Environment:     []string{"CONFIG=app.conf"},
+				},
+				invalid: {
+					Name:            invalid,
+					ReplicaName:     invalid,
+					Executable:      shell.ShellCommand,
+					Args:            []string{shell.ShellArgument, "echo started"},
+					ValidateCommand: "echo bad config; exit 1",
+				},
+			},
+			ValidateCommand: projectValidation,
+			ShellConfig:     shell,
+		}
+		for i := 0; i < 2; i++ {
+			name := fmt.Sprintf("%s-%d", replicated, i)
+			project.Processes[name] = types.ProcessConfig{
+				Name:            replicated,
+				ReplicaName:     name,
+				ReplicaNum:      i,
+				Replicas:        2,
+				Executable:      shell.ShellCommand,
+				Args:            []string{shell.ShellArgument, "echo started"},
+				ValidateCommand: "test \"$PC_REPLICA_NUM\" = 0",
+			}
+		}
+		return project
+	}
+
+	t.Run("Process", func(t *testing.T) {
+		runner, err := NewProjectRunner(&ProjectOpts{project: newProject("")})
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		_ = runner.Run(context.Background())
+		state, err := runner.GetProcessState(valid)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if state.Status != types.ProcessStateCompleted {
+			t.Errorf("process %s status = %s, want %s", valid, state.Status, types.ProcessStateCompleted)
+		}
+		state, err = runner.GetProcessState(invalid)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if state.Status != types.ProcessStateSkipped {
+			t.Errorf("process %s status = %s, want %s", invalid, state.Status, types.ProcessStateSkipped)
+		}
+		if reason := runner.getEndReason(invalid); !strings.Contains(reason, "bad config") {
+			t.Errorf("process %s end reason = %q, want the validation output", invalid, reason)
+		}
+		// the replicas share the validation, run with the environment of the first replica
+		for _, name := range []string{replicated + "-0", replicated + "-1"} {
+			state, err = runner.GetProcessState(name)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if state.Status != types.ProcessStateCompleted {
+				t.Errorf("process %s status = %s, want %s", name, state.Status, types.ProcessStateCompleted)
+			}
+		}
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		runner, err := NewProjectRunner(&ProjectOpts{project: newProject("echo smoke test failed; exit 1")})
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		err = runner.Run(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "smoke test failed") {
+			t.Fatalf("Run() error = %v, want the project validation error", err)
+		}
+		state, err := runner.GetProcessState(valid)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if state.Status != types.ProcessStatePending {
+			t.Errorf("process %s status = %s, want %s", valid, state.Status, types.ProcessStatePending)
+		}
+	})
+}
+
+func TestSystem_TestStdinData(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				// cat exits once stdin is closed
+				Args:      []string{shell.ShellArgument, "read answer; echo answer=$answer; cat"},
+				StdinData: "yes\nsecond line\n",
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	logs, err := runner.GetProcessLog(proc1, 2, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := []string{"answer=yes", "second line"}
+	if !reflect.DeepEqual(logs, want) {
+		t.Errorf("process %s log = %v, want %v", proc1, logs, want)
+	}
+}
+
+func TestSystem_TestReadinessMaxRetries(t *testing.T) {
+	server := "server"
+	client := "client"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			server: {
+				Name:        server,
+				ReplicaName: server,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 2"},
+				ReadinessProbe: &health.Probe{
+					Exec: &health.ExecProbe{
+						Command: "exit 1",
+					},
+					PeriodSeconds: 10,
+				},
+			},
+			client: {
+				Name:        client,
+				ReplicaName: client,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo ready"},
+				DependsOn: map[string]types.ProcessDependency{
+					server: {
+						Condition:                types.ProcessConditionHealthy,
+						ReadinessProbeInterval:   50 * time.Millisecond,
+						ReadinessProbeTimeout:    time.Second,
+						ReadinessProbeMaxRetries: 2,
+					},
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	time.Sleep(500 * time.Millisecond)
+	state, err := runner.GetProcessState(client)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateSkipped {
+		t.Errorf("process %s is %s want %s", client, state.Status, types.ProcessStateSkipped)
+	}
+	_ = runner.ShutDownProject()
+}
+
+func TestSystem_TestProcessHooks(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	dir := t.TempDir()
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "exit 3"},
+				WorkingDir:  dir,
+				OnStart:     "echo $PC_PROC_NAME > started",
+				OnStop:      "echo $PC_EXIT_CODE > stopped",
+				OnFailure:   "echo $PC_RESTART_COUNT > failed",
+			},
+			proc2: {
+				Name:        proc2,
+				ReplicaName: proc2,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "exit 3"},
+				WorkingDir:  dir,
+				OnStart:     "echo $PC_EXIT_CODE > started$PC_RESTART_COUNT",
+				RestartPolicy: types.RestartPolicyConfig{
+					Restart:     types.RestartPolicyOnFailure,
+					MaxRestarts: 1,
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	_ = runner.Run(context.Background())
+	// the restarted process has no exit code yet when it starts again
+	want := map[string]string{
+		"started":  proc1,
+		"stopped":  "3",
+		"failed":   "0",
+		"started0": "0",
+		"started1": "0",
+	}
+	for file, content := range want {
+		var got []byte
+		for i := 0; i < 50; i++ {
+			if got, err = os.ReadFile(filepath.Join(dir, file)); err == nil && len(got) > 0 {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if strings.TrimSpace(string(got)) != content {
+			t.Errorf("hook output %s = %q, want %q", file, got, content)
+		}
+	}
+}
+
+func TestSystem_TestStartedNonBlocking(t *testing.T) {
+	slow := "slow"
+	fast := "fast"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			slow: {
+				Name:        slow,
+				ReplicaName: slow,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 2"},
+			},
+			fast: {
+				Name:        fast,
+				ReplicaName: fast,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo fast"},
+				DependsOn: map[string]types.ProcessDependency{
+					slow: {
+						Condition: types.ProcessConditionStartedNonBlocking,
+					},
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	time.Sleep(500 * time.Millisecond)
+	state, err := runner.GetProcessState(fast)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateCompleted {
+		t.Errorf("process %s is %s want %s", fast, state.Status, types.ProcessStateCompleted)
+	}
+	_ = runner.ShutDownProject()
+}
+
+func TestSystem_TestGetRunningProcessNames(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes:   map[string]types.ProcessConfig{},
+		ShellConfig: shell,
+	}
+	for _, name := range []string{"proc3", "proc1", "proc2"} {
+		project.Processes[name] = types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, "sleep 2"},
+		}
+	}
+	project.Processes["done"] = types.ProcessConfig{
+		Name:        "done",
+		ReplicaName: "done",
+		Executable:  shell.ShellCommand,
+		Args:        []string{shell.ShellArgument, "echo done"},
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	time.Sleep(300 * time.Millisecond)
+	want := []string{"proc1", "proc2", "proc3"}
+	if got := runner.GetRunningProcessNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRunningProcessNames() = %v, want %v", got, want)
+	}
+	_ = runner.ShutDownProject()
+}
+
+func TestSystem_TestStdoutStderrLogLocation(t *testing.T) {
+	proc1 := "proc1"
+	dir := t.TempDir()
+	stdoutLog := filepath.Join(dir, "stdout.log")
+	stderrLog := filepath.Join(dir, "stderr.log")
+	combinedLog := filepath.Join(dir, "combined.log")
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:              proc1,
+				ReplicaName:       proc1,
+				Executable:        shell.ShellCommand,
+				Args:              []string{shell.ShellArgument, "echo to_stdout && echo to_stderr >&2"},
+				LogLocation:       combinedLog,
+				StdoutLogLocation: stdoutLog,
+				StderrLogLocation: stderrLog,
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	tests := []struct {
+		file    string
+		want    string
+		notWant string
+	}{
+		{stdoutLog, "to_stdout", "to_stderr"},
+		{stderrLog, "to_stderr", "to_stdout"},
+	}
+	for _, tt := range tests {
+		content, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if !strings.Contains(string(content), tt.want) || strings.Contains(string(content), tt.notWant) {
+			t.Errorf("log %s = %q, want only %s", tt.file, content, tt.want)
+		}
+	}
+	content, err := os.ReadFile(combinedLog)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.Contains(string(content), "to_stdout") || !strings.Contains(string(content), "to_stderr") {
+		t.Errorf("log %s = %q, want both streams", combinedLog, content)
+	}
+}
+
+func TestSystem_TestLogLineTemplate(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	logFile := filepath.Join(t.TempDir(), "proc1.log")
+	projectLogFile := filepath.Join(t.TempDir(), "project.log")
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:               proc1,
+				ReplicaName:        proc1,
+				Executable:         shell.ShellCommand,
+				Args:               []string{shell.ShellArgument, "echo to_stdout && echo to_stderr >&2"},
+				LogLocation:        logFile,
+				LogTimestampFormat: "unix",
+				LogLineTemplate:    "{{.Timestamp}} {{.Stream}} {{.Process}}/{{.ReplicaIndex}}: {{.Line}}",
+			},
+			// logs to the project log, which keeps its own format
+			proc2: {
+				Name:            proc2,
+				ReplicaName:     proc2,
+				Executable:      shell.ShellCommand,
+				Args:            []string{shell.ShellArgument, "echo proc2_stdout"},
+				LogLineTemplate: "templated {{.Line}}",
+			},
+		},
+		LogLocation: projectLogFile,
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, want := range []string{
+		`(?m)^\d+ stdout proc1/0: to_stdout$`,
+		`(?m)^\d+ stderr proc1/0: to_stderr$`,
+	} {
+		if !regexp.MustCompile(want).Match(content) {
+			t.Errorf("log %s = %q, want a line matching %s", logFile, content, want)
+		}
+	}
+	projectLog, err := os.ReadFile(projectLogFile)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.Contains(string(projectLog), "proc2_stdout") || strings.Contains(string(projectLog), "templated") {
+		t.Errorf("project log = %q, want proc2_stdout without the log_line_template", projectLog)
+	}
+}
+
+func TestSystem_TestLogTimestampFormat(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	proc3 := "proc3"
+	dir := t.TempDir()
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:               proc1,
+				ReplicaName:        proc1,
+				Executable:         shell.ShellCommand,
+				Args:               []string{shell.ShellArgument, "echo proc1"},
+				LogLocation:        filepath.Join(dir, "proc1.log"),
+				LogTimestampFormat: "unix",
+			},
+			proc2: {
+				Name:         proc2,
+				ReplicaName:  proc2,
+				Executable:   shell.ShellCommand,
+				Args:         []string{shell.ShellArgument, "echo proc2"},
+				LogLocation:  filepath.Join(dir, "proc2.log"),
+				LoggerConfig: &types.LoggerConfig{AddTimestamp: true},
+			},
+			proc3: {
+				Name:        proc3,
+				ReplicaName: proc3,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo proc3"},
+				LogLocation: filepath.Join(dir, "proc3.log"),
+			},
+		},
+		ShellConfig:        shell,
+		LogTimestampFormat: "2006-01-02",
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	now := time.Now()
+	for name, want := range map[string]any{
+		proc1: float64(now.Unix()),
+		proc2: now.Format("2006-01-02"),
+		proc3: nil,
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, name+".log"))
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		line := map[string]any{}
+		if err = json.Unmarshal(content, &line); err != nil {
+			t.Fatalf("invalid log line %q: %s", content, err)
+		}
+		got := line["time"]
+		if f, ok := got.(float64); ok {
+			if wantF := want.(float64); wantF-f > 2 || f > wantF {
+				t.Errorf("process %s timestamp = %v, want about %v", name, got, want)
+			}
+		} else if got != want {
+			t.Errorf("process %s timestamp = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSystem_TestKillSignal(t *testing.T) {
+	proc1 := "proc1"
+	dir := t.TempDir()
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args: []string{shell.ShellArgument,
+					"trap '' TERM; trap 'echo killed > killed.txt; exit 0' QUIT; while true; do sleep 0.1; done"},
+				WorkingDir: dir,
+				ShutDownParams: types.ShutDownParams{
+					Signal:          int(syscall.SIGTERM),
+					ShutDownTimeout: 1,
+					KillSignal:      "SIGQUIT",
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	time.Sleep(300 * time.Millisecond)
+	if err = runner.StopProcess(proc1, 0); err != nil {
+		t.Fatalf("%s", err)
+	}
+	var content []byte
+	for i := 0; i < 50; i++ {
+		if content, err = os.ReadFile(filepath.Join(dir, "killed.txt")); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if strings.TrimSpace(string(content)) != "killed" {
+		t.Errorf("process %s wasn't killed with the kill signal", proc1)
+	}
+}
+
+func TestSystem_TestStopProcessWait(t *testing.T) {
+	proc1 := "proc1"
+	ignoresSigTerm := "ignoresSigTerm"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+			},
+			ignoresSigTerm: {
+				Name:        ignoresSigTerm,
+				ReplicaName: ignoresSigTerm,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "trap '' TERM; sleep 10"},
+				ShutDownParams: types.ShutDownParams{
+					ShutDownTimeout: 2,
+				},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(200 * time.Millisecond)
+	if err = runner.StopProcess(proc1, 5*time.Second); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.StartProcess(proc1); err != nil {
+		t.Fatalf("start after stop: %s", err)
+	}
+	if err = runner.StopProcess(ignoresSigTerm, 200*time.Millisecond); err == nil {
+		t.Errorf("expected %s to exceed the stop timeout", ignoresSigTerm)
+	}
+}
+
+func TestSystem_TestWatchPaths(t *testing.T) {
+	proc1 := "proc1"
+	dir := t.TempDir()
+	watched := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(watched, []byte("v1"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:          proc1,
+				ReplicaName:   proc1,
+				Executable:    shell.ShellCommand,
+				Args:          []string{shell.ShellArgument, "cat main.go && echo && sleep 10"},
+				WorkingDir:    dir,
+				WatchPaths:    []string{"main.go"},
+				WatchDebounce: 100 * time.Millisecond,
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(200 * time.Millisecond)
+	if err = os.WriteFile(filepath.Join(dir, "other.go"), []byte("ignored"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = os.WriteFile(watched, []byte("v2"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	var lines []string
+	for i := 0; i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		lines, err = runner.GetProcessLog(proc1, 10, 0)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if len(lines) == 2 {
+			break
+		}
+	}
+	want := []string{"v1", "v2"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", proc1, lines, want)
+	}
+}
+
+func TestSystem_TestWatchModeRestartsDependents(t *testing.T) {
+	dir := t.TempDir()
+	watched := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(watched, []byte("v1"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	shell := command.DefaultShellConfig()
+	newProc := func(name string, deps types.DependsOnConfig) types.ProcessConfig {
+		return types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, "echo started && sleep 10"},
+			DependsOn:   deps,
+		}
+	}
+	server := newProc("server", nil)
+	server.WorkingDir = dir
+	server.WatchPaths = []string{"main.go"}
+	server.WatchDebounce = 100 * time.Millisecond
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"server": server,
+			"client": newProc("client", types.DependsOnConfig{
+				"server": {Condition: types.ProcessConditionStarted},
+			}),
+			"other": newProc("other", nil),
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project, isWatchMode: true})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(200 * time.Millisecond)
+	if err = os.WriteFile(watched, []byte("v2"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	var lines []string
+	for i := 0; i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		lines, err = runner.GetProcessLog("client", 10, 0)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if len(lines) == 2 {
+			break
+		}
+	}
+	if len(lines) != 2 {
+		t.Errorf("expected the dependent client to be restarted, got log %v", lines)
+	}
+	if lines, _ = runner.GetProcessLog("other", 10, 0); len(lines) != 1 {
+		t.Errorf("expected the independent process to keep running, got log %v", lines)
+	}
+}
+
+func TestSystem_TestEnvFromProcess(t *testing.T) {
+	setup := "setup"
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			setup: {
+				Name:        setup,
+				ReplicaName: setup,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo generating && echo DB_URL=postgres://db TOKEN=42"},
+			},
+			proc1: {
+				Name:           proc1,
+				ReplicaName:    proc1,
+				Executable:     shell.ShellCommand,
+				Args:           []string{shell.ShellArgument, "echo $DB_URL $TOKEN"},
+				EnvFromProcess: setup,
+				DependsOn: types.DependsOnConfig{
+					setup: {Condition: types.ProcessConditionCompletedSuccessfully},
+				},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines, err := runner.GetProcessLog(proc1, 1, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := []string{"postgres://db 42"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", proc1, lines, want)
+	}
+
+	// a restarted dependent still gets the env of the ended dependency
+	if err = runner.StartProcess(proc1); err != nil {
+		t.Fatalf("%s", err)
+	}
+	runner.waitGroup.Wait()
+	lines, err = runner.GetProcessLog(proc1, 1, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("restarted process %s log = %v, want %v", proc1, lines, want)
+	}
+}
+
+func TestSystem_TestKeepStdinOpen(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:          proc1,
+				ReplicaName:   proc1,
+				Executable:    shell.ShellCommand,
+				Args:          []string{shell.ShellArgument, "read line && echo got $line"},
+				KeepStdinOpen: true,
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project, isTuiOn: true})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(200 * time.Millisecond)
+	if err = runner.WriteProcessStdin(proc1, "hello"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	var lines []string
+	for i := 0; i < 50; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if lines, err = runner.GetProcessLog(proc1, 1, 0); err == nil && len(lines) == 1 {
+			break
+		}
+	}
+	want := []string{"got hello"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", proc1, lines, want)
+	}
+}
+
+func TestSystem_TestReloadProcess(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	// the sleep also receives the signal sent to the process group, it runs in the background so its termination isn't logged
+	trap := "trap 'echo reloaded' USR1; while true; do sleep 1 & wait; done"
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{
+			name:   "Process",
+			script: trap,
+		},
+		{
+			name:   "ProcessGroup",
+			script: "trap : USR1; " + shell.ShellCommand + " -c \"" + trap + "\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &types.Project{
+				Processes: map[string]types.ProcessConfig{
+					proc1: {
+						Name:         proc1,
+						ReplicaName:  proc1,
+						Executable:   shell.ShellCommand,
+						Args:         []string{shell.ShellArgument, tt.script},
+						ReloadSignal: "SIGUSR1",
+					},
+				},
+				ShellConfig: shell,
+				LogLength:   10,
+			}
+			runner, err := NewProjectRunner(&ProjectOpts{project: project})
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			go runner.Run(context.Background())
+			defer runner.ShutDownProject()
+			time.Sleep(200 * time.Millisecond)
+			if err = runner.ReloadProcess(proc1); err != nil {
+				t.Fatalf("%s", err)
+			}
+			var lines []string
+			for i := 0; i < 50; i++ {
+				time.Sleep(20 * time.Millisecond)
+				if lines, err = runner.GetProcessLog(proc1, 1, 0); err == nil && len(lines) == 1 {
+					break
+				}
+			}
+			want := []string{"reloaded"}
+			if !reflect.DeepEqual(lines, want) {
+				t.Errorf("process %s log = %v, want %v", proc1, lines, want)
+			}
+			state, err := runner.GetProcessState(proc1)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if state.Status != types.ProcessStateRunning {
+				t.Errorf("process %s status = %s, want %s", proc1, state.Status, types.ProcessStateRunning)
+			}
+		})
+	}
+}
+
+func TestSystem_TestInputFrom(t *testing.T) {
+	producer := "producer"
+	consumer := "consumer"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			producer: {
+				Name:        producer,
+				ReplicaName: producer,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo first && echo second"},
+			},
+			consumer: {
+				Name:        consumer,
+				ReplicaName: consumer,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "while read line; do echo got $line; done; echo eof"},
+				InputFrom:   producer,
+				DependsOn: types.DependsOnConfig{
+					producer: {Condition: types.ProcessConditionStarted},
+				},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines, err := runner.GetProcessLog(consumer, 3, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := []string{"got first", "got second", "eof"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", consumer, lines, want)
+	}
+
+	// started again without its producer, the consumer doesn't run
+	if err = runner.StartProcess(consumer); err != nil {
+		t.Fatalf("%s", err)
+	}
+	runner.waitGroup.Wait()
+	state, err := runner.GetProcessState(consumer)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateSkipped {
+		t.Errorf("process %s is %s, want %s", consumer, state.Status, types.ProcessStateSkipped)
+	}
+}
+
+func TestSystem_TestInputFromReplicas(t *testing.T) {
+	producer := "producer"
+	consumer := "consumer"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes:   map[string]types.ProcessConfig{},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("%s-%d", producer, i)
+		project.Processes[name] = types.ProcessConfig{
+			Name:        producer,
+			ReplicaName: name,
+			ReplicaNum:  i,
+			Replicas:    2,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, "echo line from " + name},
+		}
+	}
+	project.Processes[consumer] = types.ProcessConfig{
+		Name:        consumer,
+		ReplicaName: consumer,
+		Executable:  shell.ShellCommand,
+		Args:        []string{shell.ShellArgument, "while read line; do echo got $line; done"},
+		InputFrom:   producer,
+		DependsOn: types.DependsOnConfig{
+			producer: {Condition: types.ProcessConditionStarted},
+		},
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines, err := runner.GetProcessLog(consumer, 2, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines = slices.Clone(lines)
+	slices.Sort(lines)
+	want := []string{"got line from producer-0", "got line from producer-1"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", consumer, lines, want)
+	}
+}
+
+func TestSystem_TestBootstrapCommand(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo $TOKEN $REGION"},
+			},
+		},
+		BootstrapCommand: "echo '# secrets' && echo TOKEN=42 && echo export REGION=eu-west-1",
+		ShellConfig:      shell,
+		LogLength:        10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines, err := runner.GetProcessLog(proc1, 1, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := []string{"42 eu-west-1"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", proc1, lines, want)
+	}
+
+	project.Environment = nil
+	project.CliEnvVars = []string{"TOKEN=cli"}
+	runner, err = NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines, err = runner.GetProcessLog(proc1, 1, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want = []string{"cli eu-west-1"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log with a command line variable = %v, want %v", proc1, lines, want)
+	}
+
+	project.BootstrapCommand = "exit 1"
+	runner, err = NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err == nil {
+		t.Errorf("expected the failed bootstrap command to fail the project")
+	}
+}
+
+func TestSystem_TestBootstrapCommandEnvSchema(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	newProject := func(bootstrapCommand string) *types.Project {
+		return &types.Project{
+			Processes: map[string]types.ProcessConfig{
+				proc1: {
+					Name:        proc1,
+					ReplicaName: proc1,
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "echo $PC_TEST_TOKEN $PC_TEST_PORT"},
+					EnvSchema: map[string]types.EnvVarSpec{
+						"PC_TEST_TOKEN": {Type: types.EnvVarTypeInt, Required: true},
+						"PC_TEST_PORT":  {Type: types.EnvVarTypeInt, Default: "80"},
+					},
+				},
+			},
+			BootstrapCommand: bootstrapCommand,
+			ShellConfig:      shell,
+			LogLength:        10,
+		}
+	}
+	tests := []struct {
+		name             string
+		bootstrapCommand string
+		wantErr          bool
+		wantLog          []string
+	}{
+		{
+			name:             "Defined by the bootstrap",
+			bootstrapCommand: "echo PC_TEST_TOKEN=42",
+			wantLog:          []string{"42 80"},
+		},
+		{
+			name:             "Default overridden by the bootstrap",
+			bootstrapCommand: "echo PC_TEST_TOKEN=42 && echo PC_TEST_PORT=8080",
+			wantLog:          []string{"42 8080"},
+		},
+		{
+			name:             "Invalid type",
+			bootstrapCommand: "echo PC_TEST_TOKEN=secret",
+			wantErr:          true,
+		},
+		{
+			name:             "Missing required",
+			bootstrapCommand: "echo PC_TEST_OTHER=1",
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, err := NewProjectRunner(&ProjectOpts{project: newProject(tt.bootstrapCommand)})
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			err = runner.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			lines, err := runner.GetProcessLog(proc1, 1, 0)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLog) {
+				t.Errorf("process %s log = %v, want %v", proc1, lines, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestSystem_TestDeadlockTimeout(t *testing.T) {
+	blocker := "blocker"
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			blocker: {
+				Name:        blocker,
+				ReplicaName: blocker,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+			},
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo proc1"},
+				DependsOn: types.DependsOnConfig{
+					blocker: {Condition: types.ProcessConditionCompleted},
+				},
+			},
+		},
+		DeadlockTimeout: 300 * time.Millisecond,
+		ShellConfig:     shell,
+		LogLength:       10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	start := time.Now()
+	err = runner.Run(context.Background())
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Errorf("expected the deadlocked project to exit with code 1, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the deadlock to abort the project, it ran for %v", elapsed)
+	}
+}
+
+func TestSystem_TestDescribeDependencyChain(t *testing.T) {
+	blocker := "blocker"
+	mid := "mid"
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	completed := types.ProcessDependency{Condition: types.ProcessConditionCompleted}
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			blocker: {
+				Name:        blocker,
+				ReplicaName: blocker,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+			},
+			mid: {
+				Name:        mid,
+				ReplicaName: mid,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo mid"},
+				DependsOn:   types.DependsOnConfig{blocker: completed},
+			},
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo proc1"},
+				DependsOn:   types.DependsOnConfig{mid: completed, blocker: completed},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	for i := 0; i < 50; i++ {
+		if proc := runner.getRunningProcess(blocker); proc != nil && proc.getStatusName() == types.ProcessStateRunning {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name string
+		proc string
+		want string
+	}{
+		{
+			name: "no dependencies",
+			proc: blocker,
+			want: "nothing",
+		},
+		{
+			name: "single hop",
+			proc: mid,
+			want: "blocker (process_completed, Running)",
+		},
+		{
+			name: "multi hop",
+			proc: proc1,
+			want: "blocker (process_completed, Running), mid (process_completed, Pending) -> [blocker (process_completed, Running)]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runner.describeDependencyChain(tt.proc, map[string]bool{tt.proc: true})
+			if got != tt.want {
+				t.Errorf("describeDependencyChain(%s) = %q, want %q", tt.proc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystem_TestReadyReplicas(t *testing.T) {
+	worker := "worker"
+	some := "some"
+	all := "all"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes:   map[string]types.ProcessConfig{},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	for i := 0; i < 2; i++ {
+		proc := types.ProcessConfig{
+			Name:       worker,
+			Replicas:   2,
+			ReplicaNum: i,
+			Executable: shell.ShellCommand,
+			Args:       []string{shell.ShellArgument, "exit $" + EnvReplicaNum},
+		}
+		proc.ReplicaName = proc.CalculateReplicaName()
+		project.Processes[proc.ReplicaName] = proc
+	}
+	for name, readyReplicas := range map[string]int{some: 1, all: 0} {
+		project.Processes[name] = types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, "echo " + name},
+			DependsOn: types.DependsOnConfig{
+				worker: {
+					Condition:     types.ProcessConditionCompletedSuccessfully,
+					ReadyReplicas: readyReplicas,
+				},
+			},
+		}
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	_ = runner.Run(context.Background())
+	if state, err := runner.GetProcessState(some); err != nil || state.ExitCode != 0 || state.Status != types.ProcessStateCompleted {
+		t.Errorf("expected %s to run after 1 of 2 replicas completed, got %+v, %v", some, state, err)
+	}
+	if state, err := runner.GetProcessState(all); err != nil || state.Status == types.ProcessStateCompleted {
+		t.Errorf("expected %s not to run since a replica failed, got %+v, %v", all, state, err)
+	}
+}
+
+func TestSystem_TestSummaryFile(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	shell := command.DefaultShellConfig()
+	summaryFile := filepath.Join(t.TempDir(), "summary.json")
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "exit 0"},
+			},
+			proc2: {
+				Name:        proc2,
+				ReplicaName: proc2,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "exit 3"},
+			},
+		},
+		SummaryFile: summaryFile,
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	_ = runner.Run(context.Background())
+	data, err := os.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatalf("failed to read the summary: %v", err)
+	}
+	var summary RunSummary
+	if err = json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("failed to parse the summary: %v", err)
+	}
+	if len(summary.Processes) != 2 {
+		t.Fatalf("expected 2 processes in the summary, got %+v", summary.Processes)
+	}
+	for _, proc := range summary.Processes {
+		want := map[string]int{proc1: 0, proc2: 3}[proc.Name]
+		if proc.ExitCode != want || proc.Status != types.ProcessStateCompleted {
+			t.Errorf("expected %s to complete with %d, got %+v", proc.Name, want, proc)
+		}
+		if proc.StartTime == "" || proc.EndTime == "" {
+			t.Errorf("expected %s start and end times, got %+v", proc.Name, proc)
+		}
+	}
+}
+
+func TestSystem_TestEnvRequired(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo $DB_URL"},
+				Environment: []string{"DB_URL=postgres://db"},
+				EnvRequired: []string{"DB_URL"},
+			},
+			proc2: {
+				Name:        proc2,
+				ReplicaName: proc2,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo $PC_TEST_MISSING_VAR"},
+				Environment: []string{"PC_TEST_EMPTY_VAR="},
+				EnvRequired: []string{"PC_TEST_MISSING_VAR", "PC_TEST_EMPTY_VAR"},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	state, err := runner.GetProcessState(proc1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateCompleted {
+		t.Errorf("process %s is %s want %s", proc1, state.Status, types.ProcessStateCompleted)
+	}
+	state, err = runner.GetProcessState(proc2)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateSkipped {
+		t.Errorf("process %s is %s want %s", proc2, state.Status, types.ProcessStateSkipped)
+	}
+}
+
+func TestSystem_TestWorstExitCode(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	newProject := func(commands map[string]string) *types.Project {
+		project := &types.Project{
+			Processes:   map[string]types.ProcessConfig{},
+			ShellConfig: shell,
+			LogLength:   10,
+		}
+		for name, cmd := range commands {
+			project.Processes[name] = types.ProcessConfig{
+				Name:        name,
+				ReplicaName: name,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, cmd},
+			}
+		}
+		return project
+	}
+	tests := []struct {
+		name     string
+		commands map[string]string
+		want     int
+	}{
+		{
+			name:     "All Succeeded",
+			commands: map[string]string{"proc1": "exit 0", "proc2": "exit 0"},
+			want:     0,
+		},
+		{
+			name:     "Worst Failure",
+			commands: map[string]string{"proc1": "exit 2", "proc2": "exit 7", "proc3": "exit 0"},
+			want:     7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, err := NewProjectRunner(&ProjectOpts{project: newProject(tt.commands)})
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			err = runner.Run(context.Background())
+			got := 0
+			var exitErr *ExitError
+			if errors.As(err, &exitErr) {
+				got = exitErr.Code
+			} else if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if got != tt.want {
+				t.Errorf("Run() exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystem_TestLivenessProbeRestart(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxRestarts    int
+		backoffSeconds int
+	}{
+		{name: "single restart", maxRestarts: 1},
+		{name: "restarts with backoff", maxRestarts: 2, backoffSeconds: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc1 := "proc1"
+			shell := command.DefaultShellConfig()
+			project := &types.Project{
+				Processes: map[string]types.ProcessConfig{
+					proc1: {
+						Name:        proc1,
+						ReplicaName: proc1,
+						Executable:  shell.ShellCommand,
+						Args:        []string{shell.ShellArgument, "sleep 10"},
+						RestartPolicy: types.RestartPolicyConfig{
+							Restart:        types.RestartPolicyOnFailure,
+							MaxRestarts:    tt.maxRestarts,
+							BackoffSeconds: tt.backoffSeconds,
+						},
+						LivenessProbe: &health.Probe{
+							Exec:             &health.ExecProbe{Command: "exit 1"},
+							PeriodSeconds:    1,
+							FailureThreshold: 1,
+						},
+					},
+				},
+				ShellConfig: shell,
+				LogLength:   10,
+			}
+			runner, err := NewProjectRunner(&ProjectOpts{project: project})
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			start := time.Now()
+			_ = runner.Run(context.Background())
+			elapsed := time.Since(start)
+			if elapsed > 8*time.Second {
+				t.Errorf("process %s wasn't stopped by its liveness probe, ran for %v", proc1, elapsed)
+			}
+			if backoff := time.Duration(tt.maxRestarts*tt.backoffSeconds) * time.Second; elapsed < backoff {
+				t.Errorf("process %s ran for %v, want at least the %v restarts backoff", proc1, elapsed, backoff)
+			}
+			state, err := runner.GetProcessState(proc1)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if state.Restarts != tt.maxRestarts {
+				t.Errorf("process %s restarts = %d, want %d", proc1, state.Restarts, tt.maxRestarts)
+			}
+		})
+	}
+}
+
+func TestSystem_TestMaskSecrets(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo token=$API_TOKEN url=$DB_URL port=$PORT"},
+				Environment: []string{"API_TOKEN=s3cr3t-t0ken", "DB_URL=postgres://user:pass@db", "PORT=8080"},
+			},
+		},
+		SecretEnvVars: []string{"DB_URL"},
+		ShellConfig:   shell,
+		LogLength:     10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	lines, err := runner.GetProcessLog(proc1, 1, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := []string{"token=[REDACTED] url=[REDACTED] port=8080"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("process %s log = %v, want %v", proc1, lines, want)
+	}
+}
+
+func TestSystem_TestLogAppend(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	runProject := func(logFile string, logAppend bool) {
+		project := &types.Project{
+			Processes: map[string]types.ProcessConfig{
+				proc1: {
+					Name:        proc1,
+					ReplicaName: proc1,
+					Executable:  shell.ShellCommand,
+					Args:        []string{shell.ShellArgument, "echo hello"},
+					LogLocation: logFile,
+					LogAppend:   &logAppend,
+				},
+			},
+			ShellConfig: shell,
+			LogLength:   10,
+		}
+		runner, err := NewProjectRunner(&ProjectOpts{project: project})
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if err = runner.Run(context.Background()); err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+	for _, logAppend := range []bool{false, true} {
+		logFile := filepath.Join(t.TempDir(), "proc1.log")
+		runProject(logFile, logAppend)
+		runProject(logFile, logAppend)
+		data, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		wantRuns := 1
+		if logAppend {
+			wantRuns = 2
+		}
+		if got := strings.Count(string(data), "hello"); got != wantRuns {
+			t.Errorf("log_append %v: log has %d runs, want %d:\n%s", logAppend, got, wantRuns, data)
+		}
+		wantSeparators := 0
+		if logAppend {
+			wantSeparators = 2
+		}
+		if got := strings.Count(string(data), "proc1 started at"); got != wantSeparators {
+			t.Errorf("log_append %v: log has %d separators, want %d:\n%s", logAppend, got, wantSeparators, data)
+		}
+	}
+}
+
+func TestSystem_TestKillProject(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "trap '' TERM; sleep 60"},
+				ShutDownParams: types.ShutDownParams{
+					ShutDownTimeout: 60,
+					Signal:          int(syscall.SIGTERM),
+				},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if got := runner.GetGracePeriod(); got != DefaultGracePeriodSec*time.Second {
+		t.Errorf("default grace period is %v, want %v", got, DefaultGracePeriodSec*time.Second)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		_ = runner.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	time.Sleep(500 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatalf("project stopped before %s was killed", proc1)
+	default:
+	}
+	runner.KillProject()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("project didn't stop after %s was killed", proc1)
+	}
+}
+
+func TestSystem_TestKillProjectBeforeShutdown(t *testing.T) {
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "trap '' TERM; sleep 60"},
+				ShutDownParams: types.ShutDownParams{
+					ShutDownTimeout: 60,
+					Signal:          int(syscall.SIGTERM),
+				},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = runner.Run(context.Background())
+		close(done)
+	}()
+	time.Sleep(500 * time.Millisecond)
+	// a second signal can arrive before the shutdown has started
+	runner.KillProject()
+	go runner.ShutDownProject()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("project didn't stop, %s wasn't killed", proc1)
+	}
+}
+
+func TestSystem_TestExplainProcess(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	newProc := func(name, cmd string, deps types.DependsOnConfig) types.ProcessConfig {
+		return types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, cmd},
+			DependsOn:   deps,
+		}
+	}
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"failing": newProc("failing", "exit 1", nil),
+			"skipped": newProc("skipped", "echo skipped", types.DependsOnConfig{
+				"failing": {Condition: types.ProcessConditionCompletedSuccessfully},
+			}),
+			"server": newProc("server", "sleep 10", nil),
+			"pending": newProc("pending", "echo pending", types.DependsOnConfig{
+				"server": {Condition: types.ProcessConditionCompleted},
+			}),
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(500 * time.Millisecond)
+
+	explanations := map[string]string{
+		"skipped": "Process skipped is Skipped because process skipped depended on failing to complete successfully, but it exited with status 1",
+		"failing": "Process failing Completed with exit code 1, which is a failure",
+		"pending": "Process pending is Pending, waiting for its dependencies: server to be process_completed (server is Running for",
+		"server":  "Process server is Running for",
+	}
+	for name, want := range explanations {
+		got, err := runner.ExplainProcess(name)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if !strings.HasPrefix(got, want) {
+			t.Errorf("ExplainProcess(%s) = %q, want it to start with %q", name, got, want)
+		}
+	}
+	if _, err = runner.ExplainProcess("missing"); err == nil {
+		t.Errorf("expected an error for a missing process")
+	}
+}
+
+func TestSystem_TestUnhealthyCriticalProcesses(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	newProc := func(name, cmd string, critical bool) types.ProcessConfig {
+		return types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, cmd},
+			Critical:    critical,
+		}
+	}
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"server":    newProc("server", "sleep 10", true),
+			"migration": newProc("migration", "exit 0", true),
+			"failing":   newProc("failing", "exit 1", true),
+			"optional":  newProc("optional", "exit 1", false),
+			"disabled":  newProc("disabled", "exit 0", true),
+			"console":   newProc("console", "exit 0", true),
+			"waiting":   newProc("waiting", "exit 0", true),
+			"skipped":   newProc("skipped", "exit 0", true),
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	waiting := project.Processes["waiting"]
+	waiting.DependsOn = types.DependsOnConfig{"server": {Condition: types.ProcessConditionCompleted}}
+	project.Processes["waiting"] = waiting
+	skipped := project.Processes["skipped"]
+	skipped.DependsOn = types.DependsOnConfig{"failing": {Condition: types.ProcessConditionCompletedSuccessfully}}
+	project.Processes["skipped"] = skipped
+	disabled := project.Processes["disabled"]
+	disabled.Disabled = true
+	project.Processes["disabled"] = disabled
+	console := project.Processes["console"]
+	console.IsForeground = true
+	project.Processes["console"] = console
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	time.Sleep(500 * time.Millisecond)
+
+	unhealthy, err := GetUnhealthyCriticalProcesses(runner)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	// the pending process isn't unhealthy while it waits for its dependency
+	want := map[string]string{"failing": "Completed with exit code 1", "skipped": types.ProcessStateSkipped}
+	if !reflect.DeepEqual(unhealthy, want) {
+		t.Errorf("GetUnhealthyCriticalProcesses() = %v, want %v", unhealthy, want)
+	}
+}
+
+func TestSystem_TestRestartNotification(t *testing.T) {
+	restarts := make(chan webhookRestart, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var restart webhookRestart
+		if err := json.NewDecoder(r.Body).Decode(&restart); err != nil {
+			t.Errorf("failed to decode the webhook body: %v", err)
+		}
+		if restart.Event == webhookEventRestart {
+			restarts <- restart
+		}
+	}))
+	defer server.Close()
+
+	proc1 := "proc1"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "for i in 1 2 3 4 5 6 7 8 9 10 11 12; do echo line$i; done; exit 3"},
+				RestartPolicy: types.RestartPolicyConfig{
+					Restart:     types.RestartPolicyOnFailure,
+					MaxRestarts: 1,
+				},
+				OutputWebhook: server.URL,
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   100,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+
+	select {
+	case restart := <-restarts:
+		if restart.Process != proc1 || restart.Restarts != 1 || restart.ExitCode != 3 || restart.Uptime == "" {
+			t.Errorf("unexpected restart notification %+v", restart)
+		}
+		if len(restart.LastLines) != restartContextLines || restart.LastLines[0] != "line3" ||
+			restart.LastLines[restartContextLines-1] != "line12" {
+			t.Errorf("expected the last %d output lines, got %q", restartContextLines, restart.LastLines)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the restart notification wasn't delivered")
+	}
+}
+
+func TestSystem_TestIsolatedProcess(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process isolation is only supported on Linux")
+	}
+	shell := command.DefaultShellConfig()
+	newProc := func(name string, isolated bool) types.ProcessConfig {
+		return types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, "echo pid=$$"},
+			Isolated:    isolated,
+		}
+	}
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"isolated": newProc("isolated", true),
+			"shared":   newProc("shared", false),
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	err = runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	state, err := runner.GetProcessState("isolated")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.ExitCode != 0 {
+		t.Skipf("the namespaces can't be created here: %v", getProcessLogLines(t, runner, "isolated"))
+	}
+	if logs := getProcessLogLines(t, runner, "isolated"); !slices.Contains(logs, "pid=1") {
+		t.Errorf("expected the isolated process to be PID 1 of its namespace, got %q", logs)
+	}
+	if logs := getProcessLogLines(t, runner, "shared"); slices.Contains(logs, "pid=1") {
+		t.Errorf("expected the shared process to run in the host PID namespace, got %q", logs)
+	}
+}
+
+func getProcessLogLines(t *testing.T, runner *ProjectRunner, name string) []string {
+	logs, err := runner.GetProcessLog(name, 10, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return logs
+}
+
+func TestSystem_TestDependencyAttemptLimit(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	for _, tt := range []struct {
+		attemptLimit int
+		restart      string
+		wantStatus   string
+		wantErr      bool
+	}{
+		{attemptLimit: 3, restart: types.RestartPolicyNo, wantStatus: types.ProcessStateCompleted},
+		{attemptLimit: 2, restart: types.RestartPolicyNo, wantStatus: types.ProcessStateSkipped, wantErr: true},
+		{attemptLimit: 3, restart: types.RestartPolicyOnFailure, wantStatus: types.ProcessStateCompleted},
+	} {
+		t.Run(fmt.Sprintf("attempt_limit=%d,restart=%s", tt.attemptLimit, tt.restart), func(t *testing.T) {
+			counter := filepath.Join(t.TempDir(), "attempts")
+			dependency := types.ProcessDependency{
+				Condition:    types.ProcessConditionCompletedSuccessfully,
+				AttemptLimit: tt.attemptLimit,
+				RetryDelay:   50 * time.Millisecond,
+			}
+			project := &types.Project{
+				Processes: map[string]types.ProcessConfig{
+					"migrate": {
+						Name:        "migrate",
+						ReplicaName: "migrate",
+						Executable:  shell.ShellCommand,
+						// fails twice, then succeeds
+						Args: []string{shell.ShellArgument, fmt.Sprintf(
+							`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; [ $n -ge 3 ]`, counter)},
+						RestartPolicy: types.RestartPolicyConfig{Restart: tt.restart},
+					},
+					"app": {
+						Name:        "app",
+						ReplicaName: "app",
+						Executable:  shell.ShellCommand,
+						Args:        []string{shell.ShellArgument, "echo migrated"},
+						DependsOn:   types.DependsOnConfig{"migrate": dependency},
+					},
+					"worker": {
+						Name:        "worker",
+						ReplicaName: "worker",
+						Executable:  shell.ShellCommand,
+						Args:        []string{shell.ShellArgument, "echo migrated"},
+						DependsOn:   types.DependsOnConfig{"migrate": dependency},
+					},
+				},
+				ShellConfig: shell,
+				LogLength:   10,
+			}
+			runner, err := NewProjectRunner(&ProjectOpts{project: project})
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			// only the last attempt of migrate counts in the project exit code
+			if err = runner.Run(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			for _, name := range []string{"app", "worker"} {
+				state, err := runner.GetProcessState(name)
+				if err != nil {
+					t.Fatalf("%s", err)
+				}
+				if state.Status != tt.wantStatus {
+					t.Errorf("expected %s to be %s, got %s", name, tt.wantStatus, state.Status)
+				}
+			}
+			data, err := os.ReadFile(counter)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if attempts := strings.TrimSpace(string(data)); attempts != strconv.Itoa(min(tt.attemptLimit, 3)) {
+				t.Errorf("expected %d attempts of migrate, got %s", min(tt.attemptLimit, 3), attempts)
+			}
+		})
+	}
+}
+
+func TestSystem_TestBlockOutputBuffering(t *testing.T) {
+	proc1 := "proc1"
+	proc2 := "proc2"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			proc1: {
+				Name:        proc1,
+				ReplicaName: proc1,
+				Executable:  shell.ShellCommand,
+				// env printf writes all the lines at once, unlike the shell builtin
+				Args:            []string{shell.ShellArgument, `env printf 'one\ntwo\nready\n' && sleep 0.5`},
+				ReadyLogLine:    "ready",
+				OutputBuffering: types.OutputBufferingBlock,
+			},
+			proc2: {
+				Name:        proc2,
+				ReplicaName: proc2,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo started"},
+				DependsOn: map[string]types.ProcessDependency{
+					proc1: {
+						Condition: types.ProcessConditionLogReady,
+					},
+				},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	state, err := runner.GetProcessState(proc2)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status != types.ProcessStateCompleted {
+		t.Errorf("expected %s to be %s, got %s", proc2, types.ProcessStateCompleted, state.Status)
+	}
+	logs, err := runner.GetProcessLog(proc1, 10, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := []string{"one", "two", "ready"}
+	if !slices.Equal(logs, want) {
+		t.Errorf("expected %s logs %v, got %v", proc1, want, logs)
+	}
+}
+
+func TestSystem_TestStartPriority(t *testing.T) {
+	shell := command.DefaultShellConfig()
+	newProc := func(name, script string, priority int, dependency string) types.ProcessConfig {
+		proc := types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, script},
+			Priority:    priority,
+		}
+		if dependency != "" {
+			proc.DependsOn = types.DependsOnConfig{
+				dependency: {Condition: types.ProcessConditionCompletedSuccessfully},
+			}
+		}
+		return proc
+	}
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"slow": newProc("slow", "sleep 2", 0, ""),
+			"fast": newProc("fast", "exit 0", 0, ""),
+			// its dependency isn't met, so it doesn't hold the lower priority process
+			"late":  newProc("late", "exit 0", 10, "slow"),
+			"early": newProc("early", "exit 0", 0, "fast"),
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+	deadline := time.Now().Add(time.Second)
+	for {
+		state, err := runner.GetProcessState("early")
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if state.Status == types.ProcessStateCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("process early is %s, want it to complete before its higher priority peer starts", state.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if state, _ := runner.GetProcessState("late"); state.Status != types.ProcessStatePending {
+		t.Errorf("process late is %s, want %s until slow completes", state.Status, types.ProcessStatePending)
+	}
+}
+
+// lockedBuffer collects the log lines written from several goroutines
+type lockedBuffer struct {
+	mtx sync.Mutex
+	buf strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.String()
+}
+
+func TestSystem_TestStartupSummary(t *testing.T) {
+	logs := &lockedBuffer{}
+	prevLogger := log.Logger
+	log.Logger = zerolog.New(logs)
+	defer func() { log.Logger = prevLogger }()
+
+	shell := command.DefaultShellConfig()
+	newProc := func(name, cmd string, dependsOn map[string]types.ProcessDependency) types.ProcessConfig {
+		return types.ProcessConfig{
+			Name:        name,
+			ReplicaName: name,
+			Executable:  shell.ShellCommand,
+			Args:        []string{shell.ShellArgument, cmd},
+			DependsOn:   dependsOn,
+		}
+	}
+	completed := func(name string) map[string]types.ProcessDependency {
+		return map[string]types.ProcessDependency{
+			name: {Condition: types.ProcessConditionCompletedSuccessfully},
+		}
+	}
+	broken := newProc("broken", "exit 0", nil)
+	broken.WorkingDir = filepath.Join(t.TempDir(), "missing")
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"server":  newProc("server", "sleep 10", nil),
+			"first":   newProc("first", "sleep 0.3", nil),
+			"late":    newProc("late", "exit 0", completed("first")),
+			"failing": newProc("failing", "exit 1", nil),
+			"never":   newProc("never", "exit 0", completed("failing")),
+			"broken":  broken,
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	go runner.Run(context.Background())
+	defer runner.ShutDownProject()
+
+	type startupSummary struct {
+		Message         string  `json:"message"`
+		Total           int     `json:"total"`
+		Started         int     `json:"started"`
+		WontRun         int     `json:"wont_run"`
+		Failed          int     `json:"failed"`
+		StartupDuration float64 `json:"startup_duration"`
+	}
+	var summary *startupSummary
+	// logged once the processes have started, without waiting for the server to end
+	for i := 0; i < 50 && summary == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		for _, line := range strings.Split(logs.String(), "\n") {
+			var entry startupSummary
+			if json.Unmarshal([]byte(line), &entry) == nil && entry.Message == "Project startup completed" {
+				summary = &entry
+				break
+			}
+		}
+	}
+	if summary == nil {
+		t.Fatalf("no startup summary logged, got:\n%s", logs.String())
+	}
+	want := startupSummary{Message: "Project startup completed", Total: 6, Started: 4, WontRun: 1, Failed: 1}
+	got := *summary
+	got.StartupDuration = 0
+	if got != want {
+		t.Errorf("startup summary = %+v, want %+v", got, want)
+	}
+	// late starts once first completes
+	if summary.StartupDuration < 300 {
+		t.Errorf("startup_duration = %vms, want at least 300ms", summary.StartupDuration)
+	}
+	if summary.StartupDuration >= 5000 {
+		t.Errorf("startup_duration = %vms, want it to not wait for the server to end", summary.StartupDuration)
+	}
+}
+
+func TestSystem_TestShutDownWhileWaitingForDependency(t *testing.T) {
+	dep := "dep"
+	app := "app"
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			dep: {
+				Name:        dep,
+				ReplicaName: dep,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "sleep 10"},
+			},
+			app: {
+				Name:        app,
+				ReplicaName: app,
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, "echo started"},
+				DependsOn: map[string]types.ProcessDependency{
+					dep: {Condition: types.ProcessConditionCompletedSuccessfully},
+				},
+				RestartPolicy: types.RestartPolicyConfig{ExitOnSkipped: true},
+			},
+		},
+		ShellConfig: shell,
+	}
+	runner, err := NewProjectRunner(&ProjectOpts{project: project})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- runner.Run(context.Background())
+	}()
+	time.Sleep(300 * time.Millisecond)
+	if err = runner.ShutDownProject(); err != nil {
+		t.Fatalf("%s", err)
+	}
+	select {
+	case err = <-runErr:
+		// the failed wait of app isn't a dependency failure, so exit_on_skipped doesn't apply
+		if err != nil {
+			t.Errorf("Run() = %v, want no error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("project didn't end after the shutdown")
+	}
+	state, err := runner.GetProcessState(app)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if state.Status == types.ProcessStateSkipped {
+		t.Errorf("process %s status = %s, want it to not be skipped by the shutdown", app, state.Status)
+	}
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	minTimelineBarWidth = 10
+	svgRowHeight        = 24
+	svgCharWidth        = 8
+	svgChartWidth       = 640
+)
+
+type timelineEntry struct {
+	name     string
+	status   string
+	exitCode int
+	start    time.Time
+	end      time.Time
+}
+
+func (e timelineEntry) started() bool {
+	return !e.start.IsZero()
+}
+
+type timeline struct {
+	entries []timelineEntry
+	origin  time.Time
+	span    time.Duration
+}
+
+// newTimeline places the summary processes on a shared time axis, starting at the first process start
+func newTimeline(summary *RunSummary) *timeline {
+	tl := &timeline{}
+	finished, _ := time.Parse(time.RFC3339, summary.Timestamp)
+	var last time.Time
+	for _, proc := range summary.Processes {
+		entry := timelineEntry{
+			name:     proc.Name,
+			status:   proc.Status,
+			exitCode: proc.ExitCode,
+		}
+		entry.start, _ = time.Parse(time.RFC3339Nano, proc.StartTime)
+		entry.end, _ = time.Parse(time.RFC3339Nano, proc.EndTime)
+		if entry.started() {
+			if entry.end.Before(entry.start) {
+				// still running when the summary was written
+				entry.end = entry.start
+				if finished.After(entry.start) {
+					entry.end = finished
+				}
+			}
+			if tl.origin.IsZero() || entry.start.Before(tl.origin) {
+				tl.origin = entry.start
+			}
+			if entry.end.After(last) {
+				last = entry.end
+			}
+		}
+		tl.entries = append(tl.entries, entry)
+	}
+	if !tl.origin.IsZero() {
+		tl.span = last.Sub(tl.origin)
+	}
+	sort.SliceStable(tl.entries, func(i, j int) bool {
+		a, b := tl.entries[i], tl.entries[j]
+		if a.started() != b.started() {
+			return a.started()
+		}
+		if !a.start.Equal(b.start) {
+			return a.start.Before(b.start)
+		}
+		return a.name < b.name
+	})
+	return tl
+}
+
+// position returns the fraction of the time axis at t
+func (tl *timeline) position(t time.Time) float64 {
+	if tl.span <= 0 {
+		return 0
+	}
+	return float64(t.Sub(tl.origin)) / float64(tl.span)
+}
+
+func (tl *timeline) describe(entry timelineEntry) string {
+	if !entry.started() {
+		return entry.status
+	}
+	return fmt.Sprintf("%.1fs-%.1fs %s (%d)",
+		entry.start.Sub(tl.origin).Seconds(), entry.end.Sub(tl.origin).Seconds(), entry.status, entry.exitCode)
+}
+
+// RenderTimelineText renders the run summary as an ASCII Gantt chart of the given width
+func RenderTimelineText(summary *RunSummary, width int) string {
+	tl := newTimeline(summary)
+	nameWidth, descWidth := 0, 0
+	for _, entry := range tl.entries {
+		nameWidth = max(nameWidth, len(entry.name))
+		descWidth = max(descWidth, len(tl.describe(entry)))
+	}
+	barWidth := max(width-nameWidth-descWidth-4, minTimelineBarWidth)
+
+	var sb strings.Builder
+	for _, entry := range tl.entries {
+		bar := make([]byte, barWidth)
+		for i := range bar {
+			bar[i] = ' '
+		}
+		if entry.started() {
+			from := min(int(tl.position(entry.start)*float64(barWidth)), barWidth-1)
+			to := min(max(int(tl.position(entry.end)*float64(barWidth)), from+1), barWidth)
+			for i := from; i < to; i++ {
+				bar[i] = '#'
+			}
+		}
+		fmt.Fprintf(&sb, "%-*s |%s| %s\n", nameWidth, entry.name, bar, tl.describe(entry))
+	}
+	end := fmt.Sprintf("%.1fs", tl.span.Seconds())
+	fmt.Fprintf(&sb, "%-*s  0s%*s\n", nameWidth, "", max(barWidth-2, len(end)), end)
+	return sb.String()
+}
+
+// RenderTimelineSVG renders the run summary as an SVG Gantt chart
+func RenderTimelineSVG(summary *RunSummary) string {
+	tl := newTimeline(summary)
+	nameWidth := 0
+	for _, entry := range tl.entries {
+		nameWidth = max(nameWidth, len(entry.name))
+	}
+	labelWidth := (nameWidth + 2) * svgCharWidth
+	descX := labelWidth + svgChartWidth + svgCharWidth
+	width := descX + 32*svgCharWidth
+	height := (len(tl.entries) + 1) * svgRowHeight
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="12">`+"\n", width, height)
+	fmt.Fprintf(&sb, `  <rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	for i, entry := range tl.entries {
+		y := i * svgRowHeight
+		fmt.Fprintf(&sb, `  <text x="%d" y="%d">%s</text>`+"\n", svgCharWidth, y+16, html.EscapeString(entry.name))
+		if entry.started() {
+			x := labelWidth + int(tl.position(entry.start)*svgChartWidth)
+			barWidth := max(int((tl.position(entry.end)-tl.position(entry.start))*svgChartWidth), 1)
+			color := "#4caf50"
+			if entry.exitCode != 0 {
+				color = "#f44336"
+			}
+			fmt.Fprintf(&sb, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x, y+4, barWidth, svgRowHeight-8, color)
+		}
+		fmt.Fprintf(&sb, `  <text x="%d" y="%d">%s</text>`+"\n", descX, y+16, html.EscapeString(tl.describe(entry)))
+	}
+	axisY := len(tl.entries) * svgRowHeight
+	fmt.Fprintf(&sb, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="gray"/>`+"\n", labelWidth, axisY+2, labelWidth+svgChartWidth, axisY+2)
+	fmt.Fprintf(&sb, `  <text x="%d" y="%d">0s</text>`+"\n", labelWidth, axisY+16)
+	fmt.Fprintf(&sb, `  <text x="%d" y="%d" text-anchor="end">%.1fs</text>`+"\n", labelWidth+svgChartWidth, axisY+16, tl.span.Seconds())
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestRenderTimelineText(t *testing.T) {
+	summary := &RunSummary{
+		Timestamp: "2024-05-28T10:00:10Z",
+		Processes: []ProcessSummary{
+			{Name: "api", Status: types.ProcessStateCompleted, ExitCode: 1,
+				StartTime: "2024-05-28T10:00:05Z", EndTime: "2024-05-28T10:00:10Z"},
+			{Name: "db", Status: types.ProcessStateCompleted,
+				StartTime: "2024-05-28T10:00:00Z", EndTime: "2024-05-28T10:00:10Z"},
+			{Name: "lint", Status: types.ProcessStateSkipped},
+		},
+	}
+	got := RenderTimelineText(summary, 0)
+	want := strings.Join([]string{
+		"db   |##########| 0.0s-10.0s Completed (0)",
+		"api  |     #####| 5.0s-10.0s Completed (1)",
+		"lint |          | Skipped",
+		"      0s   10.0s",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("RenderTimelineText() =\n%s\nwant\n%s", got, want)
+	}
+	if svg := RenderTimelineSVG(summary); !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, ">api</text>") {
+		t.Errorf("RenderTimelineSVG() = %s, want an SVG chart", svg)
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"golang.org/x/sys/unix"
+)
+
+var supportedUlimits = map[string]int{
+	"nofile": unix.RLIMIT_NOFILE,
+	"stack":  unix.RLIMIT_STACK,
+	"core":   unix.RLIMIT_CORE,
+	"fsize":  unix.RLIMIT_FSIZE,
+}
+
+// ulimitMtx serializes the process starts, so no process inherits the limits set for another one
+var ulimitMtx sync.Mutex
+
+// startWithUlimits starts the process with its configured soft resource limits.
+// macOS can't set the limits of another process and they are inherited on fork, so they are set on
+// process-compose itself for the duration of the start and restored afterward
+func startWithUlimits(ulimits types.Ulimits, name string, cmd command.Commander) error {
+	ulimitMtx.Lock()
+	defer ulimitMtx.Unlock()
+	if len(ulimits) == 0 {
+		return cmd.Start()
+	}
+	previous := setUlimits(ulimits, name, func(resource int, limit, old *unix.Rlimit) error {
+		if old != nil {
+			if err := unix.Getrlimit(resource, old); err != nil {
+				return err
+			}
+		}
+		if limit != nil {
+			return unix.Setrlimit(resource, limit)
+		}
+		return nil
+	})
+	defer func() {
+		for resource, limit := range previous {
+			_ = unix.Setrlimit(resource, &limit)
+		}
+	}()
+	return cmd.Start()
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"golang.org/x/sys/unix"
+)
+
+var supportedUlimits = map[string]int{
+	"nofile":  unix.RLIMIT_NOFILE,
+	"nproc":   unix.RLIMIT_NPROC,
+	"stack":   unix.RLIMIT_STACK,
+	"core":    unix.RLIMIT_CORE,
+	"memlock": unix.RLIMIT_MEMLOCK,
+	"fsize":   unix.RLIMIT_FSIZE,
+}
+
+// startWithUlimits starts the process with its configured soft resource limits. They are set by the ulimit exec
+// before the command is executed, so they apply to everything the process forks. The limits of process-compose
+// and of the processes started concurrently aren't affected. Without a ulimit exec, they are set on the started
+// child with prlimit
+func startWithUlimits(ulimits types.Ulimits, name string, cmd command.Commander) error {
+	if len(ulimits) == 0 {
+		return cmd.Start()
+	}
+	if !command.CanExecWithUlimits() {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		childPid := cmd.Pid()
+		setUlimits(ulimits, name, func(resource int, limit, old *unix.Rlimit) error {
+			return unix.Prlimit(childPid, resource, limit, old)
+		})
+		return nil
+	}
+	// the child inherits the limits of process-compose, the configured ones are capped at its hard limits
+	limits := map[int]uint64{}
+	setUlimits(ulimits, name, func(resource int, limit, old *unix.Rlimit) error {
+		if old != nil {
+			return unix.Getrlimit(resource, old)
+		}
+		limits[resource] = limit.Cur
+		return nil
+	})
+	cmd.SetUlimits(limits)
+	return cmd.Start()
+}
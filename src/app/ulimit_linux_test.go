@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"golang.org/x/sys/unix"
+)
+
+func TestStartWithUlimits(t *testing.T) {
+	var before unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &before); err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := before.Cur / 2
+	// without a ulimit exec, the limits are set on the started child
+	cmd := command.BuildCommand("sleep", []string{"1"})
+	err := startWithUlimits(types.Ulimits{"nofile": int64(want), "unknown": 1}, "test", cmd)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		_ = cmd.Stop(int(unix.SIGKILL), true)
+		_ = cmd.Wait()
+	}()
+	var child unix.Rlimit
+	if err = unix.Prlimit(cmd.Pid(), unix.RLIMIT_NOFILE, nil, &child); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if child.Cur != want {
+		t.Errorf("child nofile = %d, want %d", child.Cur, want)
+	}
+	var after unix.Rlimit
+	if err = unix.Getrlimit(unix.RLIMIT_NOFILE, &after); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if after != before {
+		t.Errorf("process-compose nofile after start = %v, want %v", after, before)
+	}
+}
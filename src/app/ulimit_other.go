@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package app
+
+import (
+	"runtime"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+func startWithUlimits(ulimits types.Ulimits, name string, cmd command.Commander) error {
+	for key := range ulimits {
+		log.Warn().Msgf("ulimit %s is not supported on %s, ignoring it for %s", key, runtime.GOOS, name)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,46 @@
+//go:build linux || darwin
+
+package app
+
+import (
+	"runtime"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sys/unix"
+)
+
+// rlimitFunc sets the limit of a resource, when it isn't nil, and returns its previous limit in old, when it isn't nil
+type rlimitFunc func(resource int, limit, old *unix.Rlimit) error
+
+// setUlimits sets the configured soft resource limits with setLimit, capped at the hard limits.
+// It returns the previous limits of the changed resources
+func setUlimits(ulimits types.Ulimits, name string, setLimit rlimitFunc) map[int]unix.Rlimit {
+	previous := make(map[int]unix.Rlimit, len(ulimits))
+	for key, limit := range ulimits {
+		resource, ok := supportedUlimits[key]
+		if !ok {
+			log.Warn().Msgf("ulimit %s is not supported on %s, ignoring it for %s", key, runtime.GOOS, name)
+			continue
+		}
+		var current unix.Rlimit
+		if err := setLimit(resource, nil, &current); err != nil {
+			log.Warn().Err(err).Msgf("failed to get ulimit %s for %s", key, name)
+			continue
+		}
+		updated := current
+		updated.Cur = uint64(limit)
+		if updated.Cur > updated.Max {
+			if limit != types.UlimitUnlimited {
+				log.Warn().Msgf("ulimit %s=%d for %s exceeds the hard limit %d, using the hard limit", key, limit, name, updated.Max)
+			}
+			updated.Cur = updated.Max
+		}
+		if err := setLimit(resource, &updated, nil); err != nil {
+			log.Warn().Err(err).Msgf("failed to set ulimit %s=%d for %s", key, limit, name)
+			continue
+		}
+		previous[resource] = current
+	}
+	return previous
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// runValidateCommands runs the project validate_command, which must succeed for the project to start, and the
+// processes validate_command. A process whose validation failed won't run
+func (p *ProjectRunner) runValidateCommands(ctx context.Context, runOrder []types.ProcessConfig) error {
+	if p.project.ValidateCommand != "" {
+		env := append(os.Environ(), p.project.Environment...)
+		if err := p.runValidateCommand(ctx, p.project.ValidateCommand, "", env); err != nil {
+			return fmt.Errorf("project validation failed: %w", err)
+		}
+		log.Info().Msg("Project validation passed")
+	}
+	// the replicas of a process share its validation, run with the environment of the first replica
+	validations := map[string][]string{}
+	confs := map[string]types.ProcessConfig{}
+	for _, proc := range runOrder {
+		if proc.ValidateCommand == "" {
+			continue
+		}
+		validations[proc.Name] = append(validations[proc.Name], proc.ReplicaName)
+		if conf, ok := confs[proc.Name]; !ok || proc.ReplicaNum < conf.ReplicaNum {
+			confs[proc.Name] = proc
+		}
+	}
+	if len(validations) == 0 {
+		return nil
+	}
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	failed := map[string]error{}
+	var errs []error
+	for name, proc := range confs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.runValidateCommand(ctx, proc.ValidateCommand, proc.WorkingDir, p.getValidateEnvironment(proc))
+			if err == nil {
+				return
+			}
+			err = fmt.Errorf("process %s validation failed: %w", name, err)
+			mtx.Lock()
+			defer mtx.Unlock()
+			errs = append(errs, err)
+			for _, replica := range validations[name] {
+				failed[replica] = err
+			}
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		log.Error().Err(errors.Join(errs...)).Msgf("%d of %d processes failed their validation", len(errs), len(confs))
+	}
+	p.statesMutex.Lock()
+	p.validationErrors = failed
+	p.statesMutex.Unlock()
+	return nil
+}
+
+// getValidateEnvironment returns the environment the process is started with, as its validate_command sees it
+func (p *ProjectRunner) getValidateEnvironment(procConf types.ProcessConfig) []string {
+	return buildProcessEnvironment(&procConf, p.project.Environment, p.runID, "")
+}
+
+func (p *ProjectRunner) runValidateCommand(ctx context.Context, validateCommand, dir string, env []string) error {
+	cmd := command.BuildCommandShellArgContext(ctx, *p.project.ShellConfig, validateCommand)
+	cmd.SetEnv(env)
+	cmd.SetDir(dir)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if output := strings.TrimSpace(string(out)); output != "" {
+			return fmt.Errorf("'%s' exited with code %d: %s", validateCommand, cmd.ExitCode(), output)
+		}
+		return fmt.Errorf("'%s' failed: %w", validateCommand, err)
+	}
+	return nil
+}
+
+// popValidationError returns the validation error of the process, once. A process started again after a failed
+// validation isn't validated again
+func (p *ProjectRunner) popValidationError(name string) error {
+	p.statesMutex.Lock()
+	defer p.statesMutex.Unlock()
+	err := p.validationErrors[name]
+	delete(p.validationErrors, name)
+	return err
+}
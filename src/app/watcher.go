@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultWatchDebounce = 500 * time.Millisecond
+
+// watchProcess restarts the process when one of its watch_paths changes, until ctx is done
+func (p *ProjectRunner) watchProcess(ctx context.Context, config types.ProcessConfig) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Err(err).Msgf("failed to create a file watcher for %s", config.ReplicaName)
+		return
+	}
+	defer watcher.Close()
+
+	// files are watched through their directory to survive editors that replace the file on save
+	dirs := map[string]bool{}
+	files := map[string]bool{}
+	for _, path := range config.WatchPaths {
+		if !filepath.IsAbs(path) && config.WorkingDir != "" {
+			path = filepath.Join(config.WorkingDir, path)
+		}
+		path = filepath.Clean(path)
+		dir := path
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dirs[path] = true
+		} else {
+			files[path] = true
+			dir = filepath.Dir(path)
+		}
+		if err = watcher.Add(dir); err != nil {
+			log.Err(err).Msgf("failed to watch %s for %s", path, config.ReplicaName)
+		}
+	}
+
+	debounce := config.WatchDebounce
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ctxApp.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			name := filepath.Clean(event.Name)
+			if !files[name] && !dirs[filepath.Dir(name)] {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Err(err).Msgf("file watcher error for %s", config.ReplicaName)
+		case <-timer.C:
+			log.Info().Msgf("Watched files changed, restarting %s", config.ReplicaName)
+			if err = p.RestartProcess(config.ReplicaName); err != nil {
+				log.Err(err).Msgf("failed to restart %s", config.ReplicaName)
+			} else if p.isWatchMode {
+				p.restartDependents(config.ReplicaName)
+			}
+		}
+	}
+}
+
+// restartDependents restarts the running processes that depend on the restarted process, directly or
+// transitively, so they wait for it again
+func (p *ProjectRunner) restartDependents(name string) {
+	for _, dependent := range p.project.GetDependents(name) {
+		if p.getRunningProcess(dependent) == nil {
+			continue
+		}
+		log.Info().Msgf("Restarting %s, it depends on %s", dependent, name)
+		if err := p.RestartProcess(dependent); err != nil {
+			log.Err(err).Msgf("failed to restart %s", dependent)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	webhookBuffer       = 1024
+	webhookRetries      = 3
+	webhookRetryBackoff = 500 * time.Millisecond
+	webhookTimeout      = 5 * time.Second
+	webhookEventRestart = "restart"
+)
+
+type webhookLine struct {
+	Process string `json:"process"`
+	Line    string `json:"line"`
+	Stream  string `json:"stream"`
+	Ts      string `json:"ts"`
+}
+
+// webhookRestart is the restart notification of a process, with the context to diagnose a crash loop
+type webhookRestart struct {
+	Process   string   `json:"process"`
+	Event     string   `json:"event"`
+	Restarts  int      `json:"restarts"`
+	ExitCode  int      `json:"exit_code"`
+	Uptime    string   `json:"uptime"`
+	LastLines []string `json:"last_lines"`
+	Ts        string   `json:"ts"`
+}
+
+// webhookPayload is a queued webhook body of a process
+type webhookPayload struct {
+	process string
+	body    any
+}
+
+// outputWebhook posts the process output lines to a URL, without blocking the process output handling
+type outputWebhook struct {
+	url    string
+	client *http.Client
+	lines  chan webhookPayload
+	mtx    sync.Mutex
+	closed bool
+}
+
+func newOutputWebhook(url string) *outputWebhook {
+	return &outputWebhook{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+		lines:  make(chan webhookPayload, webhookBuffer),
+	}
+}
+
+// send queues the line for delivery. The line is dropped if the queue is full
+func (w *outputWebhook) send(process, stream, line string) {
+	w.enqueue(process, webhookLine{
+		Process: process,
+		Line:    line,
+		Stream:  stream,
+		Ts:      time.Now().Format(time.RFC3339Nano),
+	}, "a line")
+}
+
+// sendRestart queues the restart notification for delivery. It is dropped if the queue is full
+func (w *outputWebhook) sendRestart(restart *webhookRestart) {
+	w.enqueue(restart.Process, restart, "a restart notification")
+}
+
+func (w *outputWebhook) enqueue(process string, body any, desc string) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.lines <- webhookPayload{process: process, body: body}:
+	default:
+		log.Warn().Str("process", process).Msgf("output webhook queue is full, dropping %s", desc)
+	}
+}
+
+// close stops the delivery once the queued lines are delivered
+func (w *outputWebhook) close() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if !w.closed {
+		w.closed = true
+		close(w.lines)
+	}
+}
+
+// run delivers the queued lines until the queue is closed
+func (w *outputWebhook) run() {
+	for payload := range w.lines {
+		if err := w.deliver(payload.body); err != nil {
+			log.Err(err).Str("process", payload.process).Msgf("failed to deliver the output to %s", w.url)
+		}
+	}
+}
+
+func (w *outputWebhook) deliver(payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	backoff := webhookRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = w.post(body)
+		if err == nil || attempt == webhookRetries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *outputWebhook) post(body []byte) error {
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
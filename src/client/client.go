@@ -58,7 +58,11 @@ func newClient(address string, client *http.Client, logLength int) *PcClient {
 }
 
 func (p *PcClient) ShutDownProject() error {
-	return p.shutDownProject()
+	return p.shutDownProject(0)
+}
+
+func (p *PcClient) ShutDownProjectWithTimeout(timeout time.Duration) error {
+	return p.shutDownProject(timeout)
 }
 
 func (p *PcClient) IsRemote() bool {
@@ -87,6 +91,10 @@ func (p *PcClient) GetProcessLog(name string, offsetFromEnd, limit int) ([]strin
 	panic("implement me")
 }
 
+func (p *PcClient) SearchProcessLog(name string, query *types.LogSearchQuery) ([]string, error) {
+	return p.searchProcessLog(name, query)
+}
+
 func (p *PcClient) GetLexicographicProcessNames() ([]string, error) {
 	names, err := p.GetProcessesName()
 	return names, err
@@ -109,8 +117,16 @@ func (p *PcClient) GetProcessesState() (*types.ProcessesState, error) {
 	return p.GetRemoteProcessesState()
 }
 
-func (p *PcClient) StopProcess(name string) error {
-	return p.stopProcess(name)
+func (p *PcClient) StopProcess(name string, timeout time.Duration) error {
+	return p.stopProcess(name, timeout)
+}
+
+func (p *PcClient) ExplainProcess(name string) (string, error) {
+	return p.explainProcess(name)
+}
+
+func (p *PcClient) ReloadProcess(name string) error {
+	return p.reloadProcess(name)
 }
 
 func (p *PcClient) StopProcesses(names []string) (map[string]string, error) {
@@ -164,6 +180,17 @@ func (p *PcClient) SetProcessPassword(_, _ string) error {
 	return fmt.Errorf("set process password not allowed for PC client")
 }
 
+func (p *PcClient) WriteProcessStdin(_, _ string) error {
+	return fmt.Errorf("write process stdin not allowed for PC client")
+}
+
+func (p *PcClient) UpdateProcessConfig(name string, procConf *types.ProcessConfig) error {
+	return p.updateProcessConfig(name, procConf)
+}
+
 func (p *PcClient) UpdateProject(project *types.Project) (map[string]string, error) {
 	return p.updateProject(project)
 }
+
+// Audit is a no-op, the commands are recorded by the server
+func (p *PcClient) Audit(_, _ string, _ ...string) {}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog/log"
+	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
+	"time"
 )
 
 func (p *PcClient) GetProcessesName() ([]string, error) {
@@ -40,7 +44,7 @@ func (p *PcClient) GetRemoteProcessesState() (*types.ProcessesState, error) {
 }
 
 func (p *PcClient) getProcessState(name string) (*types.ProcessState, error) {
-	url := fmt.Sprintf("http://%s/process/%s", p.address, name)
+	url := fmt.Sprintf("http://%s/process/%s", p.address, url.PathEscape(name))
 	resp, err := p.client.Get(url)
 	if err != nil {
 		return nil, err
@@ -58,7 +62,7 @@ func (p *PcClient) getProcessState(name string) (*types.ProcessState, error) {
 }
 
 func (p *PcClient) getProcessInfo(name string) (*types.ProcessConfig, error) {
-	url := fmt.Sprintf("http://%s/process/info/%s", p.address, name)
+	url := fmt.Sprintf("http://%s/process/info/%s", p.address, url.PathEscape(name))
 	resp, err := p.client.Get(url)
 	if err != nil {
 		return nil, err
@@ -76,7 +80,7 @@ func (p *PcClient) getProcessInfo(name string) (*types.ProcessConfig, error) {
 }
 
 func (p *PcClient) getProcessPorts(name string) (*types.ProcessPorts, error) {
-	url := fmt.Sprintf("http://%s/process/ports/%s", p.address, name)
+	url := fmt.Sprintf("http://%s/process/ports/%s", p.address, url.PathEscape(name))
 	resp, err := p.client.Get(url)
 	if err != nil {
 		return nil, err
@@ -92,3 +96,60 @@ func (p *PcClient) getProcessPorts(name string) (*types.ProcessPorts, error) {
 
 	return &sResp, nil
 }
+
+func (p *PcClient) searchProcessLog(name string, query *types.LogSearchQuery) ([]string, error) {
+	params := url.Values{}
+	params.Set("q", query.Pattern)
+	if !query.Since.IsZero() {
+		params.Set("since", query.Since.Format(time.RFC3339))
+	}
+	if !query.Until.IsZero() {
+		params.Set("until", query.Until.Format(time.RFC3339))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	reqUrl := fmt.Sprintf("http://%s/processes/%s/logs?%s", p.address, url.PathEscape(name), params.Encode())
+	resp, err := p.client.Get(reqUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var respErr pcError
+		if err = json.NewDecoder(resp.Body).Decode(&respErr); err != nil {
+			log.Error().Msgf("failed to decode search process %s logs response: %v", name, err)
+			return nil, err
+		}
+		return nil, fmt.Errorf(respErr.Error)
+	}
+	var logs []string
+	if err = json.NewDecoder(resp.Body).Decode(&logs); err != nil {
+		log.Err(err).Msgf("failed to decode process %s logs", name)
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (p *PcClient) explainProcess(name string) (string, error) {
+	url := fmt.Sprintf("http://%s/processes/%s/explain", p.address, url.PathEscape(name))
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var respErr pcError
+		if err = json.NewDecoder(resp.Body).Decode(&respErr); err != nil {
+			log.Error().Msgf("failed to decode explain process %s response: %v", name, err)
+			return "", err
+		}
+		return "", fmt.Errorf(respErr.Error)
+	}
+	explanation := map[string]string{}
+	if err = json.NewDecoder(resp.Body).Decode(&explanation); err != nil {
+		log.Err(err).Msgf("failed to decode process %s explanation", name)
+		return "", err
+	}
+	return explanation["explanation"], nil
+}
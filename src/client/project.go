@@ -7,10 +7,15 @@ import (
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"net/url"
+	"time"
 )
 
-func (p *PcClient) shutDownProject() error {
+func (p *PcClient) shutDownProject(timeout time.Duration) error {
 	url := fmt.Sprintf("http://%s/project/stop/", p.address)
+	if timeout > 0 {
+		url += "?timeout=" + timeout.String()
+	}
 	req, err := http.NewRequest(http.MethodPost, url, nil)
 	if err != nil {
 		return err
@@ -78,3 +83,31 @@ func (p *PcClient) updateProject(project *types.Project) (map[string]string, err
 	}
 	return nil, fmt.Errorf(respErr.Error)
 }
+
+func (p *PcClient) updateProcessConfig(name string, procConf *types.ProcessConfig) error {
+	url := fmt.Sprintf("http://%s/processes/%s", p.address, url.PathEscape(name))
+	jsonData, err := json.Marshal(procConf)
+	if err != nil {
+		log.Err(err).Msgf("failed to marshal process %s config", name)
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var respErr pcError
+	if err = json.NewDecoder(resp.Body).Decode(&respErr); err != nil {
+		log.Error().Msgf("failed to decode update process %s response: %v", name, err)
+		return err
+	}
+	return fmt.Errorf(respErr.Error)
+}
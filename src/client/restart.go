@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"net/url"
 )
 
 func (p *PcClient) restartProcess(name string) error {
-	url := fmt.Sprintf("http://%s/process/restart/%s", p.address, name)
+	url := fmt.Sprintf("http://%s/process/restart/%s", p.address, url.PathEscape(name))
 	resp, err := p.client.Post(url, "application/json", nil)
 	if err != nil {
 		return err
@@ -24,3 +25,21 @@ func (p *PcClient) restartProcess(name string) error {
 	}
 	return fmt.Errorf(respErr.Error)
 }
+
+func (p *PcClient) reloadProcess(name string) error {
+	url := fmt.Sprintf("http://%s/processes/%s/reload", p.address, url.PathEscape(name))
+	resp, err := p.client.Post(url, "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var respErr pcError
+	if err = json.NewDecoder(resp.Body).Decode(&respErr); err != nil {
+		log.Error().Msgf("failed to decode reload process %s response: %v", name, err)
+		return err
+	}
+	return fmt.Errorf(respErr.Error)
+}
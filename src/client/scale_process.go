@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"net/url"
 )
 
 func (p *PcClient) scaleProcess(name string, scale int) error {
-	url := fmt.Sprintf("http://%s/process/scale/%s/%d", p.address, name, scale)
+	url := fmt.Sprintf("http://%s/process/scale/%s/%d", p.address, url.PathEscape(name), scale)
 	req, err := http.NewRequest(http.MethodPatch, url, nil)
 	if err != nil {
 		return err
@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"net/url"
 )
 
 func (p *PcClient) startProcess(name string) error {
-	url := fmt.Sprintf("http://%s/process/start/%s", p.address, name)
+	url := fmt.Sprintf("http://%s/process/start/%s", p.address, url.PathEscape(name))
 	resp, err := p.client.Post(url, "application/json", nil)
 	if err != nil {
 		return err
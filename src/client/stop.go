@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"net/url"
+	"time"
 )
 
-func (p *PcClient) stopProcess(name string) error {
-	url := fmt.Sprintf("http://%s/process/stop/%s", p.address, name)
+func (p *PcClient) stopProcess(name string, timeout time.Duration) error {
+	url := fmt.Sprintf("http://%s/process/stop/%s", p.address, url.PathEscape(name))
+	if timeout > 0 {
+		url += "?timeout=" + timeout.String()
+	}
 	req, err := http.NewRequest(http.MethodPatch, url, nil)
 	if err != nil {
 		return err
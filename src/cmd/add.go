@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	addProcessName string
+	addConfigFile  string
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add [COMMAND]",
+	Short: "Add a process to the config file",
+	Long: `Add a process to the config file. The process name is derived from the command executable,
+unless set with --name: 'process-compose add "./bin/server --port 8080"' adds a process named 'server'`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		command := args[0]
+		name := addProcessName
+		if name == "" {
+			name = loader.ProcessNameFromCommand(command)
+		}
+		if name == "" {
+			log.Fatal().Msg("failed to derive the process name from the command, use --name")
+		}
+		file := addConfigFile
+		if file == "" {
+			var err error
+			if file, err = loader.DiscoverConfigFile(); err != nil {
+				log.Fatal().Err(err).Msg("failed to find the config file")
+			}
+		}
+		if err := loader.AddProcess(file, name, command); err != nil {
+			log.Fatal().Err(err).Msgf("failed to add process %s", name)
+		}
+		fmt.Printf("Process %s added to %s\n", name, file)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().StringVarP(&addProcessName, "name", "n", "", "process name, derived from the command if not set")
+	addCmd.Flags().StringVarP(&addConfigFile, "config", "f", "", "config file to add the process to (default: the auto discovered config file)")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffOutputFormat  = "text"
+	diffEnvFiles      []string
+	diffExtraEnvFiles []string
+	diffEnvVars       []string
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [FILE1] [FILE2]",
+	Short: "Show the process changes between two config files",
+	Long: `Load both config files and show the processes added, removed and modified from the first to the second,
+with the changed fields of each modified process, e.g. to review a change of the process composition:
+'process-compose diff <(git show main:process-compose.yaml) process-compose.yaml'`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := loader.Load(&loader.LoaderOptions{FileNames: []string{args[0]}, EnvFileNames: diffEnvFiles, ExtraEnvFileNames: diffExtraEnvFiles, EnvVars: diffEnvVars})
+		if err != nil {
+			log.Fatal().Err(err).Msgf("failed to load %s", args[0])
+		}
+		to, err := loader.Load(&loader.LoaderOptions{FileNames: []string{args[1]}, EnvFileNames: diffEnvFiles, ExtraEnvFileNames: diffExtraEnvFiles, EnvVars: diffEnvVars})
+		if err != nil {
+			log.Fatal().Err(err).Msgf("failed to load %s", args[1])
+		}
+		diff, err := loader.DiffProjects(from, to)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to diff the config files")
+		}
+		switch diffOutputFormat {
+		case "json":
+			b, err := json.MarshalIndent(diff, "", "\t")
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to marshal the diff")
+			}
+			fmt.Println(string(b))
+		case "text":
+			if diff.IsEmpty() {
+				fmt.Println("No process changes")
+				return
+			}
+			printColoredDiff(diff.String())
+		default:
+			log.Fatal().Msgf("unknown output format %s", diffOutputFormat)
+		}
+	},
+}
+
+func printColoredDiff(text string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			color.Green(line)
+		case strings.HasPrefix(line, "-"):
+			color.Red(line)
+		case strings.HasPrefix(line, "~"):
+			color.Yellow(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringArrayVarP(&diffEnvFiles, "env", "e", []string{".env"}, "path to env files to load")
+	diffCmd.Flags().StringArrayVar(&diffExtraEnvFiles, "env-file", nil, "path to env files to load, in addition to the --env ones")
+	diffCmd.Flags().StringArrayVar(&diffEnvVars, "var", nil, "KEY=VALUE variables to set in the environment, they override all the other environment sources")
+	diffCmd.Flags().StringVarP(&diffOutputFormat, "output", "o", diffOutputFormat, "Output format. One of: (text, json)")
+}
@@ -1,25 +1,93 @@
 package cmd
 
 import (
+	"time"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog/log"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	downTimeoutSec    = 0
+	downRemoveState   = false
+	downConfigFiles   []string
+	downEnvFiles      []string
+	downExtraEnvFiles []string
+	downEnvVars       []string
+)
+
 // downCmd represents the down command
 var downCmd = &cobra.Command{
 	Use:   "down",
 	Short: "Stops all the running processes and terminates the Process Compose",
+	Long: `Stops all the running processes and terminates the Process Compose.
+If the Process Compose server isn't available, the processes recorded in the project cleanup_pid_dir are terminated instead`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := getClient().ShutDownProject()
-		if err != nil {
-			log.Fatal().Err(err).Msg("failed to stop project")
+		timeout := time.Duration(downTimeoutSec) * time.Second
+		pcClient := getClient()
+		var project *types.Project
+		if pcClient.IsAlive() == nil {
+			var err error
+			if timeout > 0 {
+				err = pcClient.ShutDownProjectWithTimeout(timeout)
+			} else {
+				err = pcClient.ShutDownProject()
+			}
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to stop project")
+			}
+		} else {
+			project = loadDownProject()
+			if project.CleanupPIDDir == "" {
+				log.Fatal().Msg("the Process Compose server isn't available and the project has no cleanup_pid_dir to find the running processes")
+			}
+			if timeout == 0 {
+				timeout = app.GetGracePeriod(project)
+			}
+			stopped, err := app.StopPidDir(project.CleanupPIDDir, timeout)
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to stop project")
+			}
+			log.Info().Msgf("Stopped %d processes from %s", stopped, project.CleanupPIDDir)
 		}
 		log.Info().Msgf("Project stopped")
+		if downRemoveState {
+			if project == nil {
+				project = loadDownProject()
+			}
+			if err := app.RemoveProjectState(project); err != nil {
+				log.Fatal().Err(err).Msg("failed to remove the project state")
+			}
+			log.Info().Msgf("Removed the project state %s", project.StateDir)
+		}
 	},
 }
 
+func loadDownProject() *types.Project {
+	project, err := loader.Load(&loader.LoaderOptions{
+		FileNames:         downConfigFiles,
+		EnvFileNames:      downEnvFiles,
+		ExtraEnvFileNames: downExtraEnvFiles,
+		EnvVars:           downEnvVars,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load the project")
+	}
+	return project
+}
+
 func init() {
 	rootCmd.AddCommand(downCmd)
 	downCmd.Flags().StringVarP(pcFlags.Address, "address", "a", *pcFlags.Address, "address of the target process compose server")
+	downCmd.Flags().IntVarP(&downTimeoutSec, "timeout", "t", downTimeoutSec, "grace period in seconds before killing the processes that are still running (default: the processes shutdown timeouts, or grace_period_seconds without a server)")
+	downCmd.Flags().BoolVar(&downRemoveState, "remove-state", downRemoveState, "also remove the processes log files and the project state directory")
+	downCmd.Flags().StringArrayVarP(&downConfigFiles, "config", "f", config.GetConfigDefault(), "path to config files to load, when the server isn't available or with --remove-state (env: "+config.EnvVarNameConfig+")")
+	downCmd.Flags().StringArrayVarP(&downEnvFiles, "env", "e", []string{".env"}, "path to env files to load")
+	downCmd.Flags().StringArrayVar(&downExtraEnvFiles, "env-file", nil, "path to env files to load, in addition to the --env ones")
+	downCmd.Flags().StringArrayVar(&downEnvVars, "var", nil, "KEY=VALUE variables to set in the environment, they override all the other environment sources")
 }
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain [PROCESS]",
+	Short: "Explain why processes are in their current state",
+	Long: `Explain why a process is in its current state, e.g. which dependency a Pending process is waiting for,
+or why a process was Skipped. Without a process name, all the processes are explained`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pcClient := getClient()
+		names := args
+		if len(names) == 0 {
+			var err error
+			if names, err = pcClient.GetLexicographicProcessNames(); err != nil {
+				log.Fatal().Err(err).Msg("failed to get the processes names")
+			}
+		}
+		for _, name := range names {
+			explanation, err := pcClient.ExplainProcess(name)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("failed to explain process %s", name)
+			}
+			fmt.Println(explanation)
+		}
+	},
+}
+
+func init() {
+	processCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateFilter     string
+	generateAll        bool
+	generateOutputFile string
+)
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a config file from the running processes",
+	Long: `Generate a config file with a process per running process, to bootstrap the config of an existing setup.
+Each process has the command, working directory and environment of the running process, the environment includes
+only the variables that differ from the current environment, the secret variables reference the variable of the same
+name instead of holding its value. Select the processes with the --filter regex,
+e.g. 'process-compose generate --filter "redis|postgres"', or include all of them with --all`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if generateFilter == "" && !generateAll {
+			log.Fatal().Msg("select the processes with --filter, or use --all to include all the running processes")
+		}
+		var filter *regexp.Regexp
+		if generateFilter != "" {
+			var err error
+			if filter, err = regexp.Compile(generateFilter); err != nil {
+				log.Fatal().Err(err).Msgf("invalid filter %s", generateFilter)
+			}
+		}
+		data, err := loader.GenerateConfig(filter)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to generate the config")
+		}
+		if generateOutputFile == "" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err = os.WriteFile(generateOutputFile, data, 0600); err != nil {
+			log.Fatal().Err(err).Msgf("failed to write %s", generateOutputFile)
+		}
+		fmt.Printf("%s generated\n", generateOutputFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().StringVar(&generateFilter, "filter", "", "regex matched against the command lines of the processes to include")
+	generateCmd.Flags().BoolVar(&generateAll, "all", false, "include all the running processes")
+	generateCmd.MarkFlagsMutuallyExclusive("filter", "all")
+	generateCmd.Flags().StringVarP(&generateOutputFile, "output", "o", "", "config file to write (default: print to stdout)")
+}
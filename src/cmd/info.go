@@ -26,6 +26,7 @@ func printInfo() {
 	}
 	fmt.Printf(format, "Custom Theme:", config.GetThemesPath())
 	fmt.Printf(format, "Settings:", config.GetSettingsPath())
+	fmt.Printf(format, "State:", config.GetStateHome())
 
 }
 
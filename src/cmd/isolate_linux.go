@@ -0,0 +1,45 @@
+//go:build linux
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+)
+
+// registerIsolationInit makes this executable the init of the isolated processes
+func registerIsolationInit() {
+	if exe, err := os.Executable(); err == nil {
+		command.SetIsolationInit(exe)
+	}
+}
+
+// runAsIsolationInit runs the command of an isolated process as the child of the init (PID 1) of its PID
+// namespace. It mounts the /proc of the namespace, forwards the signals to the command and reaps the orphan
+// processes until the command exits. It returns only if this process isn't an isolation init
+func runAsIsolationInit() {
+	if os.Getenv(command.EnvIsolationInit) == "" || len(os.Args) < 2 {
+		return
+	}
+	_ = os.Unsetenv(command.EnvIsolationInit)
+	// the mount namespace is private, the host /proc is left as is
+	if err := syscall.Mount("proc", "/proc", "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "process-compose: failed to mount /proc: %v\n", err)
+	}
+	sigs := make(chan os.Signal, 16)
+	signal.Notify(sigs)
+	cmd := exec.Command(os.Args[1], os.Args[2:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "process-compose: %v\n", err)
+		os.Exit(127)
+	}
+	superviseChild(cmd, sigs)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+// TestMain runs the test binary as the init of the isolated processes and as the ulimit exec, like the
+// process-compose executable
+func TestMain(m *testing.M) {
+	runAsIsolationInit()
+	runAsUlimitExec()
+	os.Exit(m.Run())
+}
+
+func TestIsolationInit(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	command.SetIsolationInit(exe)
+	defer command.SetIsolationInit("")
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"isolated": {
+				Name:        "isolated",
+				ReplicaName: "isolated",
+				Executable:  shell.ShellCommand,
+				Args:        []string{shell.ShellArgument, `echo init=$(tr '\0' ' ' < /proc/1/cmdline)`},
+				Isolated:    true,
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := app.NewProjectRunner((&app.ProjectOpts{}).WithProject(project))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Skipf("the namespaces can't be created here: %s", err)
+	}
+	logs, err := runner.GetProcessLog("isolated", 10, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	// the /proc of the namespace shows the init as PID 1
+	wantInit := "init=" + exe
+	if !slices.ContainsFunc(logs, func(line string) bool { return strings.HasPrefix(line, wantInit) }) {
+		t.Errorf("expected %s to be PID 1 in the isolated /proc, got %q", filepath.Base(exe), logs)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package cmd
+
+func registerIsolationInit() {}
+
+func runAsIsolationInit() {}
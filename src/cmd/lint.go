@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintOutputFormat  = "text"
+	lintConfigFiles   []string
+	lintEnvFiles      []string
+	lintExtraEnvFiles []string
+	lintEnvVars       []string
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Report the issues of the config files",
+	Long: `Load the config files and report their issues with a severity (error, warning or info) and a suggested fix.
+The exit code is 1 if any error was found, otherwise 0`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var issues []loader.LintIssue
+		project, err := loader.Load(&loader.LoaderOptions{
+			FileNames:         lintConfigFiles,
+			EnvFileNames:      lintEnvFiles,
+			ExtraEnvFileNames: lintExtraEnvFiles,
+			EnvVars:           lintEnvVars,
+		})
+		if err != nil {
+			issues = []loader.LintIssue{loadErrorIssue(err)}
+		} else {
+			issues = loader.Lint(project)
+		}
+		switch lintOutputFormat {
+		case "json":
+			b, err := json.MarshalIndent(issues, "", "\t")
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to marshal the lint issues")
+			}
+			fmt.Println(string(b))
+		case "text":
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		default:
+			log.Fatal().Msgf("unknown output format %s", lintOutputFormat)
+		}
+		for _, issue := range issues {
+			if issue.Severity == loader.LintSeverityError {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func loadErrorIssue(err error) loader.LintIssue {
+	issue := loader.LintIssue{
+		Severity: loader.LintSeverityError,
+		Message:  err.Error(),
+	}
+	var validationErr *loader.ValidationError
+	if errors.As(err, &validationErr) {
+		issue.File = validationErr.Location.File
+		issue.Line = validationErr.Location.Line
+		issue.Message = validationErr.Message
+	}
+	return issue
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringArrayVarP(&lintConfigFiles, "config", "f", config.GetConfigDefault(), "path to config files to load (env: "+config.EnvVarNameConfig+")")
+	lintCmd.Flags().StringArrayVarP(&lintEnvFiles, "env", "e", []string{".env"}, "path to env files to load")
+	lintCmd.Flags().StringArrayVar(&lintExtraEnvFiles, "env-file", nil, "path to env files to load, in addition to the --env ones")
+	lintCmd.Flags().StringArrayVar(&lintEnvVars, "var", nil, "KEY=VALUE variables to set in the environment, they override all the other environment sources")
+	lintCmd.Flags().StringVarP(&lintOutputFormat, "output", "o", lintOutputFormat, "Output format. One of: (text, json)")
+}
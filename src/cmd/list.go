@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const maxDescriptionWidth = 40
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:     "list",
@@ -47,7 +49,7 @@ var listCmd = &cobra.Command{
 func printStatesAsTable(states []types.ProcessState) {
 
 	// Create a table
-	table := []string{"PID", "NAME", "NAMESPACE", "STATUS", "AGE", "HEALTH", "RESTARTS", "EXITCODE"}
+	table := []string{"PID", "NAME", "NAMESPACE", "STATUS", "AGE", "HEALTH", "RESTARTS", "EXITCODE", "DESCRIPTION"}
 	tableColWidth := make([]int, len(table))
 
 	for _, state := range states {
@@ -75,6 +77,9 @@ func printStatesAsTable(states []types.ProcessState) {
 		if len(fmt.Sprintf("%d", state.ExitCode)) > tableColWidth[7] {
 			tableColWidth[7] = len(fmt.Sprintf("%d", state.ExitCode))
 		}
+		if len(truncate(state.Description, maxDescriptionWidth)) > tableColWidth[8] {
+			tableColWidth[8] = len(truncate(state.Description, maxDescriptionWidth))
+		}
 	}
 	for i, col := range table {
 		if len(col) > tableColWidth[i] {
@@ -94,11 +99,20 @@ func printStatesAsTable(states []types.ProcessState) {
 		fmt.Printf("%-*s   ", tableColWidth[5], state.Health)
 		fmt.Printf("%-*d   ", tableColWidth[6], state.Restarts)
 		fmt.Printf("%-*d   ", tableColWidth[7], state.ExitCode)
+		fmt.Printf("%-*s   ", tableColWidth[8], truncate(state.Description, maxDescriptionWidth))
 		fmt.Println()
 	}
 
 }
 
+func truncate(str string, maxLen int) string {
+	runes := []rune(str)
+	if len(runes) <= maxLen {
+		return str
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
 func init() {
 	processCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(listCmd)
@@ -0,0 +1,50 @@
+package cmd
+
+import "testing"
+
+func Test_truncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		str    string
+		maxLen int
+		want   string
+	}{
+		{
+			name:   "empty",
+			str:    "",
+			maxLen: 10,
+			want:   "",
+		},
+		{
+			name:   "shorter",
+			str:    "API server",
+			maxLen: 20,
+			want:   "API server",
+		},
+		{
+			name:   "exact length",
+			str:    "API server",
+			maxLen: 10,
+			want:   "API server",
+		},
+		{
+			name:   "longer",
+			str:    "API server of the shop",
+			maxLen: 10,
+			want:   "API ser...",
+		},
+		{
+			name:   "multibyte",
+			str:    "Сервер приложения",
+			maxLen: 9,
+			want:   "Сервер...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.str, tt.maxLen); got != tt.want {
+				t.Errorf("truncate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,13 +3,22 @@ package cmd
 import (
 	"fmt"
 	"github.com/f1bonacc1/process-compose/src/client"
+	"github.com/fatih/color"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"io"
 	"os"
 	"os/signal"
+	"regexp"
+	"strings"
 	"time"
 )
 
+var (
+	logsGrep         string
+	logsOnlyMatching bool
+)
+
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs [PROCESS]",
@@ -18,8 +27,19 @@ var logsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 
+		var out io.StringWriter = os.Stdout
+		if logsGrep != "" {
+			re, err := regexp.Compile(logsGrep)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("Invalid grep pattern %s", logsGrep)
+			}
+			out = newGrepWriter(os.Stdout, re, logsOnlyMatching)
+		} else if logsOnlyMatching {
+			log.Fatal().Msg("--only-matching requires a --grep pattern")
+		}
+
 		logger := getLogClient()
-		done, err := logger.ReadProcessLogs(name, *pcFlags.LogTailLength, *pcFlags.LogFollow, os.Stdout)
+		done, err := logger.ReadProcessLogs(name, *pcFlags.LogTailLength, *pcFlags.LogFollow, out)
 		if err != nil {
 			log.Fatal().Err(err).Msgf("Failed to fetch logs for process %s", name)
 		}
@@ -42,6 +62,43 @@ func init() {
 
 	logsCmd.Flags().BoolVarP(pcFlags.LogFollow, "follow", "f", *pcFlags.LogFollow, "Follow log output")
 	logsCmd.Flags().IntVarP(pcFlags.LogTailLength, "tail", "n", *pcFlags.LogTailLength, "Number of lines to show from the end of the logs")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Highlight the parts of the lines matching the regex pattern")
+	logsCmd.Flags().BoolVarP(&logsOnlyMatching, "only-matching", "o", false, "Show only the lines matching the --grep pattern")
+}
+
+// grepWriter highlights the matches of the pattern in the log lines, and drops the lines that don't match
+// if onlyMatching is set
+type grepWriter struct {
+	out          io.StringWriter
+	re           *regexp.Regexp
+	onlyMatching bool
+	highlight    func(a ...interface{}) string
+}
+
+func newGrepWriter(out io.StringWriter, re *regexp.Regexp, onlyMatching bool) *grepWriter {
+	return &grepWriter{
+		out:          out,
+		re:           re,
+		onlyMatching: onlyMatching,
+		highlight:    color.New(color.Bold, color.FgRed).SprintFunc(),
+	}
+}
+
+func (w *grepWriter) WriteString(line string) (int, error) {
+	text := strings.TrimSuffix(line, "\n")
+	if !w.re.MatchString(text) {
+		if w.onlyMatching {
+			return len(line), nil
+		}
+		return w.out.WriteString(line)
+	}
+	highlighted := w.re.ReplaceAllStringFunc(text, func(match string) string {
+		if match == "" {
+			return match
+		}
+		return w.highlight(match)
+	})
+	return w.out.WriteString(highlighted + line[len(text):])
 }
 
 func getLogClient() *client.LogClient {
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_grepWriter(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		onlyMatching bool
+		lines        []string
+		want         string
+	}{
+		{
+			name:    "highlight",
+			pattern: `err\w*`,
+			lines:   []string{"starting\n", "error: failed, err=42\n", "done\n"},
+			want:    "starting\n<error>: failed, <err>=42\ndone\n",
+		},
+		{
+			name:         "only matching",
+			pattern:      `err\w*`,
+			onlyMatching: true,
+			lines:        []string{"starting\n", "error: failed\n", "done\n"},
+			want:         "<error>: failed\n",
+		},
+		{
+			name:    "empty match",
+			pattern: `x*`,
+			lines:   []string{"abc\n"},
+			want:    "abc\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			w := newGrepWriter(&out, regexp.MustCompile(tt.pattern), tt.onlyMatching)
+			w.highlight = func(a ...interface{}) string {
+				return "<" + a[0].(string) + ">"
+			}
+			for _, line := range tt.lines {
+				if _, err := w.WriteString(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if out.String() != tt.want {
+				t.Errorf("grepWriter output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	normalizeConfigFile string
+	normalizeToStdout   bool
+)
+
+// normalizeCmd represents the normalize command
+var normalizeCmd = &cobra.Command{
+	Use:     "normalize",
+	Aliases: []string{"fmt"},
+	Short:   "Sort and canonicalize the config file",
+	Long: `Rewrite the config file with its keys sorted alphabetically, its comments stripped and a consistent indentation,
+so its diffs show only meaningful changes. The file is updated in place, unless --stdout is set`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file := normalizeConfigFile
+		if file == "" {
+			var err error
+			if file, err = loader.DiscoverConfigFile(); err != nil {
+				log.Fatal().Err(err).Msg("failed to find the config file")
+			}
+		}
+		if normalizeToStdout {
+			data, err := loader.Normalize(file)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("failed to normalize %s", file)
+			}
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err := loader.NormalizeFile(file); err != nil {
+			log.Fatal().Err(err).Msgf("failed to normalize %s", file)
+		}
+		fmt.Printf("%s normalized\n", file)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(normalizeCmd)
+
+	normalizeCmd.Flags().StringVarP(&normalizeConfigFile, "config", "f", "", "config file to normalize (default: the auto discovered config file)")
+	normalizeCmd.Flags().BoolVar(&normalizeToStdout, "stdout", false, "print the normalized config instead of updating the file")
+}
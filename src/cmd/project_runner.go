@@ -1,14 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/f1bonacc1/process-compose/src/config"
 	"github.com/f1bonacc1/process-compose/src/loader"
 	"github.com/f1bonacc1/process-compose/src/tui"
+	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog/log"
 	"os"
 	"os/signal"
-	"syscall"
+	"time"
 )
 
 func getProjectRunner(process []string, noDeps bool, mainProcess string, mainProcessArgs []string) *app.ProjectRunner {
@@ -23,7 +25,15 @@ func getProjectRunner(process []string, noDeps bool, mainProcess string, mainPro
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load project")
 	}
+	if project.ReaperMode {
+		runAsReaper()
+	} else if os.Getpid() == 1 {
+		log.Warn().Msg("process-compose runs as PID 1 without reaper_mode, the orphan processes won't be reaped")
+	}
 	*pcFlags.IsTuiEnabled = !project.IsTuiDisabled
+	if project.LogFormat == types.LogFormatJSON && logFile != nil {
+		setLogWriters(logFile, project.LogFormat)
+	}
 
 	prjOpts := app.ProjectOpts{}
 
@@ -34,6 +44,8 @@ func getProjectRunner(process []string, noDeps bool, mainProcess string, mainPro
 			WithProject(project).
 			WithProcessesToRun(process).
 			WithOrderedShutDown(*pcFlags.IsOrderedShutDown).
+			WithWatchMode(*pcFlags.IsWatchMode).
+			WithIsolation(*pcFlags.IsIsolated).
 			WithNoDeps(noDeps),
 	)
 	if err != nil {
@@ -57,27 +69,39 @@ func runProject(runner *app.ProjectRunner) error {
 	return err
 }
 
-func setSignal(signalHandler func()) {
+// setSignal calls the signalHandler on the first of the signals, and the killHandler on a second signal
+// or when the processes didn't shut down within the grace period
+func setSignal(signalHandler, killHandler func(), gracePeriod time.Duration, sigs ...os.Signal) {
 	cancelChan := make(chan os.Signal, 1)
-	signal.Notify(cancelChan, syscall.SIGTERM, os.Interrupt, syscall.SIGHUP)
+	signal.Notify(cancelChan, sigs...)
 	go func() {
 		sig := <-cancelChan
 		log.Info().Msgf("Caught %v - Shutting down the running processes...", sig)
 		signalHandler()
+		select {
+		case sig = <-cancelChan:
+			log.Warn().Msgf("Caught %v again - Killing the running processes...", sig)
+		case <-time.After(gracePeriod):
+			log.Warn().Msgf("Processes didn't shut down within %v - Killing them...", gracePeriod)
+		}
+		killHandler()
 	}()
 }
 
 func runHeadless(project *app.ProjectRunner) error {
-	setSignal(func() {
-		_ = project.ShutDownProject()
-	})
-	return project.Run()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	setSignal(cancel, project.KillProject, project.GetGracePeriod(), project.ShutdownSignals()...)
+	return project.Run(ctx)
 }
 
 func runTui(project *app.ProjectRunner) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	setSignal(cancel, project.KillProject, project.GetGracePeriod(), project.ShutdownSignals()...)
 	startTui(project, true)
-	err := project.Run()
-	if !*pcFlags.KeepProjectOn && !*pcFlags.KeepTuiOn {
+	err := project.Run(ctx)
+	if ctx.Err() != nil || (!*pcFlags.KeepProjectOn && !*pcFlags.KeepTuiOn) {
 		tui.Stop()
 	} else {
 		tui.Wait()
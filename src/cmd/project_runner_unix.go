@@ -7,9 +7,12 @@ import (
 	"github.com/rs/zerolog/log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"syscall"
 )
 
+const envReaperChild = "PC_REAPER_CHILD"
+
 func runInDetachedMode() {
 	log.Info().Msg("Running in detached mode")
 	fmt.Println("Starting Process Compose in detached mode. Use 'process-compose attach' to connect to it or 'process-compose down' to stop it")
@@ -40,3 +43,69 @@ func runInDetachedMode() {
 	// Exit the parent process
 	os.Exit(0)
 }
+
+// runAsReaper runs process-compose in a child process and reaps the orphan processes re-parented to
+// this one (running as PID 1) until the child exits. The signals are forwarded to the child.
+// Only the child process returns from runAsReaper
+func runAsReaper() {
+	if os.Getenv(envReaperChild) != "" {
+		return
+	}
+	log.Info().Msg("Running in reaper mode")
+	sigs := make(chan os.Signal, 16)
+	signal.Notify(sigs)
+	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	cmd.Env = append(os.Environ(), envReaperChild+"=1")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start process-compose in reaper mode")
+	}
+	superviseChild(cmd, sigs)
+}
+
+// superviseChild forwards the signals to the child and reaps the orphan processes until the child exits, then
+// exits with the child exit code
+func superviseChild(cmd *exec.Cmd, sigs chan os.Signal) {
+	for sig := range sigs {
+		switch sig {
+		case syscall.SIGCHLD:
+			if status, exited := reapZombies(cmd.Process.Pid); exited {
+				os.Exit(waitStatusExitCode(status))
+			}
+		case syscall.SIGURG:
+			// used by the Go runtime for goroutine preemption
+		default:
+			_ = cmd.Process.Signal(sig)
+		}
+	}
+}
+
+// reapZombies reaps all the exited children. It returns true, with its status, if pid is one of them
+func reapZombies(pid int) (syscall.WaitStatus, bool) {
+	var childStatus syscall.WaitStatus
+	exited := false
+	for {
+		var status syscall.WaitStatus
+		reaped, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil || reaped <= 0 {
+			return childStatus, exited
+		}
+		if reaped == pid {
+			childStatus, exited = status, true
+		} else {
+			log.Debug().Msgf("Reaped orphan process %d", reaped)
+		}
+	}
+}
+
+func waitStatusExitCode(status syscall.WaitStatus) int {
+	if status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return status.ExitStatus()
+}
@@ -0,0 +1,51 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func Test_waitStatusExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   int
+	}{
+		{
+			name:   "exited",
+			script: "exit 0",
+			want:   0,
+		},
+		{
+			name:   "exited with code",
+			script: "exit 3",
+			want:   3,
+		},
+		{
+			name:   "signaled",
+			script: "kill -TERM $$",
+			want:   128 + int(syscall.SIGTERM),
+		},
+		{
+			name:   "killed",
+			script: "kill -KILL $$",
+			want:   128 + int(syscall.SIGKILL),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("sh", "-c", tt.script)
+			_ = cmd.Run()
+			status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+			if !ok {
+				t.Fatalf("unexpected process state %T", cmd.ProcessState.Sys())
+			}
+			if got := waitStatusExitCode(status); got != tt.want {
+				t.Errorf("waitStatusExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,3 +5,7 @@ import "github.com/rs/zerolog/log"
 func runInDetachedMode() {
 	log.Fatal().Msg("Running in detached mode is not supported on Windows")
 }
+
+func runAsReaper() {
+	log.Warn().Msg("Reaper mode is not supported on Windows")
+}
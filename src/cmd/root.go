@@ -9,6 +9,7 @@ import (
 	"github.com/f1bonacc1/process-compose/src/client"
 	"github.com/f1bonacc1/process-compose/src/config"
 	"github.com/f1bonacc1/process-compose/src/loader"
+	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -60,7 +61,10 @@ func runProjectCmd(args []string) {
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-
+	runAsIsolationInit()
+	runAsUlimitExec()
+	registerIsolationInit()
+	registerUlimitExec()
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -80,11 +84,15 @@ func init() {
 	rootCmd.Flags().BoolVar(pcFlags.KeepProjectOn, "keep-project", *pcFlags.KeepProjectOn, "keep the project running even after all processes exit")
 	rootCmd.PersistentFlags().BoolVar(pcFlags.NoServer, "no-server", *pcFlags.NoServer, "disable HTTP server (env: "+config.EnvVarNameNoServer+")")
 	rootCmd.PersistentFlags().BoolVar(pcFlags.IsOrderedShutDown, "ordered-shutdown", *pcFlags.IsOrderedShutDown, "shut down processes in reverse dependency order")
+	rootCmd.PersistentFlags().BoolVar(pcFlags.IsIsolated, "isolate", *pcFlags.IsIsolated, "run all the processes in new PID and mount namespaces (Linux only)")
+	rootCmd.PersistentFlags().BoolVar(pcFlags.IsWatchMode, "watch", *pcFlags.IsWatchMode, "restart the processes that depend on a process restarted by its watch_paths changes")
 	rootCmd.Flags().BoolVarP(pcFlags.HideDisabled, "hide-disabled", "d", *pcFlags.HideDisabled, "hide disabled processes (env: "+config.EnvVarHideDisabled+")")
 	rootCmd.Flags().VarP(refreshRateFlag{pcFlags.RefreshRate}, "ref-rate", "r", "TUI refresh rate in seconds or as a Go duration string (e.g. 1s)")
 	rootCmd.PersistentFlags().IntVarP(pcFlags.PortNum, "port", "p", *pcFlags.PortNum, "port number (env: "+config.EnvVarNamePort+")")
 	rootCmd.Flags().StringArrayVarP(&opts.FileNames, "config", "f", config.GetConfigDefault(), "path to config files to load (env: "+config.EnvVarNameConfig+")")
 	rootCmd.Flags().StringArrayVarP(&opts.EnvFileNames, "env", "e", []string{".env"}, "path to env files to load")
+	rootCmd.Flags().StringArrayVar(&opts.ExtraEnvFileNames, "env-file", nil, "path to env files to load, in addition to the --env ones")
+	rootCmd.Flags().StringArrayVar(&opts.EnvVars, "var", nil, "KEY=VALUE variables to set in the environment, they override all the other environment sources")
 	rootCmd.Flags().StringArrayVarP(&nsAdmitter.EnabledNamespaces, "namespace", "n", nil, "run only specified namespaces (default all)")
 	rootCmd.PersistentFlags().StringVarP(pcFlags.LogFile, "log-file", "L", *pcFlags.LogFile, "Specify the log file path (env: "+config.LogPathEnvVarName+")")
 	rootCmd.PersistentFlags().BoolVar(pcFlags.IsReadOnlyMode, "read-only", *pcFlags.IsReadOnlyMode, "enable read-only mode (env: "+config.EnvVarReadOnlyMode+")")
@@ -119,6 +127,22 @@ func setupLogger() *os.File {
 	if err != nil {
 		logFatal(err, "Failed to open log file: %s", *pcFlags.LogFile)
 	}
+	setLogWriters(file, types.LogFormatText)
+	zerolog.TimeFieldFormat = time.RFC3339Nano
+	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	return file
+}
+
+// setLogWriters sets the process compose log output in the text (console) or json format
+func setLogWriters(file *os.File, format string) {
+	if format == types.LogFormatJSON {
+		writer := zerolog.MultiLevelWriter(
+			&zerolog.FilteredLevelWriter{Level: zerolog.DebugLevel, Writer: zerolog.LevelWriterAdapter{Writer: file}},
+			&zerolog.FilteredLevelWriter{Level: zerolog.FatalLevel, Writer: zerolog.LevelWriterAdapter{Writer: os.Stderr}},
+		)
+		setGlobalLogger(writer)
+		return
+	}
 	writers := []io.Writer{
 		&zerolog.FilteredLevelWriter{
 
@@ -137,17 +161,16 @@ func setupLogger() *os.File {
 			}},
 		},
 	}
-	writer := zerolog.MultiLevelWriter(writers...)
+	setGlobalLogger(zerolog.MultiLevelWriter(writers...))
+}
 
+func setGlobalLogger(writer io.Writer) {
 	// add caller only in debug mode
 	if os.Getenv("PC_DEBUG") != "" {
 		log.Logger = zerolog.New(writer).With().Timestamp().Caller().Logger()
 	} else {
 		log.Logger = zerolog.New(writer).With().Timestamp().Logger()
 	}
-	zerolog.TimeFieldFormat = time.RFC3339Nano
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	return file
 }
 
 // Logs and exits with a non-zero code if there are any errors.
@@ -11,6 +11,8 @@ var runCmd = &cobra.Command{
 	Use:   "run PROCESS [flags] -- [process_args]",
 	Short: "Run PROCESS in the foreground, and its dependencies in the background",
 	Long: `Run selected process with std(in|out|err) attached, while other processes run in the background.
+Once PROCESS completes (or is skipped due to a failed dependency), its dependencies are stopped
+and process-compose exits with the PROCESS exit code.
 Command line arguments, provided after --, are passed to the PROCESS.`,
 	Args: cobra.MinimumNArgs(1),
 	// Args: cobra.ExactArgs(1),
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec PROCESS -- COMMAND [args...]",
+	Short: "Run a command in the environment of PROCESS",
+	Long: `Run a one-off command with the environment and working directory PROCESS would run with.
+PROCESS itself is not started. For example:
+process-compose exec api -- printenv DATABASE_URL`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		*pcFlags.IsTuiEnabled = false
+		processName := args[0]
+		if cmd.ArgsLenAtDash() != 1 {
+			fmt.Println("Separate the COMMAND from the PROCESS name with: --")
+			os.Exit(1)
+		}
+		args = args[1:]
+
+		runner := getProjectRunner([]string{}, false, "", []string{})
+		env, workingDir, err := runner.GetProcessEnvironment(processName)
+		if err != nil {
+			logFatal(err, "failed to get the environment of %s", processName)
+		}
+
+		command := exec.Command(args[0], args[1:]...)
+		command.Env = env
+		command.Dir = workingDir
+		command.Stdin = os.Stdin
+		command.Stdout = os.Stdout
+		command.Stderr = os.Stderr
+		if err = command.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			logFatal(err, "failed to run %s", args[0])
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().AddFlag(rootCmd.Flags().Lookup("config"))
+	execCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env"))
+	execCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env-file"))
+	execCmd.Flags().AddFlag(rootCmd.Flags().Lookup("var"))
+	execCmd.Flags().AddFlag(rootCmd.Flags().Lookup("disable-dotenv"))
+}
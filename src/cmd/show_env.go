@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const redactedValue = "[REDACTED]"
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env PROCESS",
+	Short: "Print the environment of PROCESS",
+	Long: `Print the resolved environment PROCESS would run with, one KEY=VALUE per line, sorted by key.
+PROCESS itself is not started. The values of the secret_env_vars, and of the auto detected secrets, are shown as [REDACTED].
+The variables added at run time, by the bootstrap_command or from env_from_process, are not included`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		*pcFlags.IsTuiEnabled = false
+		processName := args[0]
+		runner := getProjectRunner([]string{}, false, "", []string{})
+		env, _, err := runner.GetProcessEnvironment(processName)
+		if err != nil {
+			logFatal(err, "failed to get the environment of %s", processName)
+		}
+		for _, pair := range resolveEnv(env, runner.IsSecretEnvVar) {
+			fmt.Println(pair)
+		}
+	},
+}
+
+// resolveEnv keeps the last value of each key, as the process receives it, and redacts the secret values
+func resolveEnv(env []string, isSecret func(key string) bool) []string {
+	values := map[string]string{}
+	for _, pair := range env {
+		key, value, _ := strings.Cut(pair, "=")
+		values[key] = value
+	}
+	resolved := make([]string, 0, len(values))
+	for key, value := range values {
+		if isSecret(key) {
+			value = redactedValue
+		}
+		resolved = append(resolved, key+"="+value)
+	}
+	sort.Strings(resolved)
+	return resolved
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().AddFlag(rootCmd.Flags().Lookup("config"))
+	envCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env"))
+	envCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env-file"))
+	envCmd.Flags().AddFlag(rootCmd.Flags().Lookup("var"))
+	envCmd.Flags().AddFlag(rootCmd.Flags().Lookup("disable-dotenv"))
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_resolveEnv(t *testing.T) {
+	isSecret := func(key string) bool {
+		return key == "DB_PASSWORD"
+	}
+	tests := []struct {
+		name string
+		env  []string
+		want []string
+	}{
+		{
+			name: "sorted by key",
+			env:  []string{"B=2", "A=1", "C=3"},
+			want: []string{"A=1", "B=2", "C=3"},
+		},
+		{
+			name: "last value wins",
+			env:  []string{"HOST=os", "PORT=80", "HOST=process"},
+			want: []string{"HOST=process", "PORT=80"},
+		},
+		{
+			name: "secret redacted",
+			env:  []string{"DB_PASSWORD=os", "DB_USER=admin", "DB_PASSWORD=s3cr3t"},
+			want: []string{"DB_PASSWORD=[REDACTED]", "DB_USER=admin"},
+		},
+		{
+			name: "value with equal sign and empty value",
+			env:  []string{"OPTS=a=b", "EMPTY=", "NO_VALUE"},
+			want: []string{"EMPTY=", "NO_VALUE=", "OPTS=a=b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveEnv(tt.env, isSecret); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
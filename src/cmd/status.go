@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var statusQuiet = false
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [PROCESS]",
+	Short: "Print the processes state as JSON",
+	Long: `Print the state of all the processes, or of a single process, as JSON.
+The exit code is 1 if any of the processes failed, otherwise 0`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var states []types.ProcessState
+		var output any
+		if len(args) == 1 {
+			state, err := getClient().GetProcessState(args[0])
+			if err != nil {
+				logFatal(err, "failed to get process %s state", args[0])
+			}
+			states = []types.ProcessState{*state}
+			output = state
+		} else {
+			processes, err := getClient().GetRemoteProcessesState()
+			if err != nil {
+				logFatal(err, "failed to get the processes state")
+			}
+			states = processes.States
+			output = states
+		}
+		if !statusQuiet {
+			b, err := json.MarshalIndent(output, "", "\t")
+			if err != nil {
+				log.Fatal().Err(err).Msg("failed to marshal the processes state")
+			}
+			fmt.Println(string(b))
+		}
+		for _, state := range states {
+			if isFailedState(&state) {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// isFailedState returns true if the process failed to run, was skipped as its dependencies failed, exited with an
+// error or is not ready
+func isFailedState(state *types.ProcessState) bool {
+	switch state.Status {
+	case types.ProcessStateError, types.ProcessStateSkipped:
+		return true
+	case types.ProcessStateCompleted, types.ProcessStateRestarting:
+		if state.ExitCode != 0 {
+			return true
+		}
+	}
+	return state.Health == types.ProcessHealthNotReady
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().BoolVarP(&statusQuiet, "quiet", "q", false, "don't print the state, only set the exit code")
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func Test_isFailedState(t *testing.T) {
+	tests := []struct {
+		name  string
+		state types.ProcessState
+		want  bool
+	}{
+		{
+			name:  "running",
+			state: types.ProcessState{Status: types.ProcessStateRunning},
+			want:  false,
+		},
+		{
+			name:  "running and ready",
+			state: types.ProcessState{Status: types.ProcessStateRunning, Health: types.ProcessHealthReady},
+			want:  false,
+		},
+		{
+			name:  "running and not ready",
+			state: types.ProcessState{Status: types.ProcessStateRunning, Health: types.ProcessHealthNotReady},
+			want:  true,
+		},
+		{
+			name:  "completed",
+			state: types.ProcessState{Status: types.ProcessStateCompleted},
+			want:  false,
+		},
+		{
+			name:  "completed with error",
+			state: types.ProcessState{Status: types.ProcessStateCompleted, ExitCode: 2},
+			want:  true,
+		},
+		{
+			name:  "restarting after an error",
+			state: types.ProcessState{Status: types.ProcessStateRestarting, ExitCode: 1},
+			want:  true,
+		},
+		{
+			name:  "error",
+			state: types.ProcessState{Status: types.ProcessStateError},
+			want:  true,
+		},
+		{
+			name:  "skipped",
+			state: types.ProcessState{Status: types.ProcessStateSkipped},
+			want:  true,
+		},
+		{
+			name:  "disabled",
+			state: types.ProcessState{Status: types.ProcessStateDisabled},
+			want:  false,
+		},
+		{
+			name:  "pending",
+			state: types.ProcessState{Status: types.ProcessStatePending},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFailedState(&tt.state); got != tt.want {
+				t.Errorf("isFailedState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const defaultTimelineWidth = 80
+
+var (
+	timelineFormat string
+	timelineWidth  int
+)
+
+// timelineCmd represents the timeline command
+var timelineCmd = &cobra.Command{
+	Use:   "timeline SUMMARY_FILE",
+	Short: "Show a timeline of a completed run",
+	Long: `Render the processes lifecycle of a completed run, read from its summary_file, as a Gantt chart on a shared time axis.
+The text chart is scaled to the terminal width, the SVG chart can be embedded in documentation`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		summary, err := app.ReadRunSummary(args[0])
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to read the run summary")
+		}
+		switch timelineFormat {
+		case "text":
+			width := timelineWidth
+			if width <= 0 {
+				width = defaultTimelineWidth
+				if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+					width = w
+				}
+			}
+			fmt.Print(app.RenderTimelineText(summary, width))
+		case "svg":
+			fmt.Print(app.RenderTimelineSVG(summary))
+		default:
+			log.Fatal().Msgf("unsupported output format %s", timelineFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timelineCmd)
+
+	timelineCmd.Flags().StringVar(&timelineFormat, "format", "text", "output format: text or svg")
+	timelineCmd.Flags().IntVar(&timelineWidth, "width", 0, "text chart width (default: the terminal width)")
+}
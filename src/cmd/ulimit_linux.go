@@ -0,0 +1,46 @@
+//go:build linux
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/f1bonacc1/process-compose/src/command"
+)
+
+// registerUlimitExec makes this executable set the ulimits of the processes before their commands are executed
+func registerUlimitExec() {
+	if exe, err := os.Executable(); err == nil {
+		command.SetUlimitExec(exe)
+	}
+}
+
+// runAsUlimitExec sets the ulimits of a process and execs its command in place of this process, so the limits
+// apply from its start and to everything it forks. It returns only if this process isn't a ulimit exec
+func runAsUlimitExec() {
+	value := os.Getenv(command.EnvUlimits)
+	if value == "" || len(os.Args) < 2 {
+		return
+	}
+	_ = os.Unsetenv(command.EnvUlimits)
+	limits, err := command.ParseUlimits(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "process-compose: %v\n", err)
+	}
+	for resource, soft := range limits {
+		var limit syscall.Rlimit
+		if err = syscall.Getrlimit(resource, &limit); err == nil {
+			limit.Cur = soft
+			// syscall.Setrlimit keeps the exec from restoring the original open files limit
+			err = syscall.Setrlimit(resource, &limit)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "process-compose: failed to set the limit of resource %d: %v\n", resource, err)
+		}
+	}
+	err = syscall.Exec(os.Args[1], os.Args[1:], os.Environ())
+	fmt.Fprintf(os.Stderr, "process-compose: %v\n", err)
+	os.Exit(127)
+}
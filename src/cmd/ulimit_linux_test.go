@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/app"
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"golang.org/x/sys/unix"
+)
+
+func TestUlimitExec(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	command.SetUlimitExec(exe)
+	defer command.SetUlimitExec("")
+	var limit unix.Rlimit
+	if err = unix.Getrlimit(unix.RLIMIT_NOFILE, &limit); err != nil {
+		t.Fatalf("%s", err)
+	}
+	want := strconv.FormatUint(limit.Max/2, 10)
+	shell := command.DefaultShellConfig()
+	project := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"limited": {
+				Name:        "limited",
+				ReplicaName: "limited",
+				Executable:  shell.ShellCommand,
+				// the forked subshell inherits the limit the shell was started with
+				Args:    []string{shell.ShellArgument, "(ulimit -n)"},
+				Ulimits: types.Ulimits{"nofile": int64(limit.Max / 2)},
+			},
+		},
+		ShellConfig: shell,
+		LogLength:   10,
+	}
+	runner, err := app.NewProjectRunner((&app.ProjectOpts{}).WithProject(project))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = runner.Run(context.Background()); err != nil {
+		t.Fatalf("%s", err)
+	}
+	logs, err := runner.GetProcessLog("limited", 10, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !slices.Equal(logs, []string{want}) {
+		t.Errorf("expected the process to start with nofile %s, got %q", want, logs)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package cmd
+
+func registerUlimitExec() {}
+
+func runAsUlimitExec() {}
@@ -28,6 +28,8 @@ func init() {
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("namespace"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("config"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("env-file"))
+	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("var"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("ref-rate"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("tui"))
 	upCmd.Flags().AddFlag(rootCmd.Flags().Lookup("hide-disabled"))
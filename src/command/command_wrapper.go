@@ -7,13 +7,24 @@ import (
 )
 
 type CmdWrapper struct {
-	cmd *exec.Cmd
+	cmd      *exec.Cmd
+	isolated bool
+	ulimits  map[int]uint64
 }
 
 func (c *CmdWrapper) Start() error {
+	c.applyUlimits()
+	if c.isolated {
+		c.applyIsolation()
+	}
 	return c.cmd.Start()
 }
 
+// Isolate starts the process in new PID and mount namespaces
+func (c *CmdWrapper) Isolate() {
+	c.isolated = true
+}
+
 func (c *CmdWrapper) Run() error {
 	return c.cmd.Run()
 }
@@ -42,6 +53,10 @@ func (c *CmdWrapper) StdinPipe() (io.WriteCloser, error) {
 	return c.cmd.StdinPipe()
 }
 
+func (c *CmdWrapper) SetStdin(stdin io.Reader) {
+	c.cmd.Stdin = stdin
+}
+
 func (c *CmdWrapper) AttachIo() {
 	c.cmd.Stdin = os.Stdin
 	c.cmd.Stdout = os.Stdout
@@ -55,3 +70,8 @@ func (c *CmdWrapper) SetEnv(env []string) {
 func (c *CmdWrapper) SetDir(dir string) {
 	c.cmd.Dir = dir
 }
+
+// CombinedOutput runs the command and returns its stdout and stderr output
+func (c *CmdWrapper) CombinedOutput() ([]byte, error) {
+	return c.cmd.CombinedOutput()
+}
@@ -17,6 +17,10 @@ func (c *CmdWrapperPty) Start() (err error) {
 	if c.ptmx != nil {
 		return nil
 	}
+	c.applyUlimits()
+	if c.isolated {
+		c.applyIsolation()
+	}
 	c.ptmx, err = pty.Start(c.cmd)
 	// No need to capture/restore old state, because we close the PTY when we're done.
 	_, err = term.MakeRaw(int(c.ptmx.Fd()))
@@ -4,7 +4,10 @@ import "io"
 
 type Commander interface {
 	Stop(sig int, _parentOnly bool) error
+	Signal(sig int, parentOnly bool) error
 	SetCmdArgs()
+	Isolate()
+	SetUlimits(limits map[int]uint64)
 	Start() error
 	Run() error
 	Wait() error
@@ -13,6 +16,7 @@ type Commander interface {
 	StdoutPipe() (io.ReadCloser, error)
 	StderrPipe() (io.ReadCloser, error)
 	StdinPipe() (io.WriteCloser, error)
+	SetStdin(stdin io.Reader)
 	AttachIo()
 	SetEnv(env []string)
 	SetDir(dir string)
@@ -0,0 +1,12 @@
+package command
+
+// EnvIsolationInit marks the process-compose process that runs as the init of an isolated process
+const EnvIsolationInit = "PC_ISOLATION_INIT"
+
+var isolationInit string
+
+// SetIsolationInit sets the executable that runs as the init (PID 1) of the isolated processes and starts their
+// commands. Without it the command itself is the init of its PID namespace
+func SetIsolationInit(path string) {
+	isolationInit = path
+}
@@ -0,0 +1,37 @@
+//go:build linux
+
+package command
+
+import (
+	"os"
+	"syscall"
+)
+
+// applyIsolation runs the process in new PID and mount namespaces. The mount namespace is private, so the
+// process mounts (e.g. the /proc of its PID namespace) aren't propagated to the host. An unprivileged user also
+// runs it in a new user namespace, as its root mapped to the host user and group, so the init has the capabilities
+// to mount the /proc of the namespace
+func (c *CmdWrapper) applyIsolation() {
+	if c.cmd.SysProcAttr == nil {
+		c.cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	attr := c.cmd.SysProcAttr
+	attr.Cloneflags |= syscall.CLONE_NEWPID
+	attr.Unshareflags |= syscall.CLONE_NEWNS
+	if uid := os.Geteuid(); uid != 0 {
+		gid := os.Getegid()
+		attr.Cloneflags |= syscall.CLONE_NEWUSER
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: uid, Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: gid, Size: 1}}
+		attr.GidMappingsEnableSetgroups = false
+	}
+	if isolationInit == "" {
+		return
+	}
+	if c.cmd.Env == nil {
+		c.cmd.Env = os.Environ()
+	}
+	c.cmd.Env = append(c.cmd.Env, EnvIsolationInit+"=1")
+	c.cmd.Args = append([]string{isolationInit, c.cmd.Path}, c.cmd.Args[1:]...)
+	c.cmd.Path = isolationInit
+}
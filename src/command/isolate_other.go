@@ -0,0 +1,10 @@
+//go:build !linux
+
+package command
+
+import "github.com/rs/zerolog/log"
+
+// applyIsolation is only supported on Linux, the process runs without isolation
+func (c *CmdWrapper) applyIsolation() {
+	log.Warn().Msgf("process isolation is only supported on Linux, running %s without it", c.cmd.Path)
+}
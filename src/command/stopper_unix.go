@@ -3,7 +3,10 @@
 package command
 
 import (
+	"fmt"
 	"github.com/rs/zerolog/log"
+	"strconv"
+	"strings"
 	"syscall"
 )
 
@@ -39,6 +42,55 @@ func (c *CmdWrapper) Stop(sig int, parentOnly bool) error {
 	return err
 }
 
+// Signal sends the signal to the process group, or to the process only if parentOnly, without stopping it
+func (c *CmdWrapper) Signal(sig int, parentOnly bool) error {
+	if c.cmd == nil || c.cmd.Process == nil {
+		return fmt.Errorf("process is not running")
+	}
+	if parentOnly {
+		return c.cmd.Process.Signal(syscall.Signal(sig))
+	}
+	pgid, err := syscall.Getpgid(c.Pid())
+	if err != nil {
+		return err
+	}
+	return syscall.Kill(-pgid, syscall.Signal(sig))
+}
+
+var signals = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGABRT": syscall.SIGABRT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// ParseSignal converts a signal name (SIGKILL or KILL) or number to its number
+func ParseSignal(name string) (int, error) {
+	if num, err := strconv.Atoi(name); err == nil {
+		if num < min_sig || num > max_sig {
+			return 0, fmt.Errorf("invalid signal number %d", num)
+		}
+		return num, nil
+	}
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if sig, ok := signals[name]; ok {
+		return int(sig), nil
+	}
+	return 0, fmt.Errorf("unknown signal %s", name)
+}
+
 func (c *CmdWrapper) SetCmdArgs() {
 	c.cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
+
+// Detach starts the process in a new session, so it isn't affected by the process-compose termination
+func (c *CmdWrapper) Detach() {
+	c.cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -1,16 +1,37 @@
 package command
 
 import (
+	"fmt"
 	"os/exec"
 	"strconv"
+	"syscall"
 )
 
+const detachedProcess = 0x00000008
+
 func (c *CmdWrapper) Stop(sig int, _parentOnly bool) error {
 	//p.command.Process.Kill()
 	kill := exec.Command("TASKKILL", "/T", "/F", "/PID", strconv.Itoa(c.Pid()))
 	return kill.Run()
 }
 
+// Signal is not supported, Windows processes can't handle signals
+func (c *CmdWrapper) Signal(_ int, _ bool) error {
+	return fmt.Errorf("sending signals is not supported on Windows")
+}
+
+// ParseSignal always returns SIGKILL, processes are forcefully terminated on Windows
+func ParseSignal(_ string) (int, error) {
+	return int(syscall.SIGKILL), nil
+}
+
 func (c *CmdWrapper) SetCmdArgs() {
 	//empty for windows
 }
+
+// Detach starts the process without a console in a new process group
+func (c *CmdWrapper) Detach() {
+	c.cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess,
+	}
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvUlimits marks the process-compose process that sets the ulimits of a process before it execs its command.
+// Its value is the soft limits, as comma separated resource=limit pairs
+const EnvUlimits = "PC_ULIMITS"
+
+var ulimitExec string
+
+// SetUlimitExec sets the executable that sets the ulimits of the processes and then execs their commands
+func SetUlimitExec(path string) {
+	ulimitExec = path
+}
+
+// CanExecWithUlimits returns true if the ulimits of a process can be set before its command is executed
+func CanExecWithUlimits() bool {
+	return ulimitExec != ""
+}
+
+// SetUlimits sets the soft limits of the resources, applied before the command is executed
+func (c *CmdWrapper) SetUlimits(limits map[int]uint64) {
+	c.ulimits = limits
+}
+
+// applyUlimits runs the command through the ulimit exec, which sets the limits and execs the command in its place
+func (c *CmdWrapper) applyUlimits() {
+	if len(c.ulimits) == 0 || ulimitExec == "" {
+		return
+	}
+	if c.cmd.Env == nil {
+		c.cmd.Env = os.Environ()
+	}
+	c.cmd.Env = append(c.cmd.Env, EnvUlimits+"="+formatUlimits(c.ulimits))
+	c.cmd.Args = append([]string{ulimitExec, c.cmd.Path}, c.cmd.Args[1:]...)
+	c.cmd.Path = ulimitExec
+}
+
+func formatUlimits(limits map[int]uint64) string {
+	pairs := make([]string, 0, len(limits))
+	for resource, limit := range limits {
+		pairs = append(pairs, strconv.Itoa(resource)+"="+strconv.FormatUint(limit, 10))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseUlimits parses the EnvUlimits value into the soft limit of each resource
+func ParseUlimits(value string) (map[int]uint64, error) {
+	limits := map[int]uint64{}
+	for _, pair := range strings.Split(value, ",") {
+		resource, limit, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid ulimit %q", pair)
+		}
+		res, err := strconv.Atoi(resource)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ulimit resource %q: %w", resource, err)
+		}
+		lim, err := strconv.ParseUint(limit, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ulimit %q value: %w", resource, err)
+		}
+		limits[res] = lim
+	}
+	return limits, nil
+}
@@ -29,15 +29,17 @@ const (
 )
 
 const (
-	EnvVarNamePort       = "PC_PORT_NUM"
-	EnvVarNameTui        = "PC_DISABLE_TUI"
-	EnvVarNameConfig     = "PC_CONFIG_FILES"
-	EnvVarNameNoServer   = "PC_NO_SERVER"
-	EnvVarUnixSocketPath = "PC_SOCKET_PATH"
-	EnvVarReadOnlyMode   = "PC_READ_ONLY"
-	EnvVarDisableDotEnv  = "PC_DISABLE_DOTENV"
-	EnvVarTuiFullScreen  = "PC_TUI_FULL_SCREEN"
-	EnvVarHideDisabled   = "PC_HIDE_DISABLED_PROC"
+	EnvVarNamePort          = "PC_PORT_NUM"
+	EnvVarNameTui           = "PC_DISABLE_TUI"
+	EnvVarNameConfig        = "PC_CONFIG_FILES"
+	EnvVarNameConfigContent = "PROCESS_COMPOSE_CONFIG"
+	EnvVarNameNoServer      = "PC_NO_SERVER"
+	EnvVarUnixSocketPath    = "PC_SOCKET_PATH"
+	EnvVarReadOnlyMode      = "PC_READ_ONLY"
+	EnvVarDisableDotEnv     = "PC_DISABLE_DOTENV"
+	EnvVarTuiFullScreen     = "PC_TUI_FULL_SCREEN"
+	EnvVarHideDisabled      = "PC_HIDE_DISABLED_PROC"
+	EnvVarDebugMode         = "PC_DEBUG_MODE"
 )
 
 // Flags represents PC configuration flags.
@@ -62,6 +64,8 @@ type Flags struct {
 	KeepTuiOn         *bool
 	KeepProjectOn     *bool
 	IsOrderedShutDown *bool
+	IsWatchMode       *bool
+	IsIsolated        *bool
 	PcTheme           *string
 	PcThemeChanged    bool
 	UnixSocketPath    *string
@@ -93,6 +97,8 @@ func NewFlags() *Flags {
 		KeepTuiOn:         toPtr(false),
 		KeepProjectOn:     toPtr(false),
 		IsOrderedShutDown: toPtr(false),
+		IsWatchMode:       toPtr(false),
+		IsIsolated:        toPtr(false),
 		PcTheme:           toPtr(DefaultThemeName),
 		UnixSocketPath:    toPtr(""),
 		IsUnixSocket:      toPtr(false),
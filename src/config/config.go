@@ -108,6 +108,11 @@ func CreateProcCompHome() string {
 	return xdgPcHome
 }
 
+// GetStateHome returns the directory of the projects state directories
+func GetStateHome() string {
+	return filepath.Join(xdg.StateHome, configHome)
+}
+
 func getProcConfigDir() string {
 	if env := os.Getenv(pcConfigEnv); env != "" {
 		return env
@@ -119,6 +124,20 @@ func getProcConfigDir() string {
 	return xdgPcHome
 }
 
+// GetGlobalConfigDirs returns the directories searched for a global project configuration
+func GetGlobalConfigDirs() []string {
+	dirs := []string{filepath.Join(xdg.ConfigHome, configHome)}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to retrieve user home directory")
+		return dirs
+	}
+	if dotConfig := filepath.Join(home, ".config", configHome); dotConfig != dirs[0] {
+		dirs = append(dirs, dotConfig)
+	}
+	return dirs
+}
+
 func GetShortCutsPath() string {
 	pcHome := getProcConfigDir()
 	if pcHome == "" {
@@ -8,12 +8,12 @@ import (
 
 type execChecker struct {
 	command    string
-	timeout    int
+	timeout    time.Duration
 	workingDir string
 }
 
 func (c *execChecker) Status() (interface{}, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
 	cmd := command.BuildCommandContext(ctx, c.command)
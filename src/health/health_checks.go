@@ -46,7 +46,14 @@ func New(name string, probe Probe, onCheckEnd func(bool, bool, string)) (*Prober
 		}
 		return p, err
 	}
-	return nil, fmt.Errorf("no probes [http_get, exec] configured for %s", name)
+	if probe.TcpSocket != nil {
+		err := p.addProber(p.getTcpChecker)
+		if err != nil {
+			return nil, err
+		}
+		return p, err
+	}
+	return nil, fmt.Errorf("no probes [http_get, exec, tcp_socket] configured for %s", name)
 }
 
 func (p *Prober) Start() {
@@ -102,13 +109,25 @@ func (p *Prober) addProber(factory func() (health.ICheckable, error)) error {
 }
 
 func (p *Prober) getHttpChecker() (health.ICheckable, error) {
-	url, err := p.probe.HttpGet.getUrl()
+	return newHttpChecker(p.probe.HttpGet, time.Duration(p.probe.TimeoutSeconds)*time.Second)
+}
+
+func (p *Prober) getExecChecker() (health.ICheckable, error) {
+	return newExecChecker(p.probe.Exec, time.Duration(p.probe.TimeoutSeconds)*time.Second), nil
+}
+
+func (p *Prober) getTcpChecker() (health.ICheckable, error) {
+	return newTcpChecker(p.probe.TcpSocket, time.Duration(p.probe.TimeoutSeconds)*time.Second)
+}
+
+func newHttpChecker(probe *HttpProbe, timeout time.Duration) (health.ICheckable, error) {
+	url, err := probe.getUrl()
 	if err != nil {
 		return nil, err
 	}
 	checker, err := checkers.NewHTTP(&checkers.HTTPConfig{
 		URL:     url,
-		Timeout: time.Duration(p.probe.TimeoutSeconds) * time.Second,
+		Timeout: timeout,
 	})
 	if err != nil {
 		return nil, err
@@ -116,10 +135,47 @@ func (p *Prober) getHttpChecker() (health.ICheckable, error) {
 	return checker, nil
 }
 
-func (p *Prober) getExecChecker() (health.ICheckable, error) {
+func newExecChecker(probe *ExecProbe, timeout time.Duration) *execChecker {
 	return &execChecker{
-		command:    p.probe.Exec.Command,
-		timeout:    p.probe.TimeoutSeconds,
-		workingDir: p.probe.Exec.WorkingDir,
+		command:    probe.Command,
+		timeout:    timeout,
+		workingDir: probe.WorkingDir,
+	}
+}
+
+func newTcpChecker(probe *TcpProbe, timeout time.Duration) (health.ICheckable, error) {
+	if probe.Port == "" {
+		return nil, fmt.Errorf("tcp_socket probe requires a port")
+	}
+	return &tcpChecker{
+		address:       probe.getAddress(),
+		timeout:       timeout,
+		expectTimeout: time.Duration(probe.ExpectTimeoutSeconds) * time.Second,
+		sendData:      probe.SendData,
+		expectData:    probe.ExpectData,
 	}, nil
 }
+
+// CheckOnce runs a single probe check, limited by timeout, and returns its error if it fails
+func CheckOnce(probe Probe, timeout time.Duration) error {
+	probe.ValidateAndSetDefaults()
+	var checker health.ICheckable
+	switch {
+	case probe.Exec != nil:
+		checker = newExecChecker(probe.Exec, timeout)
+	case probe.HttpGet != nil:
+		var err error
+		if checker, err = newHttpChecker(probe.HttpGet, timeout); err != nil {
+			return err
+		}
+	case probe.TcpSocket != nil:
+		var err error
+		if checker, err = newTcpChecker(probe.TcpSocket, timeout); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("no probes [http_get, exec, tcp_socket] configured")
+	}
+	_, err := checker.Status()
+	return err
+}
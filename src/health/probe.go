@@ -2,6 +2,7 @@ package health
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 type Probe struct {
 	Exec             *ExecProbe `yaml:"exec,omitempty"`
 	HttpGet          *HttpProbe `yaml:"http_get,omitempty"`
+	TcpSocket        *TcpProbe  `yaml:"tcp_socket,omitempty"`
 	InitialDelay     int        `yaml:"initial_delay_seconds,omitempty"`
 	PeriodSeconds    int        `yaml:"period_seconds,omitempty"`
 	TimeoutSeconds   int        `yaml:"timeout_seconds,omitempty"`
@@ -30,6 +32,18 @@ type HttpProbe struct {
 	NumPort int    `yaml:"num_port,omitempty"`
 }
 
+type TcpProbe struct {
+	Host                 string `yaml:"host,omitempty"`
+	Port                 string `yaml:"port,omitempty"`
+	SendData             string `yaml:"send_data,omitempty"`
+	ExpectData           string `yaml:"expect_data,omitempty"`
+	ExpectTimeoutSeconds int    `yaml:"expect_timeout_seconds,omitempty"`
+}
+
+func (t *TcpProbe) getAddress() string {
+	return net.JoinHostPort(t.Host, t.Port)
+}
+
 func (h *HttpProbe) getUrl() (*url.URL, error) {
 	urlStr := ""
 	if h.NumPort != 0 {
@@ -61,6 +75,9 @@ func (p *Probe) ValidateAndSetDefaults() {
 	if p.HttpGet != nil {
 		p.HttpGet.validateAndSetHttpDefaults()
 	}
+	if p.TcpSocket != nil {
+		p.TcpSocket.validateAndSetTcpDefaults(p.TimeoutSeconds)
+	}
 }
 
 func (p *HttpProbe) validateAndSetHttpDefaults() {
@@ -83,3 +100,12 @@ func (p *HttpProbe) validateAndSetHttpDefaults() {
 		p.NumPort = 0
 	}
 }
+
+func (t *TcpProbe) validateAndSetTcpDefaults(timeoutSeconds int) {
+	if len(strings.TrimSpace(t.Host)) == 0 {
+		t.Host = "127.0.0.1"
+	}
+	if t.ExpectTimeoutSeconds < 1 {
+		t.ExpectTimeoutSeconds = timeoutSeconds
+	}
+}
@@ -1,9 +1,13 @@
 package health
 
 import (
+	"bufio"
+	"net"
 	"net/url"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestProbe_validateAndSetDefaults(t *testing.T) {
@@ -156,3 +160,64 @@ func TestHttpProbe_getUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestTcpProbe_Check(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == "PING\r\n" {
+					_, _ = conn.Write([]byte("+PONG\r\n"))
+				}
+			}(conn)
+		}
+	}()
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+	tests := []struct {
+		name    string
+		probe   TcpProbe
+		wantErr bool
+	}{
+		{
+			name:  "Connect",
+			probe: TcpProbe{Port: port},
+		},
+		{
+			name:  "Send And Expect",
+			probe: TcpProbe{Port: port, SendData: "PING\r\n", ExpectData: "+PONG"},
+		},
+		{
+			name:    "Unexpected Response",
+			probe:   TcpProbe{Port: port, SendData: "QUIT\r\n", ExpectData: "+PONG"},
+			wantErr: true,
+		},
+		{
+			name:    "No Port",
+			probe:   TcpProbe{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := tt.probe
+			err := CheckOnce(Probe{TcpSocket: &probe}, time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckOnce() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+)
+
+// maxTcpResponseSize limits the response read while looking for the expected data
+const maxTcpResponseSize = 64 * 1024
+
+type tcpChecker struct {
+	address       string
+	timeout       time.Duration
+	expectTimeout time.Duration
+	sendData      string
+	expectData    string
+}
+
+func (c *tcpChecker) Status() (interface{}, error) {
+	conn, err := net.DialTimeout("tcp", c.address, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if c.sendData == "" && c.expectData == "" {
+		return map[string]string{"address": c.address}, nil
+	}
+	if err = conn.SetDeadline(time.Now().Add(c.expectTimeout)); err != nil {
+		return nil, err
+	}
+	if c.sendData != "" {
+		if _, err = conn.Write([]byte(c.sendData)); err != nil {
+			return nil, fmt.Errorf("failed to send data to %s: %w", c.address, err)
+		}
+	}
+	if c.expectData == "" {
+		return map[string]string{"address": c.address}, nil
+	}
+
+	var response []byte
+	buf := make([]byte, 4096)
+	for len(response) < maxTcpResponseSize {
+		n, readErr := conn.Read(buf)
+		response = append(response, buf[:n]...)
+		if bytes.Contains(response, []byte(c.expectData)) {
+			return map[string]string{"address": c.address}, nil
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("expected %q from %s, got %q: %w", c.expectData, c.address, response, readErr)
+		}
+	}
+	return nil, fmt.Errorf("expected %q from %s, got %q", c.expectData, c.address, response)
+}
@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProcessNameFromCommand derives a process name from the command executable basename (./bin/server -> server)
+func ProcessNameFromCommand(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	base := filepath.Base(fields[0])
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// AddProcess appends a process to the config file, creating the file if it doesn't exist.
+// The rest of the file, including its comments, is kept as is
+func AddProcess(fileName, name, command string) error {
+	mode := os.FileMode(0644)
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+	data, err := os.ReadFile(fileName)
+	switch {
+	case err == nil:
+		if info, statErr := os.Stat(fileName); statErr == nil {
+			mode = info.Mode().Perm()
+		}
+		if err = yaml.Unmarshal(data, doc); err != nil {
+			return parseError(fileName, err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse %s: not a YAML mapping", fileName)
+	}
+
+	processes := mappingValue(root, "processes")
+	if processes == nil {
+		processes = &yaml.Node{Kind: yaml.MappingNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "processes"}, processes)
+	} else if processes.Kind == yaml.ScalarNode && processes.Tag == "!!null" {
+		// an empty 'processes:' key
+		*processes = yaml.Node{Kind: yaml.MappingNode}
+	} else if processes.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse %s: 'processes' is not a mapping", fileName)
+	}
+	if mappingValue(processes, name) != nil {
+		return fmt.Errorf("process %s is already defined in %s", name, fileName)
+	}
+	processes.Content = append(processes.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+		&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "command"},
+			{Kind: yaml.ScalarNode, Value: command, Style: yaml.DoubleQuotedStyle},
+		}},
+	)
+
+	data, err = encodeNode(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", fileName, err)
+	}
+	return os.WriteFile(fileName, data, mode)
+}
+
+// encodeNode encodes a YAML document with 2 spaces indentation
+func encodeNode(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
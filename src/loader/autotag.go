@@ -0,0 +1,65 @@
+package loader
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	envGitCommit = "GIT_COMMIT"
+	envGitBranch = "GIT_BRANCH"
+	envGitDirty  = "GIT_DIRTY"
+)
+
+// applyAutoTag adds the git commit, branch and dirty state of the config file directory to the project
+// environment. The variables already defined in the project environment are kept
+func applyAutoTag(p *types.Project) {
+	if !p.AutoTag {
+		return
+	}
+	dir := "."
+	if len(p.FileNames) > 0 && p.FileNames[0] != "-" {
+		dir = filepath.Dir(p.FileNames[0])
+	}
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		log.Warn().Err(err).Msgf("auto_tag: failed to get the git commit of %s", dir)
+		return
+	}
+	// empty on a detached HEAD
+	branch, err := runGit(dir, "branch", "--show-current")
+	if err != nil {
+		log.Warn().Err(err).Msgf("auto_tag: failed to get the git branch of %s", dir)
+	}
+	status, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		log.Warn().Err(err).Msgf("auto_tag: failed to get the git status of %s", dir)
+	}
+	tags := map[string]string{
+		envGitCommit: commit,
+		envGitBranch: branch,
+		envGitDirty:  strconv.FormatBool(status != ""),
+	}
+	for _, kv := range p.Environment {
+		key, _, _ := strings.Cut(kv, "=")
+		delete(tags, key)
+	}
+	for _, name := range []string{envGitCommit, envGitBranch, envGitDirty} {
+		if val, ok := tags[name]; ok {
+			p.Environment = append(p.Environment, name+"="+val)
+		}
+	}
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,86 @@
+package loader
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func Test_applyAutoTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "process-compose.yaml")
+	if err := os.WriteFile(configFile, []byte("processes: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "process-compose.yaml"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		project types.Project
+		dirty   bool
+		want    types.Environment
+	}{
+		{
+			name:    "Disabled",
+			project: types.Project{FileNames: []string{configFile}},
+		},
+		{
+			name:    "Clean",
+			project: types.Project{AutoTag: true, FileNames: []string{configFile}},
+			want:    types.Environment{"GIT_COMMIT=" + commit, "GIT_BRANCH=main", "GIT_DIRTY=false"},
+		},
+		{
+			name:    "Dirty",
+			project: types.Project{AutoTag: true, FileNames: []string{configFile}},
+			dirty:   true,
+			want:    types.Environment{"GIT_COMMIT=" + commit, "GIT_BRANCH=main", "GIT_DIRTY=true"},
+		},
+		{
+			name: "Defined",
+			project: types.Project{
+				AutoTag:     true,
+				FileNames:   []string{configFile},
+				Environment: types.Environment{"GIT_BRANCH=release"},
+			},
+			want: types.Environment{"GIT_BRANCH=release", "GIT_COMMIT=" + commit, "GIT_DIRTY=false"},
+		},
+		{
+			name:    "Not a repository",
+			project: types.Project{AutoTag: true, FileNames: []string{filepath.Join(t.TempDir(), "process-compose.yaml")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			untracked := filepath.Join(dir, "untracked.txt")
+			if tt.dirty {
+				if err := os.WriteFile(untracked, []byte("dirty\n"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Remove(untracked)
+			}
+			applyAutoTag(&tt.project)
+			if !reflect.DeepEqual(tt.project.Environment, tt.want) {
+				t.Errorf("applyAutoTag() environment = %v, want %v", tt.project.Environment, tt.want)
+			}
+		})
+	}
+}
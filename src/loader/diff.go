@@ -0,0 +1,165 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"gopkg.in/yaml.v3"
+)
+
+// FieldChange is a changed process field, by its config path (e.g. depends_on.db.condition)
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+}
+
+// ProcessDiff is the changed fields of a process
+type ProcessDiff struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// ProjectDiff is the processes added, removed and modified between two projects
+type ProjectDiff struct {
+	Added    []string      `json:"added"`
+	Removed  []string      `json:"removed"`
+	Modified []ProcessDiff `json:"modified"`
+}
+
+// IsEmpty returns true if the projects have the same processes
+func (d *ProjectDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// String renders the diff as text, the added processes prefixed with +, the removed ones with - and the
+// modified ones with ~
+func (d *ProjectDiff) String() string {
+	var sb strings.Builder
+	for _, name := range d.Added {
+		fmt.Fprintf(&sb, "+ %s\n", name)
+	}
+	for _, name := range d.Removed {
+		fmt.Fprintf(&sb, "- %s\n", name)
+	}
+	for _, proc := range d.Modified {
+		fmt.Fprintf(&sb, "~ %s\n", proc.Name)
+		for _, change := range proc.Changes {
+			fmt.Fprintf(&sb, "    %s: %s -> %s\n", change.Field, formatDiffValue(change.Old), formatDiffValue(change.New))
+		}
+	}
+	return sb.String()
+}
+
+func formatDiffValue(val any) string {
+	if val == nil {
+		return "(none)"
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(b)
+}
+
+// derivedProcessKeys are the process fields that aren't set in the config, e.g. the replica name or the
+// executable and arguments derived from the command
+var derivedProcessKeys = func() map[string]bool {
+	keys := map[string]bool{}
+	procType := reflect.TypeOf(types.ProcessConfig{})
+	for i := 0; i < procType.NumField(); i++ {
+		if field := procType.Field(i); field.Tag.Get("yaml") == "" {
+			keys[strings.ToLower(field.Name)] = true
+		}
+	}
+	return keys
+}()
+
+// DiffProjects returns the processes added, removed and modified from one project to the other
+func DiffProjects(from, to *types.Project) (*ProjectDiff, error) {
+	diff := &ProjectDiff{
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []ProcessDiff{},
+	}
+	for name := range to.Processes {
+		if _, ok := from.Processes[name]; !ok {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+	for name, fromProc := range from.Processes {
+		toProc, ok := to.Processes[name]
+		if !ok {
+			diff.Removed = append(diff.Removed, name)
+			continue
+		}
+		fromFields, err := processFields(&fromProc)
+		if err != nil {
+			return nil, err
+		}
+		toFields, err := processFields(&toProc)
+		if err != nil {
+			return nil, err
+		}
+		var changes []FieldChange
+		diffFields("", fromFields, toFields, &changes)
+		if len(changes) > 0 {
+			diff.Modified = append(diff.Modified, ProcessDiff{Name: name, Changes: changes})
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Modified, func(i, j int) bool {
+		return diff.Modified[i].Name < diff.Modified[j].Name
+	})
+	return diff, nil
+}
+
+// processFields returns the process config fields by their config names
+func processFields(proc *types.ProcessConfig) (map[string]any, error) {
+	data, err := yaml.Marshal(proc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode process %s: %w", proc.ReplicaName, err)
+	}
+	fields := map[string]any{}
+	if err = yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode process %s: %w", proc.ReplicaName, err)
+	}
+	for key := range derivedProcessKeys {
+		delete(fields, key)
+	}
+	return fields, nil
+}
+
+// diffFields appends the changes between the fields, recursing into the nested mappings
+func diffFields(prefix string, from, to map[string]any, changes *[]FieldChange) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		fromVal, toVal := from[key], to[key]
+		fromMap, fromIsMap := fromVal.(map[string]any)
+		toMap, toIsMap := toVal.(map[string]any)
+		switch {
+		case fromIsMap && toIsMap:
+			diffFields(path, fromMap, toMap, changes)
+		case !reflect.DeepEqual(fromVal, toVal):
+			*changes = append(*changes, FieldChange{Field: path, Old: fromVal, New: toVal})
+		}
+	}
+}
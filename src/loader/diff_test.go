@@ -0,0 +1,89 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiffProjects(t *testing.T) {
+	dir := t.TempDir()
+	load := func(name, config string) *LoaderOptions {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return &LoaderOptions{FileNames: []string{file}, disableDotenv: true}
+	}
+	from, err := Load(load("from.yaml", `
+processes:
+  api:
+    command: "./api --port 80"
+    depends_on:
+      db:
+        condition: process_started
+  db:
+    command: "./db"
+  worker:
+    command: "./worker"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := Load(load("to.yaml", `
+processes:
+  api:
+    command: "./api --port 8080"
+    environment:
+      - "A=1"
+    depends_on:
+      db:
+        condition: process_healthy
+  db:
+    command: "./db"
+  worker-v2:
+    command: "./worker --v2"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffProjects(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ProjectDiff{
+		Added:   []string{"worker-v2"},
+		Removed: []string{"worker"},
+		Modified: []ProcessDiff{{
+			Name: "api",
+			Changes: []FieldChange{
+				{Field: "command", Old: "./api --port 80", New: "./api --port 8080"},
+				{Field: "depends_on.db.condition", Old: "process_started", New: "process_healthy"},
+				{Field: "environment", New: []any{"A=1"}},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffProjects() = %+v, want %+v", diff, want)
+	}
+	wantText := `+ worker-v2
+- worker
+~ api
+    command: "./api --port 80" -> "./api --port 8080"
+    depends_on.db.condition: "process_started" -> "process_healthy"
+    environment: (none) -> ["A=1"]
+`
+	if diff.String() != wantText {
+		t.Errorf("got:\n%s\nwant:\n%s", diff, wantText)
+	}
+
+	same, err := DiffProjects(from, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !same.IsEmpty() {
+		t.Errorf("expected no changes, got %+v", same)
+	}
+}
@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+var envVarPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// validateEnvVars checks the command line variables are KEY=VALUE pairs
+func validateEnvVars(vars []string) error {
+	for _, kv := range vars {
+		if !envVarPattern.MatchString(kv) {
+			return fmt.Errorf("invalid variable %q, expected KEY=VALUE", kv)
+		}
+	}
+	return nil
+}
+
+// applyEnvVars sets the command line variables in the project environment, overriding their values in the project
+// and processes environment. They are kept in the project to override the bootstrap_command output as well
+func applyEnvVars(opts *LoaderOptions) mutatorFunc {
+	return func(p *types.Project) {
+		if len(opts.EnvVars) == 0 {
+			return
+		}
+		p.CliEnvVars = opts.EnvVars
+		// the last value of a variable set more than once wins
+		vars := map[string]string{}
+		keys := []string{}
+		for _, kv := range opts.EnvVars {
+			key, _, _ := strings.Cut(kv, "=")
+			if _, ok := vars[key]; !ok {
+				keys = append(keys, key)
+			}
+			vars[key] = kv
+		}
+		defined := map[string]bool{}
+		for _, kv := range p.Environment {
+			key, _, _ := strings.Cut(kv, "=")
+			defined[key] = true
+		}
+		p.Environment = overrideEnv(p.Environment, vars)
+		for _, key := range keys {
+			if !defined[key] {
+				p.Environment = append(p.Environment, vars[key])
+			}
+		}
+		for name, proc := range p.Processes {
+			proc.Environment = overrideEnv(proc.Environment, vars)
+			p.Processes[name] = proc
+		}
+	}
+}
+
+func overrideEnv(env types.Environment, vars map[string]string) types.Environment {
+	if len(env) == 0 {
+		return env
+	}
+	overridden := make(types.Environment, 0, len(env))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if override, ok := vars[key]; ok {
+			kv = override
+		}
+		overridden = append(overridden, kv)
+	}
+	return overridden
+}
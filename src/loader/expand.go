@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// expandParameter expands a ${...} env var expression with the POSIX shell parameter expansion forms:
+//
+//	${VAR:-default} ${VAR-default} default if VAR is unset or empty (unset only)
+//	${VAR:+value} ${VAR+value}     value if VAR is set and not empty (set only)
+//	${VAR#pattern} ${VAR##pattern} VAR without its shortest (longest) matching prefix
+//	${VAR%pattern} ${VAR%%pattern} VAR without its shortest (longest) matching suffix
+//	${#VAR}                        the length of VAR
+//
+// The default and alternate values are expanded as well. The patterns support the * and ? wildcards
+func expandParameter(expr string) string {
+	if name, ok := strings.CutPrefix(expr, "#"); ok && name != "" && isEnvName(name) {
+		return strconv.Itoa(len(os.Getenv(name)))
+	}
+	i := 0
+	for i < len(expr) && isEnvNameChar(expr[i]) {
+		i++
+	}
+	name, op := expr[:i], expr[i:]
+	if name == "" || op == "" {
+		return os.Getenv(expr)
+	}
+	value, set := os.LookupEnv(name)
+	switch {
+	case strings.HasPrefix(op, ":-"):
+		if value == "" {
+			return os.Expand(op[2:], expandParameter)
+		}
+		return value
+	case strings.HasPrefix(op, "-"):
+		if !set {
+			return os.Expand(op[1:], expandParameter)
+		}
+		return value
+	case strings.HasPrefix(op, ":+"):
+		if value != "" {
+			return os.Expand(op[2:], expandParameter)
+		}
+		return ""
+	case strings.HasPrefix(op, "+"):
+		if set {
+			return os.Expand(op[1:], expandParameter)
+		}
+		return ""
+	case strings.HasPrefix(op, "##"):
+		return trimPattern(value, op[2:], true, true)
+	case strings.HasPrefix(op, "#"):
+		return trimPattern(value, op[1:], true, false)
+	case strings.HasPrefix(op, "%%"):
+		return trimPattern(value, op[2:], false, true)
+	case strings.HasPrefix(op, "%"):
+		return trimPattern(value, op[1:], false, false)
+	}
+	return os.Getenv(expr)
+}
+
+// trimPattern removes the shortest or longest prefix or suffix of value matching pattern
+func trimPattern(value, pattern string, prefix, longest bool) string {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return value
+	}
+	for n := 0; n <= len(value); n++ {
+		length := n
+		if longest {
+			length = len(value) - n
+		}
+		if prefix && re.MatchString(value[:length]) {
+			return value[length:]
+		}
+		if !prefix && re.MatchString(value[len(value)-length:]) {
+			return value[:len(value)-length]
+		}
+	}
+	return value
+}
+
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+func isEnvName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isEnvNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEnvNameChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,41 @@
+package loader
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_expandParameter(t *testing.T) {
+	t.Setenv("PC_TEST_PATH", "/usr/local/bin/app.tar.gz")
+	t.Setenv("PC_TEST_EMPTY", "")
+	t.Setenv("PC_TEST_PORT", "8080")
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"${PC_TEST_PORT}", "8080"},
+		{"$PC_TEST_PORT", "8080"},
+		{"${PC_TEST_UNSET:-80}", "80"},
+		{"${PC_TEST_EMPTY:-80}", "80"},
+		{"${PC_TEST_EMPTY-80}", ""},
+		{"${PC_TEST_UNSET-80}", "80"},
+		{"${PC_TEST_UNSET:-$PC_TEST_PORT}", "8080"},
+		{"${PC_TEST_PORT:+--port=$PC_TEST_PORT}", "--port=8080"},
+		{"${PC_TEST_EMPTY:+set}", ""},
+		{"${PC_TEST_EMPTY+set}", "set"},
+		{"${PC_TEST_UNSET+set}", ""},
+		{"${PC_TEST_PATH#*/}", "usr/local/bin/app.tar.gz"},
+		{"${PC_TEST_PATH##*/}", "app.tar.gz"},
+		{"${PC_TEST_PATH%.*}", "/usr/local/bin/app.tar"},
+		{"${PC_TEST_PATH%%.*}", "/usr/local/bin/app"},
+		{"${PC_TEST_PATH##/usr/local/}", "bin/app.tar.gz"},
+		{"${PC_TEST_PATH%%/nomatch}", "/usr/local/bin/app.tar.gz"},
+		{"${#PC_TEST_PORT}", "4"},
+		{"${#PC_TEST_UNSET}", "0"},
+	}
+	for _, tt := range tests {
+		if got := os.Expand(tt.value, expandParameter); got != tt.want {
+			t.Errorf("expand(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
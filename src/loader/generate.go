@@ -0,0 +1,137 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	puproc "github.com/shirou/gopsutil/v4/process"
+	"gopkg.in/yaml.v3"
+)
+
+// runningProcess is a running process to generate a config entry from
+type runningProcess struct {
+	pid         int32
+	args        []string
+	workingDir  string
+	environment []string
+}
+
+// GenerateConfig generates a config with a process per running process whose command line matches the filter
+// (all the processes if nil). The processes environment is included only where it differs from the current one,
+// the secret variables reference the variable of the same name instead of holding its value
+func GenerateConfig(filter *regexp.Regexp) ([]byte, error) {
+	procs, err := listRunningProcesses(filter)
+	if err != nil {
+		return nil, err
+	}
+	return generateConfig(procs, os.Environ(), (&types.Project{}).IsSecretEnvVar)
+}
+
+func listRunningProcesses(filter *regexp.Regexp) ([]runningProcess, error) {
+	all, err := puproc.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the running processes: %w", err)
+	}
+	self := int32(os.Getpid())
+	procs := []runningProcess{}
+	for _, proc := range all {
+		if proc.Pid == self {
+			continue
+		}
+		// kernel threads and the processes of other users can't be read
+		args, err := proc.CmdlineSlice()
+		if err != nil || len(args) == 0 {
+			continue
+		}
+		if filter != nil && !filter.MatchString(strings.Join(args, " ")) {
+			continue
+		}
+		running := runningProcess{pid: proc.Pid, args: args}
+		running.workingDir, _ = proc.Cwd()
+		running.environment, _ = proc.Environ()
+		procs = append(procs, running)
+	}
+	slices.SortFunc(procs, func(a, b runningProcess) int {
+		return int(a.pid - b.pid)
+	})
+	return procs, nil
+}
+
+func generateConfig(procs []runningProcess, currentEnv []string, isSecret func(string) bool) ([]byte, error) {
+	processes := &yaml.Node{Kind: yaml.MappingNode}
+	names := map[string]bool{}
+	for _, proc := range procs {
+		name := ProcessNameFromCommand(proc.args[0])
+		if names[name] {
+			name += "-" + strconv.Itoa(int(proc.pid))
+		}
+		names[name] = true
+		fields := []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "command"},
+			{Kind: yaml.ScalarNode, Value: escapeDollars(quoteCommand(proc.args)), Style: yaml.DoubleQuotedStyle},
+		}
+		if proc.workingDir != "" {
+			fields = append(fields,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "working_dir"},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: proc.workingDir, Style: yaml.DoubleQuotedStyle},
+			)
+		}
+		env := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, kv := range proc.environment {
+			if kv == "" || isShellEnvVar(kv) || slices.Contains(currentEnv, kv) {
+				continue
+			}
+			value := escapeDollars(kv)
+			if key, _, _ := strings.Cut(kv, "="); isSecret(key) {
+				// expanded from the environment on load, so the secret isn't written to the config
+				value = fmt.Sprintf("%s=${%s}", key, key)
+			}
+			env.Content = append(env.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: value, Style: yaml.DoubleQuotedStyle})
+		}
+		if len(env.Content) > 0 {
+			fields = append(fields, &yaml.Node{Kind: yaml.ScalarNode, Value: "environment"}, env)
+		}
+		processes.Content = append(processes.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+			&yaml.Node{Kind: yaml.MappingNode, Content: fields},
+		)
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "processes"},
+			processes,
+		},
+	}}}
+	return encodeNode(doc)
+}
+
+// isShellEnvVar reports whether the variable is set by the shell that started the process
+func isShellEnvVar(kv string) bool {
+	return strings.HasPrefix(kv, "_=") || strings.HasPrefix(kv, "PWD=") || strings.HasPrefix(kv, "OLDPWD=")
+}
+
+// quoteCommand joins the command line arguments, single quoting the arguments that the shell would split
+// or expand
+func quoteCommand(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`|&;<>()*?[]{}~#!") {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// escapeDollars keeps the $ signs from being expanded as environment variables when the config is loaded
+func escapeDollars(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
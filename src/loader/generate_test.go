@@ -0,0 +1,68 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	procs := []runningProcess{
+		{
+			pid:         10,
+			args:        []string{"/usr/bin/redis-server", "--port", "6379"},
+			workingDir:  "/var/lib/redis",
+			environment: []string{"HOME=/root", "REDIS_PASSWORD=pa$s", "REDIS_PORT=63$79", "_=/usr/bin/redis-server"},
+		},
+		{
+			pid:  20,
+			args: []string{"/usr/bin/redis-server", "--save", "", "--loglevel", "it's verbose"},
+		},
+	}
+	want := `processes:
+  redis-server:
+    command: "/usr/bin/redis-server --port 6379"
+    working_dir: "/var/lib/redis"
+    environment:
+      - "REDIS_PASSWORD=${REDIS_PASSWORD}"
+      - "REDIS_PORT=63$$79"
+  redis-server-20:
+    command: "/usr/bin/redis-server --save '' --loglevel 'it'\\''s verbose'"
+`
+	data, err := generateConfig(procs, []string{"HOME=/root"}, (&types.Project{}).IsSecretEnvVar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	// the generated config is loadable
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err = os.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("REDIS_PASSWORD", "secret")
+	project, err := Load(&LoaderOptions{FileNames: []string{file}, disableDotenv: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEnv := []string{"REDIS_PASSWORD=secret", "REDIS_PORT=63$79"}
+	if env := project.Processes["redis-server"].Environment; !slices.Equal(env, wantEnv) {
+		t.Errorf("expected the environment %q, got %q", wantEnv, env)
+	}
+}
+
+func TestListRunningProcesses(t *testing.T) {
+	procs, err := listRunningProcesses(regexp.MustCompile(`a^`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 0 {
+		t.Errorf("expected no process to match, got %d", len(procs))
+	}
+}
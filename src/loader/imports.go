@@ -0,0 +1,117 @@
+package loader
+
+import (
+	"fmt"
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+)
+
+// resolveImportPaths makes the import paths relative to dir, the directory of the file they are defined in
+func resolveImportPaths(p *types.Project, dir string) {
+	if len(p.Imports) == 0 {
+		return
+	}
+	for i, imp := range p.Imports {
+		if !filepath.IsAbs(imp.Path) {
+			p.Imports[i].Path = filepath.Join(dir, imp.Path)
+		}
+	}
+}
+
+// findImportFile returns the config file of an import. The path can point to a config file or
+// to a directory containing one of the DefaultFileNames
+func findImportFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to import %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	candidates := findFiles(DefaultFileNames, path)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("failed to import %s: no config files found", path)
+	}
+	return candidates[0], nil
+}
+
+// importProjects loads the project imports (and their own imports) and adds their processes
+// to the project, prefixed with the import namespace
+func importProjects(opts *LoaderOptions) mutatorFuncE {
+	return func(p *types.Project) error {
+		return importProjectsFrom(p, opts, map[string]bool{})
+	}
+}
+
+func importProjectsFrom(p *types.Project, opts *LoaderOptions, visited map[string]bool) error {
+	for _, imp := range p.Imports {
+		file, err := findImportFile(imp.Path)
+		if err != nil {
+			return err
+		}
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		if visited[absFile] {
+			return fmt.Errorf("circular import of %s", file)
+		}
+		sub, err := loadProjectFromFile(file, opts.disableDotenv, opts.EnvFileNames)
+		if err != nil {
+			return err
+		}
+		resolveImportPaths(sub, configDir(file))
+		if imp.Namespace != "" {
+			sub.Namespace = imp.Namespace
+		}
+		if sub.Namespace == "" {
+			return fmt.Errorf("import of %s requires a namespace", file)
+		}
+		visited[absFile] = true
+		if err = importProjectsFrom(sub, opts, visited); err != nil {
+			return err
+		}
+		delete(visited, absFile)
+		// the base processes are resolved by their names in the imported project, before the namespace is applied
+		if err = applyBaseProcesses(sub); err != nil {
+			return err
+		}
+		for name, proc := range sub.Processes {
+			proc.BaseProcess = ""
+			sub.Processes[name] = proc
+		}
+		applyProjectNamespace(sub)
+
+		importDir := filepath.Dir(absFile)
+		if p.Processes == nil {
+			p.Processes = types.Processes{}
+		}
+		for name, proc := range sub.Processes {
+			if _, ok := p.Processes[name]; ok {
+				return fmt.Errorf("imported process %s from %s already exists", name, file)
+			}
+			// the sub-project globals apply only to its own processes
+			proc.Environment = append(append(types.Environment{}, sub.Environment...), proc.Environment...)
+			if len(sub.Vars) > 0 {
+				vars := types.Vars{}
+				for k, v := range sub.Vars {
+					vars[k] = v
+				}
+				for k, v := range proc.Vars {
+					vars[k] = v
+				}
+				proc.Vars = vars
+			}
+			if proc.WorkingDir == "" {
+				proc.WorkingDir = importDir
+			} else if !filepath.IsAbs(proc.WorkingDir) {
+				proc.WorkingDir = filepath.Join(importDir, proc.WorkingDir)
+			}
+			p.Processes[name] = proc
+		}
+		log.Info().Msgf("Imported %d processes from %s as %s", len(sub.Processes), file, sub.Namespace)
+	}
+	return nil
+}
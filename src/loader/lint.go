@@ -0,0 +1,198 @@
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityInfo    = "info"
+)
+
+// LintIssue is a configuration issue found by Lint, with a suggested fix
+type LintIssue struct {
+	Severity string `json:"severity"`
+	Process  string `json:"process,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+func (i LintIssue) String() string {
+	location := types.Location{File: i.File, Line: i.Line}.String()
+	if i.File == "" {
+		location = "process-compose"
+	}
+	str := fmt.Sprintf("%s: %s: %s", location, i.Severity, i.Message)
+	if i.Fix != "" {
+		str += " (fix: " + i.Fix + ")"
+	}
+	return str
+}
+
+type lintFunc func(p *types.Project) []LintIssue
+
+// Lint reports the issues of a loaded project that don't prevent it from running, sorted by location
+func Lint(p *types.Project) []LintIssue {
+	issues := []LintIssue{}
+	for _, check := range []lintFunc{
+		lintRedundantDependencies,
+		lintUnlimitedRestarts,
+		lintLogFileConflicts,
+		lintDisabledDependencies,
+		lintUnreachableProcesses,
+		lintUnrenderedTemplates,
+	} {
+		issues = append(issues, check(p)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Message < issues[j].Message
+	})
+	return issues
+}
+
+func newLintIssue(severity string, proc *types.ProcessConfig, message, fix string) LintIssue {
+	return LintIssue{
+		Severity: severity,
+		Process:  proc.ReplicaName,
+		File:     proc.Location.File,
+		Line:     proc.Location.Line,
+		Message:  message,
+		Fix:      fix,
+	}
+}
+
+// lintRedundantDependencies finds the dependencies already implied by another dependency:
+// if api depends on web, and web depends on db with the same condition, api doesn't need to depend on db
+func lintRedundantDependencies(p *types.Project) []LintIssue {
+	var issues []LintIssue
+	for _, proc := range p.Processes {
+		for depName, dep := range proc.DependsOn {
+			for viaName, via := range proc.DependsOn {
+				if viaName == depName || via.Condition == types.ProcessConditionStartedNonBlocking {
+					continue
+				}
+				viaProc, ok := lookupDependency(p, viaName)
+				if !ok {
+					continue
+				}
+				if implied, ok := viaProc.DependsOn[depName]; ok && implied.Condition == dep.Condition {
+					issues = append(issues, newLintIssue(LintSeverityInfo, &proc,
+						fmt.Sprintf("dependency '%s' of process '%s' is implied by its dependency '%s'", depName, proc.ReplicaName, viaName),
+						fmt.Sprintf("remove '%s' from the process depends_on", depName)))
+					break
+				}
+			}
+		}
+	}
+	return issues
+}
+
+func lintUnlimitedRestarts(p *types.Project) []LintIssue {
+	var issues []LintIssue
+	for _, proc := range p.Processes {
+		if proc.RestartPolicy.Restart == types.RestartPolicyAlways && proc.RestartPolicy.MaxRestarts == 0 {
+			issues = append(issues, newLintIssue(LintSeverityWarning, &proc,
+				fmt.Sprintf("process '%s' is restarted always without a restarts limit", proc.ReplicaName),
+				"set availability.max_restarts"))
+		}
+	}
+	return issues
+}
+
+// lintLogFileConflicts finds the log files written by more than one process
+func lintLogFileConflicts(p *types.Project) []LintIssue {
+	writers := map[string][]string{}
+	for name, proc := range p.Processes {
+		if proc.Replicas > 1 && proc.ReplicaNum > 0 {
+			// the replicas log files are suffixed with the replica number
+			continue
+		}
+		for _, file := range []string{proc.LogLocation, proc.StdoutLogLocation, proc.StderrLogLocation} {
+			if file == "" {
+				continue
+			}
+			path := file
+			if !filepath.IsAbs(path) && proc.WorkingDir != "" {
+				path = filepath.Join(proc.WorkingDir, path)
+			}
+			path = filepath.Clean(path)
+			if !slices.Contains(writers[path], name) {
+				writers[path] = append(writers[path], name)
+			}
+		}
+	}
+	var issues []LintIssue
+	for path, names := range writers {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			proc := p.Processes[name]
+			others := make([]string, 0, len(names)-1)
+			for _, other := range names {
+				if other != name {
+					others = append(others, other)
+				}
+			}
+			issues = append(issues, newLintIssue(LintSeverityError, &proc,
+				fmt.Sprintf("log file %s of process '%s' is also written by %s", path, name, strings.Join(others, ", ")),
+				"use a separate log file for each process, or the project log_location for a unified log"))
+		}
+	}
+	return issues
+}
+
+func lintDisabledDependencies(p *types.Project) []LintIssue {
+	var issues []LintIssue
+	for _, proc := range p.Processes {
+		if proc.Disabled {
+			continue
+		}
+		for depName := range proc.DependsOn {
+			if dep, ok := lookupDependency(p, depName); ok && dep.Disabled {
+				issues = append(issues, newLintIssue(LintSeverityWarning, &proc,
+					fmt.Sprintf("process '%s' depends on the disabled process '%s'", proc.ReplicaName, depName),
+					fmt.Sprintf("enable '%s' or remove it from the process depends_on", depName)))
+			}
+		}
+	}
+	return issues
+}
+
+func lintUnreachableProcesses(p *types.Project) []LintIssue {
+	var issues []LintIssue
+	for _, name := range findUnreachableProcesses(p) {
+		proc := p.Processes[name]
+		issues = append(issues, newLintIssue(LintSeverityWarning, &proc,
+			fmt.Sprintf("process '%s' is unreachable, all the processes that depend on it are disabled", name),
+			fmt.Sprintf("disable or remove '%s'", name)))
+	}
+	return issues
+}
+
+func lintUnrenderedTemplates(p *types.Project) []LintIssue {
+	var issues []LintIssue
+	for _, name := range findUnrenderedTemplates(p) {
+		proc := p.Processes[name]
+		issues = append(issues, newLintIssue(LintSeverityWarning, &proc,
+			fmt.Sprintf("process '%s' uses the process metadata template variables, but its templates aren't rendered without vars", proc.Name),
+			"define the process vars"))
+	}
+	return issues
+}
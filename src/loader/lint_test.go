@@ -0,0 +1,76 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func TestLint(t *testing.T) {
+	started := types.ProcessDependency{Condition: types.ProcessConditionStarted}
+	p := &types.Project{
+		Processes: types.Processes{
+			"db": {
+				ReplicaName: "db",
+				LogLocation: "/tmp/shared.log",
+				Location:    types.Location{File: "pc.yaml", Line: 2},
+			},
+			"web": {
+				ReplicaName:   "web",
+				LogLocation:   "/tmp/shared.log",
+				RestartPolicy: types.RestartPolicyConfig{Restart: types.RestartPolicyAlways},
+				DependsOn:     types.DependsOnConfig{"db": started},
+				Location:      types.Location{File: "pc.yaml", Line: 5},
+			},
+			"api": {
+				ReplicaName: "api",
+				DependsOn:   types.DependsOnConfig{"web": started, "db": started, "off": started},
+				Location:    types.Location{File: "pc.yaml", Line: 9},
+			},
+			"off": {
+				ReplicaName: "off",
+				Disabled:    true,
+				Location:    types.Location{File: "pc.yaml", Line: 14},
+			},
+			"server-0": {
+				Name:        "server",
+				ReplicaName: "server-0",
+				Command:     "./server --port {{add 8080 .ReplicaIndex}}",
+				Replicas:    2,
+				Location:    types.Location{File: "pc.yaml", Line: 17},
+			},
+			"server-1": {
+				Name:        "server",
+				ReplicaName: "server-1",
+				Command:     "./server --port {{add 8080 .ReplicaIndex}}",
+				Replicas:    2,
+				ReplicaNum:  1,
+				Location:    types.Location{File: "pc.yaml", Line: 17},
+			},
+		},
+	}
+	got := map[string]int{}
+	for _, issue := range Lint(p) {
+		got[issue.Process+":"+issue.Severity]++
+		if issue.Fix == "" {
+			t.Errorf("expected a fix for %s", issue)
+		}
+	}
+	want := map[string]int{
+		"db:error":    1,
+		"web:error":   1,
+		"web:warning": 1,
+		"api:info":    1,
+		"api:warning": 1,
+
+		"server-0:warning": 1,
+	}
+	for key, count := range want {
+		if got[key] != count {
+			t.Errorf("expected %d %s issues, got %d", count, key, got[key])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected issues %v, got %v", want, got)
+	}
+}
@@ -1,14 +1,19 @@
 package loader
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/f1bonacc1/process-compose/src/config"
+	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -22,8 +27,32 @@ func Load(opts *LoaderOptions) (*types.Project, error) {
 		return nil, err
 	}
 
+	if err = validateEnvVars(opts.EnvVars); err != nil {
+		return nil, err
+	}
+	for _, kv := range opts.EnvVars {
+		key, value, _ := strings.Cut(kv, "=")
+		if err = os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	envFileNames := make([]string, 0, len(opts.EnvFileNames))
+	for _, file := range append(slices.Clip(opts.EnvFileNames), opts.ExtraEnvFileNames...) {
+		envFileNames = append(envFileNames, expandPath(file))
+	}
+	opts.EnvFileNames = envFileNames
+	if opts.inlineConfig != nil {
+		if err = loadInlineProject(opts); err != nil {
+			return nil, err
+		}
+	}
 	for _, file := range opts.FileNames {
-		p := loadProjectFromFile(file, opts.disableDotenv, opts.EnvFileNames)
+		p, err := loadProjectFromFile(file, opts.disableDotenv, opts.EnvFileNames)
+		if err != nil {
+			return nil, err
+		}
+		resolveImportPaths(p, configDir(file))
+		resolveWorkingDirs(p, configDir(file))
 		opts.projects = append(opts.projects, p)
 	}
 	mergedProject, err := merge(opts)
@@ -31,11 +60,28 @@ func Load(opts *LoaderOptions) (*types.Project, error) {
 		return nil, err
 	}
 	mergedProject.FileNames = opts.FileNames
+	mergedProject.RunID = pclog.GenerateUUID()
 	mergedProject.IsTuiDisabled = opts.isTuiDisabled || mergedProject.IsTuiDisabled
 
+	err = applyWithErr(mergedProject,
+		importProjects(opts),
+		applyBaseProcesses,
+		applyDisabledIf,
+	)
+	if err != nil {
+		return nil, err
+	}
 	apply(mergedProject,
+		applyEnvVars(opts),
+		applyStateDir(opts),
+		applyAutoTag,
 		setDefaultShell,
+		applyProjectNamespace,
 		assignDefaultProcessValues,
+		applyInputFrom,
+		applyStartupBarrier,
+		applyDefaultUlimits,
+		applyCoreDump,
 		cloneReplicas,
 		copyWorkingDirToProbes,
 	)
@@ -51,13 +97,27 @@ func Load(opts *LoaderOptions) (*types.Project, error) {
 
 	err = validate(mergedProject,
 		validateLogLevel,
+		validateLogFormat,
 		validateProcessConfig,
+		validateOutputBuffering,
+		validateKillSignal,
+		validateReloadSignal,
+		validateColor,
+		validateForwardSignals,
 		validateNoCircularDependencies,
 		validateShellConfig,
 		validatePlatformCompatibility,
 		validateHealthDependencyHasHealthCheck,
 		validateDependencyIsEnabled,
+		validateUnreachableProcesses,
+		validateUnrenderedTemplates,
 		validateNoIncompatibleHealthChecks,
+		validateEnvFromProcess,
+		validateStartupBarrier,
+		validateEnvSchema,
+		validateInputFrom,
+		validateStdinData,
+		validateLogLineTemplate,
 	)
 	admitProcesses(opts, mergedProject)
 	return mergedProject, err
@@ -78,45 +138,159 @@ func admitProcesses(opts *LoaderOptions, p *types.Project) *types.Project {
 	return p
 }
 
-func loadProjectFromFile(inputFile string, disableDotEnv bool, envFileNames []string) *types.Project {
+// loadInlineProject loads the PROCESS_COMPOSE_CONFIG content. It has no file, its relative paths are relative to
+// the working directory
+func loadInlineProject(opts *LoaderOptions) error {
+	p, err := loadProjectFromData(inlineConfigLocation, opts.inlineConfig, opts.disableDotenv, opts.EnvFileNames)
+	if err != nil {
+		return err
+	}
+	dir, err := opts.getWorkingDir()
+	if err != nil {
+		return err
+	}
+	resolveImportPaths(p, dir)
+	resolveWorkingDirs(p, dir)
+	opts.projects = append(opts.projects, p)
+	return nil
+}
+
+func loadProjectFromFile(inputFile string, disableDotEnv bool, envFileNames []string) (*types.Project, error) {
 	yamlFile, err := os.ReadFile(inputFile)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			log.Error().Msgf("File %s doesn't exist", inputFile)
 		}
-		log.Fatal().Err(err).Msgf("Failed to read %s", inputFile)
+		return nil, fmt.Errorf("failed to read %s: %w", inputFile, err)
 	}
+	return loadProjectFromData(inputFile, yamlFile, disableDotEnv, envFileNames)
+}
 
+// loadProjectFromData loads the project from the config content, inputFile is only used to report its location
+func loadProjectFromData(inputFile string, yamlFile []byte, disableDotEnv bool, envFileNames []string) (*types.Project, error) {
+	var err error
 	if !disableDotEnv {
 		// .env is optional we don't care if it errors
-		_ = godotenv.Load(envFileNames...)
+		for _, file := range envFileNames {
+			_ = godotenv.Load(file)
+		}
 	}
 
-	const envEscaped = "##PC_ENV_ESCAPED##"
-	// replace escaped $$ env vars in yaml
-	temp := strings.ReplaceAll(string(yamlFile), "$$", envEscaped)
-	temp = os.ExpandEnv(temp)
-	temp = strings.ReplaceAll(temp, envEscaped, "$")
-
+	// anchors and aliases are resolved by the parser before the env vars are expanded,
+	// so the expanded values can't break the YAML structure
+	var root yaml.Node
+	if err = yaml.Unmarshal(yamlFile, &root); err != nil {
+		return nil, parseError(inputFile, err)
+	}
+	var expansion struct {
+		DisableEnvExpansion bool `yaml:"disable_env_expansion"`
+	}
+	if err = decodeNode(&root, &expansion); err != nil {
+		return nil, parseError(inputFile, err)
+	}
+	if !expansion.DisableEnvExpansion {
+		expandEnvNode(&root)
+	}
 	project := &types.Project{
 		LogLength: defaultLogLength,
 	}
-	err = yaml.Unmarshal([]byte(temp), project)
-	if err != nil {
-		log.Fatal().Err(err).Msgf("Failed to parse %s", inputFile)
+	if err = decodeNode(&root, project); err != nil {
+		return nil, parseError(inputFile, err)
 	}
-	if project.DisableEnvExpansion {
-		err = yaml.Unmarshal(yamlFile, project)
-		if err != nil {
-			log.Fatal().Err(err).Msgf("Failed to parse %s", inputFile)
+	setProcessLocations(project, inputFile, &root)
+	expandHomePaths(project)
+
+	log.Info().Msgf("Loaded project from %s", inputFile)
+	return project, nil
+}
+
+var yamlLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): `)
+
+// parseError reports the YAML errors with their file:line location
+func parseError(inputFile string, err error) error {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		errs := make([]string, len(typeErr.Errors))
+		for i, e := range typeErr.Errors {
+			errs[i] = yamlLineRegex.ReplaceAllString(e, inputFile+":$1: ")
+		}
+		return fmt.Errorf("failed to parse %s:\n%s", inputFile, strings.Join(errs, "\n"))
+	}
+	if yamlLineRegex.MatchString(err.Error()) {
+		return fmt.Errorf("failed to parse %s", yamlLineRegex.ReplaceAllString(err.Error(), inputFile+":$1: "))
+	}
+	return fmt.Errorf("failed to parse %s: %w", inputFile, err)
+}
+
+const envEscaped = "##PC_ENV_ESCAPED##"
+
+func decodeNode(root *yaml.Node, v any) error {
+	if root.Kind == 0 {
+		// empty document
+		return nil
+	}
+	return root.Decode(v)
+}
+
+// expandEnvNode expands the env vars in all the scalar nodes. Escaped $$ is replaced with $
+func expandEnvNode(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && strings.Contains(n.Value, "$") {
+		temp := strings.ReplaceAll(n.Value, "$$", envEscaped)
+		temp = os.Expand(temp, expandParameter)
+		n.Value = strings.ReplaceAll(temp, envEscaped, "$")
+		if n.Style == 0 {
+			// let the decoder resolve the type of the expanded plain value (e.g. ${PORT} into an int)
+			n.Tag = ""
+		}
+	}
+	for _, c := range n.Content {
+		expandEnvNode(c)
+	}
+}
+
+// setProcessLocations records the file and line in which each process is defined
+func setProcessLocations(p *types.Project, inputFile string, root *yaml.Node) {
+	if len(root.Content) == 0 {
+		return
+	}
+	doc := root.Content[0]
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "processes" {
+			continue
+		}
+		processes := doc.Content[i+1]
+		for j := 0; j+1 < len(processes.Content); j += 2 {
+			key := processes.Content[j]
+			if proc, ok := p.Processes[key.Value]; ok {
+				proc.Location = types.Location{File: inputFile, Line: key.Line}
+				p.Processes[key.Value] = proc
+			}
 		}
 	}
+}
 
+// configDir returns the absolute directory of a config file, or "." for stdin
+func configDir(file string) string {
+	if file == "-" {
+		return "."
+	}
+	absFile, err := filepath.Abs(file)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Failed to validate %s", inputFile)
+		return "."
+	}
+	return filepath.Dir(absFile)
+}
+
+// resolveWorkingDirs makes the relative process working directories relative to dir, the directory
+// of the file they are defined in. Templated directories are rendered later and left as is
+func resolveWorkingDirs(p *types.Project, dir string) {
+	for name, proc := range p.Processes {
+		if proc.WorkingDir == "" || filepath.IsAbs(proc.WorkingDir) || strings.HasPrefix(proc.WorkingDir, "{{") {
+			continue
+		}
+		proc.WorkingDir = filepath.Join(dir, proc.WorkingDir)
+		p.Processes[name] = proc
 	}
-	log.Info().Msgf("Loaded project from %s", inputFile)
-	return project
 }
 
 func findFiles(names []string, pwd string) []string {
@@ -154,13 +328,12 @@ func autoDiscoverComposeFile(opts *LoaderOptions) error {
 	if err != nil {
 		return err
 	}
-	candidates := findFiles(DefaultFileNames, pwd)
-	if len(candidates) > 0 {
-		if len(candidates) > 1 {
-			log.Warn().Msgf("Found multiple config files with supported names: %s", strings.Join(candidates, ", "))
-			log.Warn().Msgf("Using %s", candidates[0])
+	if local := findComposeFile(pwd); local != "" {
+		if global := findGlobalComposeFile(opts.getGlobalConfigDirs(), local); global != "" {
+			log.Info().Msgf("Using global config file %s", global)
+			opts.FileNames = append(opts.FileNames, global)
 		}
-		opts.FileNames = append(opts.FileNames, candidates[0])
+		opts.FileNames = append(opts.FileNames, local)
 
 		overrides := findFiles(DefaultOverrideFileNames, pwd)
 		if len(overrides) > 0 {
@@ -172,5 +345,96 @@ func autoDiscoverComposeFile(opts *LoaderOptions) error {
 		}
 		return nil
 	}
+	if found, err := discoverEnvComposeFile(opts); found || err != nil {
+		return err
+	}
 	return fmt.Errorf("no config files found in %s", pwd)
 }
+
+// findComposeFile returns the preferred config file found in pwd
+func findComposeFile(pwd string) string {
+	candidates := findFiles(DefaultFileNames, pwd)
+	if len(candidates) == 0 {
+		return ""
+	}
+	if len(candidates) > 1 {
+		log.Warn().Msgf("Found multiple config files with supported names: %s", strings.Join(candidates, ", "))
+		log.Warn().Msgf("Using %s", candidates[0])
+	}
+	return candidates[0]
+}
+
+// DiscoverConfigFile returns the config file to update by the commands editing a single file, found as on load: the
+// first of PC_CONFIG_FILES, the config file in the working directory or the PROCESS_COMPOSE_CONFIG file.
+// It defaults to a process-compose.yaml file created in the working directory
+func DiscoverConfigFile() (string, error) {
+	return discoverConfigFile(&LoaderOptions{FileNames: config.GetConfigDefault()})
+}
+
+func discoverConfigFile(opts *LoaderOptions) (string, error) {
+	if len(opts.FileNames) > 0 {
+		return opts.FileNames[0], nil
+	}
+	pwd, err := opts.getWorkingDir()
+	if err != nil {
+		return "", err
+	}
+	if local := findComposeFile(pwd); local != "" {
+		return local, nil
+	}
+	if _, err = discoverEnvComposeFile(opts); err != nil {
+		return "", err
+	}
+	if opts.inlineConfig != nil {
+		return "", fmt.Errorf("the %s config is inline, set the config file to update", config.EnvVarNameConfigContent)
+	}
+	if len(opts.FileNames) > 0 {
+		return opts.FileNames[0], nil
+	}
+	return filepath.Join(pwd, "process-compose.yaml"), nil
+}
+
+// inlineConfigLocation is the location of the PROCESS_COMPOSE_CONFIG inline content in the load errors
+const inlineConfigLocation = "$" + config.EnvVarNameConfigContent
+
+const inlineConfigPrefix = "data:"
+
+// discoverEnvComposeFile uses the config of the PROCESS_COMPOSE_CONFIG environment variable: a file path, or
+// the base64 encoded content prefixed with data:
+func discoverEnvComposeFile(opts *LoaderOptions) (bool, error) {
+	val := strings.TrimSpace(os.Getenv(config.EnvVarNameConfigContent))
+	if val == "" {
+		return false, nil
+	}
+	encoded, isInline := strings.CutPrefix(val, inlineConfigPrefix)
+	if !isInline {
+		log.Info().Msgf("Using config file %s from %s", val, config.EnvVarNameConfigContent)
+		opts.FileNames = append(opts.FileNames, val)
+		return true, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return true, fmt.Errorf("failed to decode the %s inline config: %w", config.EnvVarNameConfigContent, err)
+	}
+	log.Info().Msgf("Using the inline config from %s", config.EnvVarNameConfigContent)
+	opts.inlineConfig = data
+	return true, nil
+}
+
+// findGlobalComposeFile returns the first global config file found in dirs.
+// The global config is loaded before the local one, so the local project takes priority on merge.
+func findGlobalComposeFile(dirs []string, local string) string {
+	localAbs, err := filepath.Abs(local)
+	if err != nil {
+		localAbs = local
+	}
+	for _, dir := range dirs {
+		for _, candidate := range findFiles(DefaultFileNames, dir) {
+			if abs, err := filepath.Abs(candidate); err == nil && abs == localAbs {
+				continue
+			}
+			return candidate
+		}
+	}
+	return ""
+}
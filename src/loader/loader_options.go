@@ -2,15 +2,23 @@ package loader
 
 import (
 	"github.com/f1bonacc1/process-compose/src/admitter"
+	"github.com/f1bonacc1/process-compose/src/config"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"os"
 	"path/filepath"
 )
 
 type LoaderOptions struct {
-	workingDir    string
-	FileNames     []string
-	EnvFileNames  []string
+	workingDir       string
+	globalConfigDirs []string
+	stateHome        string
+	FileNames        []string
+	inlineConfig     []byte
+	EnvFileNames     []string
+	// ExtraEnvFileNames are the env files loaded in addition to the EnvFileNames
+	ExtraEnvFileNames []string
+	// EnvVars are the KEY=VALUE variables set from the command line, they override all the other sources
+	EnvVars       []string
 	projects      []*types.Project
 	admitters     []admitter.Admitter
 	disableDotenv bool
@@ -37,6 +45,20 @@ func (o *LoaderOptions) getWorkingDir() (string, error) {
 	return os.Getwd()
 }
 
+func (o *LoaderOptions) getGlobalConfigDirs() []string {
+	if o.globalConfigDirs != nil {
+		return o.globalConfigDirs
+	}
+	return config.GetGlobalConfigDirs()
+}
+
+func (o *LoaderOptions) getStateHome() string {
+	if o.stateHome != "" {
+		return o.stateHome
+	}
+	return config.GetStateHome()
+}
+
 func (o *LoaderOptions) DisableDotenv() {
 	o.disableDotenv = true
 }
@@ -1,7 +1,15 @@
 package loader
 
 import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
 )
 
 func Test_autoDiscoverComposeFile(t *testing.T) {
@@ -17,9 +25,10 @@ func Test_autoDiscoverComposeFile(t *testing.T) {
 			name: "Should not find",
 			args: args{
 				opts: &LoaderOptions{
-					workingDir: "../../fixtures",
-					FileNames:  nil,
-					projects:   nil,
+					workingDir:       "../../fixtures",
+					globalConfigDirs: []string{},
+					FileNames:        nil,
+					projects:         nil,
 				},
 			},
 			wantErr: true,
@@ -28,9 +37,10 @@ func Test_autoDiscoverComposeFile(t *testing.T) {
 			name: "Should find process-compose.yaml",
 			args: args{
 				opts: &LoaderOptions{
-					workingDir: "../../",
-					FileNames:  nil,
-					projects:   nil,
+					workingDir:       "../../",
+					globalConfigDirs: []string{},
+					FileNames:        nil,
+					projects:         nil,
 				},
 			},
 			wantErr: false,
@@ -61,3 +71,557 @@ func Test_autoDiscoverComposeFile(t *testing.T) {
 		})
 	}
 }
+
+func Test_autoDiscoverGlobalComposeFile(t *testing.T) {
+	globalDir := t.TempDir()
+	globalFile := filepath.Join(globalDir, "process-compose.yaml")
+	if err := os.WriteFile(globalFile, []byte("version: \"0.5\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := &LoaderOptions{
+		workingDir:       "../../",
+		globalConfigDirs: []string{t.TempDir(), globalDir},
+	}
+	if err := autoDiscoverComposeFile(opts); err != nil {
+		t.Fatalf("autoDiscoverComposeFile() error = %v", err)
+	}
+	if len(opts.FileNames) < 2 {
+		t.Fatalf("autoDiscoverComposeFile() FileNames = %v, want global and local files", opts.FileNames)
+	}
+	if opts.FileNames[0] != globalFile {
+		t.Errorf("autoDiscoverComposeFile() first file = %s, want %s", opts.FileNames[0], globalFile)
+	}
+	if opts.FileNames[1] != filepath.Join("../../", "process-compose.yaml") {
+		t.Errorf("autoDiscoverComposeFile() second file = %s, want local config", opts.FileNames[1])
+	}
+
+	noLocal := &LoaderOptions{
+		workingDir:       "../../fixtures",
+		globalConfigDirs: []string{globalDir},
+	}
+	if err := autoDiscoverComposeFile(noLocal); err == nil {
+		t.Errorf("autoDiscoverComposeFile() expected error without a local config, got %v", noLocal.FileNames)
+	}
+}
+
+func Test_discoverConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(t.TempDir(), "config.yaml")
+	tests := []struct {
+		name    string
+		files   []string
+		local   string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "Config files", files: []string{"first.yaml", "second.yaml"}, local: "compose.yaml", want: "first.yaml"},
+		{name: "Local", local: "compose.yaml", env: envFile, want: filepath.Join(dir, "compose.yaml")},
+		{name: "Env path", env: envFile, want: envFile},
+		{name: "Env inline", env: "data:" + base64.StdEncoding.EncodeToString([]byte("processes: {}")), wantErr: true},
+		{name: "Default", want: filepath.Join(dir, "process-compose.yaml")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROCESS_COMPOSE_CONFIG", tt.env)
+			if tt.local != "" {
+				local := filepath.Join(dir, tt.local)
+				if err := os.WriteFile(local, []byte("processes: {}"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Remove(local)
+			}
+			got, err := discoverConfigFile(&LoaderOptions{FileNames: tt.files, workingDir: dir})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("discoverConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("discoverConfigFile() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_EnvConfig(t *testing.T) {
+	config := `
+processes:
+  proc1:
+    command: "echo 1"
+`
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		env       string
+		wantFiles []string
+		wantErr   bool
+	}{
+		{name: "Path", env: file, wantFiles: []string{file}},
+		{name: "Inline", env: "data:" + base64.StdEncoding.EncodeToString([]byte(config)), wantFiles: nil},
+		{name: "Invalid inline", env: "data:not base64", wantErr: true},
+		{name: "Not set", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROCESS_COMPOSE_CONFIG", tt.env)
+			project, err := Load(&LoaderOptions{
+				workingDir:       t.TempDir(),
+				globalConfigDirs: []string{},
+				stateHome:        t.TempDir(),
+				disableDotenv:    true,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(project.FileNames, tt.wantFiles) {
+				t.Errorf("Load() FileNames = %v, want %v", project.FileNames, tt.wantFiles)
+			}
+			if _, ok := project.Processes["proc1"]; !ok {
+				t.Errorf("Load() processes = %v, want proc1", project.Processes)
+			}
+		})
+	}
+}
+
+func Test_validateEnvVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    []string
+		wantErr bool
+	}{
+		{name: "Vars", vars: []string{"A=1", "B_2=x=y", "EMPTY="}},
+		{name: "No value", vars: []string{"A"}, wantErr: true},
+		{name: "Env file", vars: []string{"./A=1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateEnvVars(tt.vars); (err != nil) != tt.wantErr {
+				t.Errorf("validateEnvVars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_EnvVars(t *testing.T) {
+	dir := t.TempDir()
+	config := `
+environment:
+  - "PC_TEST_OVER=project"
+processes:
+  proc1:
+    command: "echo ${PC_TEST_NEW}"
+    environment:
+      - "PC_TEST_NEW=process"
+      - "PC_TEST_EXTRA=${PC_TEST_EXTRA}"
+`
+	file := filepath.Join(dir, "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	extraEnv := filepath.Join(dir, "extra.env")
+	if err := os.WriteFile(extraEnv, []byte("PC_TEST_EXTRA=extra\nPC_TEST_OVER=extra\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// restored once the test ends
+	for _, key := range []string{"PC_TEST_OVER", "PC_TEST_NEW", "PC_TEST_EXTRA"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:         []string{file},
+		EnvFileNames:      []string{filepath.Join(dir, "missing.env")},
+		ExtraEnvFileNames: []string{extraEnv},
+		EnvVars:           []string{"PC_TEST_OVER=cli", "PC_TEST_NEW=first", "PC_TEST_NEW=cli"},
+		stateHome:         t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	wantProject := types.Environment{"PC_TEST_OVER=cli", "PC_TEST_NEW=cli"}
+	if !slices.Equal(project.Environment, wantProject) {
+		t.Errorf("project environment = %v, want %v", project.Environment, wantProject)
+	}
+	wantCli := []string{"PC_TEST_OVER=cli", "PC_TEST_NEW=first", "PC_TEST_NEW=cli"}
+	if !slices.Equal(project.CliEnvVars, wantCli) {
+		t.Errorf("project command line variables = %v, want %v", project.CliEnvVars, wantCli)
+	}
+	proc := project.Processes["proc1"]
+	wantProc := types.Environment{"PC_TEST_NEW=cli", "PC_TEST_EXTRA=extra"}
+	if !slices.Equal(proc.Environment, wantProc) {
+		t.Errorf("process environment = %v, want %v", proc.Environment, wantProc)
+	}
+	if proc.Command != "echo cli" {
+		t.Errorf("process command = %q, want the variable expanded with the command line value", proc.Command)
+	}
+}
+
+func TestLoad_Imports(t *testing.T) {
+	root := t.TempDir()
+	authDir := filepath.Join(root, "services", "auth")
+	if err := os.MkdirAll(authDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	auth := `
+environment:
+  - "AUTH_MODE=dev"
+processes:
+  database:
+    command: "echo database"
+  server:
+    command: "echo server"
+    depends_on:
+      database:
+        condition: process_started
+  worker:
+    base_process: server
+    command: "echo worker"
+`
+	if err := os.WriteFile(filepath.Join(authDir, "process-compose.yaml"), []byte(auth), 0600); err != nil {
+		t.Fatal(err)
+	}
+	main := `
+imports:
+  - path: ./services/auth
+    namespace: auth
+processes:
+  web:
+    command: "echo web"
+    depends_on:
+      auth/server:
+        condition: process_started
+`
+	mainFile := filepath.Join(root, "process-compose.yaml")
+	if err := os.WriteFile(mainFile, []byte(main), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts := &LoaderOptions{
+		FileNames:     []string{mainFile},
+		disableDotenv: true,
+	}
+	project, err := Load(opts)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	server, ok := project.Processes["auth/server"]
+	if !ok {
+		t.Fatalf("expected the imported process auth/server, got %v", project.Processes)
+	}
+	if _, ok = server.DependsOn["auth/database"]; !ok {
+		t.Errorf("expected auth/server to depend on auth/database, got %v", server.DependsOn)
+	}
+	if server.WorkingDir != authDir {
+		t.Errorf("expected auth/server working dir %s, got %s", authDir, server.WorkingDir)
+	}
+	if len(server.Environment) != 1 || server.Environment[0] != "AUTH_MODE=dev" {
+		t.Errorf("expected auth/server to inherit the imported environment, got %v", server.Environment)
+	}
+	worker := project.Processes["auth/worker"]
+	if _, ok = worker.DependsOn["auth/database"]; !ok {
+		t.Errorf("expected auth/worker to inherit the auth/server dependency, got %v", worker.DependsOn)
+	}
+	if _, ok = project.Processes["web"]; !ok {
+		t.Errorf("expected the process web, got %v", project.Processes)
+	}
+}
+
+func TestLoad_ErrorLocation(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "Syntax",
+			config: `processes:
+  proc1:
+    command: echo: 1
+`,
+			wantErr: "process-compose.yaml:3: mapping values are not allowed",
+		},
+		{
+			name: "Type",
+			config: `processes:
+  proc1:
+    command: "echo 1"
+    is_tty: maybe
+`,
+			wantErr: "process-compose.yaml:4: cannot unmarshal",
+		},
+		{
+			name: "Validation",
+			config: `processes:
+  proc1:
+    command: "echo 1"
+  proc2:
+    command: "echo 2"
+    ready_log_line: "ready"
+    readiness_probe:
+      exec:
+        command: "true"
+`,
+			wantErr: "process-compose.yaml:4: 'ready_log_line' and readiness probe",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "process-compose.yaml")
+			if err := os.WriteFile(file, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(&LoaderOptions{
+				FileNames:     []string{file},
+				disableDotenv: true,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_AnchorsWithEnvExpansion(t *testing.T) {
+	t.Setenv("PC_TEST_GREETING", "hello: world # not a comment")
+	t.Setenv("PC_TEST_LOG_LENGTH", "42")
+	config := `
+log_length: ${PC_TEST_LOG_LENGTH}
+x-defaults: &defaults
+  working_dir: /tmp
+  environment:
+    - "GREETING=${PC_TEST_GREETING}"
+processes:
+  proc1:
+    <<: *defaults
+    command: "echo $${HOME}"
+  proc2:
+    <<: *defaults
+    command: "echo 2"
+`
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:     []string{file},
+		disableDotenv: true,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if project.LogLength != 42 {
+		t.Errorf("expected log length 42, got %d", project.LogLength)
+	}
+	for _, name := range []string{"proc1", "proc2"} {
+		proc := project.Processes[name]
+		if proc.WorkingDir != "/tmp" {
+			t.Errorf("expected %s working dir /tmp, got %s", name, proc.WorkingDir)
+		}
+		if len(proc.Environment) != 1 || proc.Environment[0] != "GREETING=hello: world # not a comment" {
+			t.Errorf("expected %s to have the expanded environment, got %v", name, proc.Environment)
+		}
+	}
+	if cmd := project.Processes["proc1"].Command; cmd != "echo ${HOME}" {
+		t.Errorf("expected escaped command, got %s", cmd)
+	}
+}
+
+func TestLoad_EnvFileInHomeDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	if err := os.WriteFile(filepath.Join(home, "test.env"), []byte("PC_TEST_GREETING=hello\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Unsetenv("PC_TEST_GREETING") })
+	config := `
+processes:
+  proc:
+    command: "echo ${PC_TEST_GREETING}"
+`
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	envFiles := []string{"~/test.env"}
+	project, err := Load(&LoaderOptions{
+		FileNames:    []string{file},
+		EnvFileNames: envFiles,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cmd := project.Processes["proc"].Command; cmd != "echo hello" {
+		t.Errorf("expected the command expanded from the env file, got %s", cmd)
+	}
+	if envFiles[0] != "~/test.env" {
+		t.Errorf("expected the caller env files to be left as is, got %v", envFiles)
+	}
+}
+
+func TestLoad_WorkingDirRelativeToConfigFile(t *testing.T) {
+	config := `
+processes:
+  relative:
+    command: "echo relative"
+    working_dir: ./logs
+  absolute:
+    command: "echo absolute"
+    working_dir: /tmp
+  unset:
+    command: "echo unset"
+`
+	dir := filepath.Join(t.TempDir(), "services", "web")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:     []string{file},
+		disableDotenv: true,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{
+		"relative": filepath.Join(dir, "logs"),
+		"absolute": "/tmp",
+		"unset":    "",
+	}
+	for name, wd := range want {
+		if got := project.Processes[name].WorkingDir; got != wd {
+			t.Errorf("expected %s working dir %q, got %q", name, wd, got)
+		}
+	}
+}
+
+func TestLoad_DependencyOnReplicas(t *testing.T) {
+	config := `
+is_strict: true
+processes:
+  worker:
+    command: "./worker"
+    replicas: 2
+    readiness_probe:
+      exec:
+        command: "./worker --ping"
+  api:
+    command: "./api"
+    depends_on:
+      worker:
+        condition: process_healthy
+        ready_replicas: 1
+`
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:     []string{file},
+		disableDotenv: true,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := project.Processes["api"].DependsOn["worker"].ReadyReplicas; got != 1 {
+		t.Errorf("expected ready_replicas 1, got %d", got)
+	}
+}
+
+func TestAddProcess(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	config := `# comment
+processes:
+  web:
+    command: "echo web"
+`
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	name := ProcessNameFromCommand("./bin/server --port 8080")
+	if name != "server" {
+		t.Fatalf("ProcessNameFromCommand() = %s, want server", name)
+	}
+	if err := AddProcess(file, name, "./bin/server --port 8080"); err != nil {
+		t.Fatalf("AddProcess() error = %v", err)
+	}
+	if err := AddProcess(file, name, "./bin/server"); err == nil {
+		t.Errorf("AddProcess() expected an error for an existing process")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# comment") {
+		t.Errorf("expected the comment to be kept, got:\n%s", data)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:     []string{file},
+		disableDotenv: true,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cmd := project.Processes["server"].Command; cmd != "./bin/server --port 8080" {
+		t.Errorf("expected the server command, got %s", cmd)
+	}
+	if _, ok := project.Processes["web"]; !ok {
+		t.Errorf("expected the web process to be kept")
+	}
+}
+
+func TestLoad_ReplicaTemplateVars(t *testing.T) {
+	config := `
+processes:
+  server:
+    command: "./server --port {{add .BASE_PORT .ReplicaIndex}} --run {{.RunID}}"
+    log_location: "./logs/{{.ProcessName}}-{{.ReplicaIndex}}.log"
+    replicas: 3
+    vars:
+      BASE_PORT: 8080
+    readiness_probe:
+      http_get:
+        port: "{{add .BASE_PORT .ReplicaIndex}}"
+  containers:
+    command: "docker ps --format '{{.Names}}'"
+    replicas: 2
+`
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:     []string{file},
+		disableDotenv: true,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, proc := range project.Processes {
+		if proc.Name == "containers" {
+			if want := "docker ps --format '{{.Names}}'"; proc.Command != want {
+				t.Errorf("%s command = %s, want the command without vars left as is", proc.ReplicaName, proc.Command)
+			}
+			continue
+		}
+		port := strconv.Itoa(8080 + proc.ReplicaNum)
+		wantCommand := "./server --port " + port + " --run " + project.RunID
+		if proc.Command != wantCommand {
+			t.Errorf("%s command = %s, want %s", proc.ReplicaName, proc.Command, wantCommand)
+		}
+		wantLog := "./logs/server-" + strconv.Itoa(proc.ReplicaNum) + ".log"
+		if proc.LogLocation != wantLog {
+			t.Errorf("%s log_location = %s, want %s", proc.ReplicaName, proc.LogLocation, wantLog)
+		}
+		if proc.ReadinessProbe.HttpGet.Port != port {
+			t.Errorf("%s readiness probe port = %s, want %s", proc.ReplicaName, proc.ReadinessProbe.HttpGet.Port, port)
+		}
+	}
+}
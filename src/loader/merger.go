@@ -141,16 +141,55 @@ func mergeProcesses(base, override types.Processes) (types.Processes, error) {
 }
 
 func mergeProcess(base, override *types.ProcessConfig) (*types.ProcessConfig, error) {
+	if err := applyMergeStrategy(base, override.MergeStrategy); err != nil {
+		return nil, err
+	}
 	if err := mergo.Merge(base, override,
 		mergo.WithAppendSlice,
 		mergo.WithOverride,
 		mergo.WithTransformers(processSpecials)); err != nil {
 		return nil, err
 	}
+	// the strategy applies to a single merge
+	base.MergeStrategy = nil
 
 	return base, nil
 }
 
+// applyMergeStrategy clears the base list and map fields with the replace strategy, so they are
+// replaced by the override values instead of being merged with them
+func applyMergeStrategy(base *types.ProcessConfig, strategy map[string]string) error {
+	for key, value := range strategy {
+		field, ok := fieldByYamlKey(reflect.ValueOf(base).Elem(), key)
+		if !ok {
+			return fmt.Errorf("unknown merge_strategy field %s", key)
+		}
+		if kind := field.Kind(); kind != reflect.Slice && kind != reflect.Map {
+			return fmt.Errorf("merge_strategy field %s is not a list or a map", key)
+		}
+		switch value {
+		case types.MergeStrategyAppend:
+		case types.MergeStrategyReplace:
+			field.Set(reflect.Zero(field.Type()))
+		default:
+			return fmt.Errorf("invalid merge_strategy %s for %s, expected %s or %s",
+				value, key, types.MergeStrategyAppend, types.MergeStrategyReplace)
+		}
+	}
+	return nil
+}
+
+// fieldByYamlKey returns the struct field with the given yaml key
+func fieldByYamlKey(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
 func mergeProjects(base, override *types.Project) error {
 	if err := mergo.Merge(base, override,
 		mergo.WithAppendSlice,
@@ -440,3 +440,70 @@ func Test_mergeProcesses(t *testing.T) {
 		})
 	}
 }
+
+func Test_mergeProcessStrategy(t *testing.T) {
+	newBase := func() *types.ProcessConfig {
+		return &types.ProcessConfig{
+			Name:        "proc1",
+			Environment: types.Environment{"A=1", "B=2"},
+			WatchPaths:  []string{"./src"},
+			DependsOn: types.DependsOnConfig{
+				"db": {Condition: types.ProcessConditionStarted},
+			},
+		}
+	}
+	override := &types.ProcessConfig{
+		Environment: types.Environment{"C=3"},
+		WatchPaths:  []string{"./lib"},
+		DependsOn: types.DependsOnConfig{
+			"cache": {Condition: types.ProcessConditionStarted},
+		},
+	}
+
+	merged, err := mergeProcess(newBase(), override)
+	if err != nil {
+		t.Fatalf("mergeProcess() error = %v", err)
+	}
+	if !reflect.DeepEqual(merged.WatchPaths, []string{"./src", "./lib"}) {
+		t.Errorf("appended watch_paths = %v, want %v", merged.WatchPaths, []string{"./src", "./lib"})
+	}
+	if !reflect.DeepEqual([]string(merged.Environment), []string{"A=1", "B=2", "C=3"}) {
+		t.Errorf("appended environment = %v, want %v", merged.Environment, []string{"A=1", "B=2", "C=3"})
+	}
+	if len(merged.DependsOn) != 2 {
+		t.Errorf("merged depends_on = %v, want db and cache", merged.DependsOn)
+	}
+
+	override.MergeStrategy = map[string]string{
+		"environment": types.MergeStrategyReplace,
+		"watch_paths": types.MergeStrategyReplace,
+		"depends_on":  types.MergeStrategyReplace,
+	}
+	merged, err = mergeProcess(newBase(), override)
+	if err != nil {
+		t.Fatalf("mergeProcess() error = %v", err)
+	}
+	if !reflect.DeepEqual(merged.WatchPaths, []string{"./lib"}) {
+		t.Errorf("replaced watch_paths = %v, want %v", merged.WatchPaths, []string{"./lib"})
+	}
+	if !reflect.DeepEqual([]string(merged.Environment), []string{"C=3"}) {
+		t.Errorf("replaced environment = %v, want %v", merged.Environment, []string{"C=3"})
+	}
+	if _, ok := merged.DependsOn["db"]; ok || len(merged.DependsOn) != 1 {
+		t.Errorf("replaced depends_on = %v, want only cache", merged.DependsOn)
+	}
+	if merged.MergeStrategy != nil {
+		t.Errorf("merged merge_strategy = %v, want nil", merged.MergeStrategy)
+	}
+
+	for _, strategy := range []map[string]string{
+		{"command": types.MergeStrategyReplace},
+		{"no_such_field": types.MergeStrategyReplace},
+		{"watch_paths": "prepend"},
+	} {
+		override.MergeStrategy = strategy
+		if _, err = mergeProcess(newBase(), override); err == nil {
+			t.Errorf("mergeProcess() with merge_strategy %v, want an error", strategy)
+		}
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"github.com/f1bonacc1/process-compose/src/templater"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"maps"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type mutatorFunc func(p *types.Project)
@@ -39,6 +43,152 @@ func setDefaultShell(p *types.Project) {
 	log.Info().Msgf("Global shell command: %s %s", p.ShellConfig.ShellCommand, p.ShellConfig.ShellArgument)
 }
 
+// Prefix the process names and their dependencies with the project namespace
+func applyProjectNamespace(p *types.Project) {
+	if p.Namespace == "" {
+		return
+	}
+	processes := make(types.Processes, len(p.Processes))
+	for name, proc := range p.Processes {
+		if len(proc.DependsOn) > 0 {
+			dependsOn := make(types.DependsOnConfig, len(proc.DependsOn))
+			for depName, dep := range proc.DependsOn {
+				if _, ok := p.Processes[depName]; ok {
+					depName = p.Namespace + "/" + depName
+				}
+				dependsOn[depName] = dep
+			}
+			proc.DependsOn = dependsOn
+		}
+		if _, ok := p.Processes[proc.EnvFromProcess]; ok {
+			proc.EnvFromProcess = p.Namespace + "/" + proc.EnvFromProcess
+		}
+		if _, ok := p.Processes[proc.InputFrom]; ok {
+			proc.InputFrom = p.Namespace + "/" + proc.InputFrom
+		}
+		processes[p.Namespace+"/"+name] = proc
+	}
+	p.Processes = processes
+}
+
+// Processes with a base_process use the base process configuration as their defaults
+func applyBaseProcesses(p *types.Project) error {
+	resolved := map[string]bool{}
+	for name := range p.Processes {
+		if err := resolveBaseProcess(p, name, resolved, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveBaseProcess(p *types.Project, name string, resolved, visiting map[string]bool) error {
+	proc := p.Processes[name]
+	if proc.BaseProcess == "" || resolved[name] {
+		return nil
+	}
+	if visiting[name] {
+		return newValidationError(proc.Location, fmt.Sprintf("circular base_process found in '%s'", name))
+	}
+	if _, ok := p.Processes[proc.BaseProcess]; !ok {
+		return newValidationError(proc.Location,
+			fmt.Sprintf("base process '%s' of process '%s' is not defined", proc.BaseProcess, name))
+	}
+	visiting[name] = true
+	if err := resolveBaseProcess(p, proc.BaseProcess, resolved, visiting); err != nil {
+		return err
+	}
+	merged, err := inheritProcess(p.Processes[proc.BaseProcess], proc)
+	if err != nil {
+		return fmt.Errorf("cannot inherit process %s from %s - %v", name, proc.BaseProcess, err)
+	}
+	p.Processes[name] = *merged
+	resolved[name] = true
+	return nil
+}
+
+// inheritProcess merges the process into a deep copy of its base, the same way config files are merged.
+// A disabled base process can serve as a template, so 'disabled' is not inherited
+func inheritProcess(base, proc types.ProcessConfig) (*types.ProcessConfig, error) {
+	data, err := yaml.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	var inherited types.ProcessConfig
+	if err = yaml.Unmarshal(data, &inherited); err != nil {
+		return nil, err
+	}
+	merged, err := mergeProcess(&inherited, &proc)
+	if err != nil {
+		return nil, err
+	}
+	merged.Disabled = proc.Disabled
+	merged.Location = proc.Location
+	return merged, nil
+}
+
+// applyDisabledIf disables the processes whose disabled_if expression is true
+func applyDisabledIf(p *types.Project) error {
+	for name, proc := range p.Processes {
+		if proc.DisabledIf == "" {
+			continue
+		}
+		disabled, err := evalCondition(proc.DisabledIf)
+		if err != nil {
+			return newValidationError(proc.Location, fmt.Sprintf("invalid disabled_if in process '%s': %v", name, err))
+		}
+		if disabled {
+			log.Debug().Msgf("Process %s is disabled by '%s'", name, proc.DisabledIf)
+			proc.Disabled = true
+			p.Processes[name] = proc
+		}
+	}
+	return nil
+}
+
+// evalCondition evaluates 'a == b', 'a != b' and 'a contains b' expressions.
+// A value without an operator is true if it is a true boolean (true, 1, yes...)
+func evalCondition(expr string) (bool, error) {
+	for _, op := range []string{"==", "!=", " contains "} {
+		left, right, found := strings.Cut(expr, op)
+		if !found {
+			continue
+		}
+		left, right = unquote(left), unquote(right)
+		switch op {
+		case "==":
+			return left == right, nil
+		case "!=":
+			return left != right, nil
+		default:
+			return strings.Contains(left, right), nil
+		}
+	}
+	value := unquote(expr)
+	if value == "" {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(strings.ToLower(value))
+	if err != nil {
+		switch strings.ToLower(value) {
+		case "yes", "on":
+			return true, nil
+		case "no", "off":
+			return false, nil
+		}
+		return false, fmt.Errorf("'%s' is not a boolean or a ==, != or contains expression", expr)
+	}
+	return result, nil
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
 func assignDefaultProcessValues(p *types.Project) {
 	for name, proc := range p.Processes {
 		if proc.Namespace == "" {
@@ -47,11 +197,115 @@ func assignDefaultProcessValues(p *types.Project) {
 		if proc.Replicas == 0 {
 			proc.Replicas = 1
 		}
+		if proc.MaxRestartsPerMinute == 0 {
+			proc.MaxRestartsPerMinute = p.MaxRestartsPerMinute
+		}
+		if proc.LogAppend == nil {
+			// the previous logs of a service are kept, the logs of one-shot processes are truncated
+			isService := proc.IsService()
+			proc.LogAppend = &isService
+		}
 		proc.Name = name
 		p.Processes[name] = proc
 	}
 }
 
+// Processes inherit the project default ulimits, unless they override them
+func applyDefaultUlimits(p *types.Project) {
+	if len(p.DefaultUlimits) == 0 {
+		return
+	}
+	for name, proc := range p.Processes {
+		ulimits := make(types.Ulimits, len(p.DefaultUlimits)+len(proc.Ulimits))
+		for resource, limit := range p.DefaultUlimits {
+			ulimits[resource] = limit
+		}
+		for resource, limit := range proc.Ulimits {
+			ulimits[resource] = limit
+		}
+		proc.Ulimits = ulimits
+		p.Processes[name] = proc
+	}
+}
+
+// Processes with enable_core_dump get an unlimited core file size, unless they set their own core ulimit
+func applyCoreDump(p *types.Project) {
+	for name, proc := range p.Processes {
+		if !proc.EnableCoreDump {
+			continue
+		}
+		if _, ok := proc.Ulimits["core"]; ok {
+			continue
+		}
+		ulimits := make(types.Ulimits, len(proc.Ulimits)+1)
+		for resource, limit := range proc.Ulimits {
+			ulimits[resource] = limit
+		}
+		ulimits["core"] = types.UlimitUnlimited
+		proc.Ulimits = ulimits
+		p.Processes[name] = proc
+	}
+}
+
+// Processes with input_from are started after the process they read from
+func applyInputFrom(p *types.Project) {
+	for name, proc := range p.Processes {
+		if proc.InputFrom == "" || proc.InputFrom == name {
+			continue
+		}
+		if _, ok := proc.DependsOn[proc.InputFrom]; ok {
+			continue
+		}
+		if proc.DependsOn == nil {
+			proc.DependsOn = types.DependsOnConfig{}
+		}
+		proc.DependsOn[proc.InputFrom] = types.ProcessDependency{Condition: types.ProcessConditionStarted}
+		p.Processes[name] = proc
+	}
+}
+
+// Processes outside the startup barrier depend on all the barrier processes to be ready
+func applyStartupBarrier(p *types.Project) {
+	barrier := make(map[string]types.ProcessConfig, len(p.StartupBarrier))
+	for _, name := range p.StartupBarrier {
+		name = p.ResolveProcessName(name)
+		if proc, ok := p.Processes[name]; ok && !proc.Disabled {
+			barrier[name] = proc
+		}
+	}
+	if len(barrier) == 0 {
+		return
+	}
+	for name, proc := range p.Processes {
+		if _, ok := barrier[name]; ok {
+			continue
+		}
+		dependsOn := make(types.DependsOnConfig, len(proc.DependsOn)+len(barrier))
+		for depName, dep := range proc.DependsOn {
+			dependsOn[depName] = dep
+		}
+		for depName, depProc := range barrier {
+			if _, ok := dependsOn[depName]; !ok {
+				dependsOn[depName] = types.ProcessDependency{Condition: barrierCondition(depProc)}
+			}
+		}
+		proc.DependsOn = dependsOn
+		p.Processes[name] = proc
+	}
+}
+
+// barrierCondition is the readiness condition of a startup barrier process
+func barrierCondition(proc types.ProcessConfig) string {
+	switch {
+	case proc.ReadinessProbe != nil:
+		return types.ProcessConditionHealthy
+	case proc.ReadyLogLine != "":
+		return types.ProcessConditionLogReady
+	default:
+		return types.ProcessConditionStarted
+	}
+}
+
 // Exec Probes should use the same working dir if not specified otherwise
 func copyWorkingDirToProbes(p *types.Project) {
 	for name, proc := range p.Processes {
@@ -80,6 +334,11 @@ func cloneReplicas(p *types.Project) {
 			proc.ReplicaNum = replica
 			repName := proc.CalculateReplicaName()
 			proc.ReplicaName = repName
+			if proc.Replicas > 1 {
+				// the probes are rendered for each replica
+				proc.ReadinessProbe = cloneProbe(proc.ReadinessProbe)
+				proc.LivenessProbe = cloneProbe(proc.LivenessProbe)
+			}
 			if proc.Replicas == 1 {
 				p.Processes[repName] = proc
 			} else {
@@ -95,6 +354,26 @@ func cloneReplicas(p *types.Project) {
 	}
 }
 
+func cloneProbe(probe *health.Probe) *health.Probe {
+	if probe == nil {
+		return nil
+	}
+	clone := *probe
+	if probe.Exec != nil {
+		exec := *probe.Exec
+		clone.Exec = &exec
+	}
+	if probe.HttpGet != nil {
+		httpGet := *probe.HttpGet
+		clone.HttpGet = &httpGet
+	}
+	if probe.TcpSocket != nil {
+		tcpSocket := *probe.TcpSocket
+		clone.TcpSocket = &tcpSocket
+	}
+	return &clone
+}
+
 func assignExecutableAndArgs(p *types.Project) {
 	for name, proc := range p.Processes {
 		elevatedShellArg := p.ShellConfig.ElevatedShellArg
@@ -134,12 +413,16 @@ func renderTemplates(p *types.Project) error {
 		if len(p.Vars) == 0 && len(proc.Vars) == 0 {
 			continue
 		}
-		proc.Command = tpl.RenderWithExtraVars(proc.Command, proc.Vars)
-		proc.WorkingDir = tpl.RenderWithExtraVars(proc.WorkingDir, proc.Vars)
-		proc.LogLocation = tpl.RenderWithExtraVars(proc.LogLocation, proc.Vars)
-		proc.Description = tpl.RenderWithExtraVars(proc.Description, proc.Vars)
-		renderProbe(proc.ReadinessProbe, tpl, proc.Vars)
-		renderProbe(proc.LivenessProbe, tpl, proc.Vars)
+		vars := processTemplateVars(p, &proc)
+		proc.Command = tpl.RenderWithExtraVars(proc.Command, vars)
+		proc.WorkingDir = tpl.RenderWithExtraVars(proc.WorkingDir, vars)
+		proc.LogLocation = tpl.RenderWithExtraVars(proc.LogLocation, vars)
+		proc.StdoutLogLocation = tpl.RenderWithExtraVars(proc.StdoutLogLocation, vars)
+		proc.StderrLogLocation = tpl.RenderWithExtraVars(proc.StderrLogLocation, vars)
+		proc.Description = tpl.RenderWithExtraVars(proc.Description, vars)
+		proc.RunbookURL = tpl.RenderWithExtraVars(proc.RunbookURL, vars)
+		renderProbe(proc.ReadinessProbe, tpl, vars)
+		renderProbe(proc.LivenessProbe, tpl, vars)
 
 		if tpl.GetError() != nil {
 			return fmt.Errorf("error rendering template for process %s: %w", name, tpl.GetError())
@@ -149,6 +432,19 @@ func renderTemplates(p *types.Project) error {
 	return nil
 }
 
+// processTemplateVars returns the process metadata template variables, overridden by the global and local vars
+func processTemplateVars(p *types.Project, proc *types.ProcessConfig) types.Vars {
+	vars := types.Vars{
+		"ReplicaIndex": proc.ReplicaNum,
+		"ProcessName":  proc.Name,
+		"Namespace":    proc.Namespace,
+		"RunID":        p.RunID,
+	}
+	maps.Copy(vars, p.Vars)
+	maps.Copy(vars, proc.Vars)
+	return vars
+}
+
 func renderProbe(probe *health.Probe, tpl *templater.Templater, vars types.Vars) {
 	if probe == nil {
 		return
@@ -161,6 +457,9 @@ func renderProbe(probe *health.Probe, tpl *templater.Templater, vars types.Vars)
 		probe.HttpGet.Host = tpl.RenderWithExtraVars(probe.HttpGet.Host, vars)
 		probe.HttpGet.Scheme = tpl.RenderWithExtraVars(probe.HttpGet.Scheme, vars)
 		probe.HttpGet.Port = tpl.RenderWithExtraVars(probe.HttpGet.Port, vars)
+	} else if probe.TcpSocket != nil {
+		probe.TcpSocket.Host = tpl.RenderWithExtraVars(probe.TcpSocket.Host, vars)
+		probe.TcpSocket.Port = tpl.RenderWithExtraVars(probe.TcpSocket.Port, vars)
 	}
 	probe.ValidateAndSetDefaults()
 }
@@ -5,6 +5,8 @@ import (
 	"github.com/f1bonacc1/process-compose/src/command"
 	"github.com/f1bonacc1/process-compose/src/health"
 	"github.com/f1bonacc1/process-compose/src/types"
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -47,6 +49,27 @@ func Test_assignDefaultProcessValues(t *testing.T) {
 	}
 }
 
+func Test_assignDefaultProcessValues_LogAppend(t *testing.T) {
+	keep := false
+	p := &types.Project{
+		Processes: types.Processes{
+			"migrate": {},
+			"api":     {RestartPolicy: types.RestartPolicyConfig{Restart: types.RestartPolicyAlways}},
+			"worker":  {RestartPolicy: types.RestartPolicyConfig{Restart: types.RestartPolicyOnFailure}},
+			"daemon":  {IsDaemon: true},
+			"web":     {RestartPolicy: types.RestartPolicyConfig{Restart: types.RestartPolicyAlways}, LogAppend: &keep},
+		},
+	}
+	assignDefaultProcessValues(p)
+	want := map[string]bool{"migrate": false, "api": true, "worker": true, "daemon": true, "web": false}
+	for name, isAppend := range want {
+		proc := p.Processes[name]
+		if proc.LogAppend == nil || proc.IsLogAppend() != isAppend {
+			t.Errorf("process %s log_append = %v, want %v", name, proc.IsLogAppend(), isAppend)
+		}
+	}
+}
+
 func Test_setDefaultShell(t *testing.T) {
 	type args struct {
 		p *types.Project
@@ -484,3 +507,258 @@ func compareStrings(t *testing.T, expected, actual, scope string) {
 		t.Errorf("Expected %s '%s' to be '%s'", scope, expected, actual)
 	}
 }
+
+func Test_applyDefaultUlimits(t *testing.T) {
+	p := &types.Project{
+		DefaultUlimits: types.Ulimits{"nofile": 1024, "core": 0},
+		Processes: types.Processes{
+			"default": {
+				Name: "default",
+			},
+			"override": {
+				Name:    "override",
+				Ulimits: types.Ulimits{"nofile": 65536},
+			},
+		},
+	}
+	applyDefaultUlimits(p)
+	if got := p.Processes["default"].Ulimits; got["nofile"] != 1024 || got["core"] != 0 || len(got) != 2 {
+		t.Errorf("Expected the default ulimits, got %v", got)
+	}
+	if got := p.Processes["override"].Ulimits; got["nofile"] != 65536 || len(got) != 2 {
+		t.Errorf("Expected the overridden nofile ulimit, got %v", got)
+	}
+}
+
+func Test_applyCoreDump(t *testing.T) {
+	p := &types.Project{
+		Processes: types.Processes{
+			"enabled": {
+				Name:           "enabled",
+				EnableCoreDump: true,
+				Ulimits:        types.Ulimits{"nofile": 1024},
+			},
+			"explicit": {
+				Name:           "explicit",
+				EnableCoreDump: true,
+				Ulimits:        types.Ulimits{"core": 4096},
+			},
+			"disabled": {
+				Name: "disabled",
+			},
+		},
+	}
+	applyCoreDump(p)
+	if got := p.Processes["enabled"].Ulimits; got["core"] != math.MaxInt64 || got["nofile"] != 1024 {
+		t.Errorf("Expected an unlimited core ulimit, got %v", got)
+	}
+	if got := p.Processes["explicit"].Ulimits; got["core"] != 4096 {
+		t.Errorf("Expected the explicit core ulimit, got %v", got)
+	}
+	if got := p.Processes["disabled"].Ulimits; got != nil {
+		t.Errorf("Expected no ulimits, got %v", got)
+	}
+}
+
+func Test_applyProjectNamespace(t *testing.T) {
+	p := &types.Project{
+		Namespace: "team-a",
+		Processes: types.Processes{
+			"db": {},
+			"api": {
+				DependsOn: types.DependsOnConfig{
+					"db":        {Condition: types.ProcessConditionStarted},
+					"team-b/mq": {Condition: types.ProcessConditionStarted},
+				},
+			},
+		},
+	}
+	applyProjectNamespace(p)
+	assignDefaultProcessValues(p)
+	api, ok := p.Processes["team-a/api"]
+	if !ok || api.Name != "team-a/api" {
+		t.Fatalf("Expected the namespaced process team-a/api, got %v", p.Processes)
+	}
+	if _, ok = api.DependsOn["team-a/db"]; !ok {
+		t.Errorf("Expected the dependency on team-a/db, got %v", api.DependsOn)
+	}
+	if _, ok = api.DependsOn["team-b/mq"]; !ok {
+		t.Errorf("Expected the external dependency on team-b/mq to be kept, got %v", api.DependsOn)
+	}
+	if got := p.ResolveProcessName("db"); got != "team-a/db" {
+		t.Errorf("ResolveProcessName(db) = %s, want team-a/db", got)
+	}
+	if got := p.ResolveProcessName("team-a/db"); got != "team-a/db" {
+		t.Errorf("ResolveProcessName(team-a/db) = %s, want team-a/db", got)
+	}
+}
+
+func Test_applyStartupBarrier(t *testing.T) {
+	p := &types.Project{
+		StartupBarrier: []string{"db", "cache", "disabled"},
+		Processes: types.Processes{
+			"db": {
+				ReadinessProbe: &health.Probe{},
+			},
+			"cache": {
+				ReadyLogLine: "ready",
+			},
+			"disabled": {
+				Disabled: true,
+			},
+			"api": {
+				DependsOn: types.DependsOnConfig{
+					"db": {Condition: types.ProcessConditionStarted},
+				},
+			},
+		},
+	}
+	applyStartupBarrier(p)
+	want := types.DependsOnConfig{
+		"db":    {Condition: types.ProcessConditionStarted},
+		"cache": {Condition: types.ProcessConditionLogReady},
+	}
+	if got := p.Processes["api"].DependsOn; !reflect.DeepEqual(got, want) {
+		t.Errorf("api depends on %v, want %v", got, want)
+	}
+	want = types.DependsOnConfig{
+		"db":    {Condition: types.ProcessConditionHealthy},
+		"cache": {Condition: types.ProcessConditionLogReady},
+	}
+	if got := p.Processes["disabled"].DependsOn; !reflect.DeepEqual(got, want) {
+		t.Errorf("disabled depends on %v, want %v", got, want)
+	}
+	if got := p.Processes["db"].DependsOn; len(got) != 0 {
+		t.Errorf("barrier process db should not depend on the barrier, got %v", got)
+	}
+}
+
+func Test_applyBaseProcesses(t *testing.T) {
+	p := &types.Project{
+		Processes: types.Processes{
+			"base": {
+				Command:     "echo base",
+				WorkingDir:  "/base",
+				Environment: types.Environment{"A=1", "B=1"},
+				Disabled:    true,
+			},
+			"middle": {
+				BaseProcess: "base",
+				Environment: types.Environment{"B=2"},
+				ReadinessProbe: &health.Probe{
+					Exec: &health.ExecProbe{Command: "true"},
+				},
+			},
+			"leaf": {
+				BaseProcess: "middle",
+				Command:     "echo leaf",
+			},
+		},
+	}
+	if err := applyBaseProcesses(p); err != nil {
+		t.Fatalf("applyBaseProcesses() error = %v", err)
+	}
+	leaf := p.Processes["leaf"]
+	if leaf.Command != "echo leaf" {
+		t.Errorf("leaf command = %s, want echo leaf", leaf.Command)
+	}
+	if leaf.WorkingDir != "/base" {
+		t.Errorf("leaf working dir = %s, want /base", leaf.WorkingDir)
+	}
+	if leaf.Disabled {
+		t.Errorf("leaf should not inherit disabled")
+	}
+	if !reflect.DeepEqual(leaf.Environment, types.Environment{"A=1", "B=2"}) {
+		t.Errorf("leaf environment = %v, want [A=1 B=2]", leaf.Environment)
+	}
+	if leaf.ReadinessProbe == nil || leaf.ReadinessProbe == p.Processes["middle"].ReadinessProbe {
+		t.Errorf("leaf should have its own copy of the readiness probe")
+	}
+
+	p.Processes["base"] = types.ProcessConfig{BaseProcess: "leaf"}
+	if err := applyBaseProcesses(p); err == nil {
+		t.Errorf("applyBaseProcesses() expected a circular base_process error")
+	}
+	p.Processes["base"] = types.ProcessConfig{BaseProcess: "missing"}
+	if err := applyBaseProcesses(p); err == nil {
+		t.Errorf("applyBaseProcesses() expected an undefined base process error")
+	}
+}
+
+func Test_evalCondition(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "true == true", want: true},
+		{expr: "production == development", want: false},
+		{expr: `"production" != development`, want: true},
+		{expr: " == true", want: false},
+		{expr: "linux,darwin contains darwin", want: true},
+		{expr: "linux contains windows", want: false},
+		{expr: "true", want: true},
+		{expr: "0", want: false},
+		{expr: "yes", want: true},
+		{expr: "", want: false},
+		{expr: "maybe", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalCondition(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evalCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyDisabledIf(t *testing.T) {
+	p := &types.Project{
+		Processes: types.Processes{
+			"migrate": {DisabledIf: "true == true"},
+			"seed":    {DisabledIf: "production == development"},
+			"invalid": {DisabledIf: "maybe"},
+		},
+	}
+	if err := applyDisabledIf(p); err == nil {
+		t.Errorf("applyDisabledIf() expected an error for an invalid expression")
+	}
+	delete(p.Processes, "invalid")
+	if err := applyDisabledIf(p); err != nil {
+		t.Fatalf("applyDisabledIf() error = %v", err)
+	}
+	if !p.Processes["migrate"].Disabled {
+		t.Errorf("expected migrate to be disabled")
+	}
+	if p.Processes["seed"].Disabled {
+		t.Errorf("expected seed to be enabled")
+	}
+}
+
+func Test_applyInputFrom(t *testing.T) {
+	p := &types.Project{
+		Processes: types.Processes{
+			"producer": {},
+			"consumer": {InputFrom: "producer"},
+			"explicit": {
+				InputFrom: "producer",
+				DependsOn: types.DependsOnConfig{
+					"producer": {Condition: types.ProcessConditionHealthy},
+				},
+			},
+		},
+	}
+	applyInputFrom(p)
+	want := types.DependsOnConfig{"producer": {Condition: types.ProcessConditionStarted}}
+	if got := p.Processes["consumer"].DependsOn; !reflect.DeepEqual(got, want) {
+		t.Errorf("consumer depends on %v, want %v", got, want)
+	}
+	want = types.DependsOnConfig{"producer": {Condition: types.ProcessConditionHealthy}}
+	if got := p.Processes["explicit"].DependsOn; !reflect.DeepEqual(got, want) {
+		t.Errorf("explicit depends on %v, want %v", got, want)
+	}
+}
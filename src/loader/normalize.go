@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Normalize returns the config file with its mapping keys sorted alphabetically, its comments stripped
+// and a consistent indentation. The sequences order is kept
+func Normalize(fileName string) ([]byte, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return nil, parseError(fileName, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("failed to parse %s: empty document", fileName)
+	}
+	normalizeNode(&doc)
+	data, err = encodeNode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", fileName, err)
+	}
+	return data, nil
+}
+
+// NormalizeFile normalizes the config file in place
+func NormalizeFile(fileName string) error {
+	data, err := Normalize(fileName)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, data, info.Mode().Perm())
+}
+
+type mappingEntry struct {
+	key, value *yaml.Node
+	hasAnchor  bool
+}
+
+func normalizeNode(node *yaml.Node) {
+	node.HeadComment = ""
+	node.LineComment = ""
+	node.FootComment = ""
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!merge" {
+		// otherwise the encoder writes the merge key as '!!merge <<'
+		node.Tag = ""
+	}
+	if node.Kind == yaml.AliasNode {
+		// the alias target is normalized where it is defined
+		return
+	}
+	for _, child := range node.Content {
+		normalizeNode(child)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	entries := make([]mappingEntry, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		entries = append(entries, mappingEntry{
+			key:       node.Content[i],
+			value:     node.Content[i+1],
+			hasAnchor: definesAnchor(node.Content[i+1]),
+		})
+	}
+	// an anchor must be defined before its aliases, so the entries defining anchors are kept first, in their order
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].hasAnchor || entries[j].hasAnchor {
+			return entries[i].hasAnchor && !entries[j].hasAnchor
+		}
+		return entries[i].key.Value < entries[j].key.Value
+	})
+	for i, entry := range entries {
+		node.Content[2*i] = entry.key
+		node.Content[2*i+1] = entry.value
+	}
+}
+
+func definesAnchor(node *yaml.Node) bool {
+	if node.Anchor != "" {
+		return true
+	}
+	for _, child := range node.Content {
+		if child.Kind != yaml.AliasNode && definesAnchor(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeFile(t *testing.T) {
+	config := `# top comment
+version: "0.5"
+x-defaults: &defaults
+    working_dir: /tmp # inline
+processes:
+  zeta:
+    <<: *defaults
+    command: "echo z"
+  alpha:
+      command: "echo a"
+      environment:
+        - "B=2"
+        - "A=1"
+log_level: debug
+`
+	want := `x-defaults: &defaults
+  working_dir: /tmp
+log_level: debug
+processes:
+  alpha:
+    command: "echo a"
+    environment:
+      - "B=2"
+      - "A=1"
+  zeta:
+    <<: *defaults
+    command: "echo z"
+version: "0.5"
+`
+	file := filepath.Join(t.TempDir(), "process-compose.yaml")
+	if err := os.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NormalizeFile(file); err != nil {
+		t.Fatalf("NormalizeFile() error = %v", err)
+	}
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("NormalizeFile() got:\n%s\nwant:\n%s", got, want)
+	}
+	project, err := Load(&LoaderOptions{
+		FileNames:     []string{file},
+		disableDotenv: true,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if wd := project.Processes["zeta"].WorkingDir; wd != "/tmp" {
+		t.Errorf("expected the merged working dir /tmp, got %s", wd)
+	}
+}
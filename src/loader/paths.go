@@ -0,0 +1,93 @@
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+	"github.com/rs/zerolog/log"
+)
+
+// expandPath replaces a leading ~ with the user home directory. ~user paths are left as is
+func expandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		log.Warn().Err(err).Msgf("failed to expand %s", path)
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// expandHomePaths expands the leading ~ of the project and process paths
+func expandHomePaths(p *types.Project) {
+	p.LogLocation = expandPath(p.LogLocation)
+	p.CleanupPIDDir = expandPath(p.CleanupPIDDir)
+	p.SummaryFile = expandPath(p.SummaryFile)
+	p.StateDir = expandPath(p.StateDir)
+	for i := range p.Imports {
+		p.Imports[i].Path = expandPath(p.Imports[i].Path)
+	}
+	for name, proc := range p.Processes {
+		proc.LogLocation = expandPath(proc.LogLocation)
+		proc.StdoutLogLocation = expandPath(proc.StdoutLogLocation)
+		proc.StderrLogLocation = expandPath(proc.StderrLogLocation)
+		proc.WorkingDir = expandPath(proc.WorkingDir)
+		proc.StdinFile = expandPath(proc.StdinFile)
+		proc.CoreDumpDir = expandPath(proc.CoreDumpDir)
+		proc.OutputFIFO = expandPath(proc.OutputFIFO)
+		for i := range proc.WatchPaths {
+			proc.WatchPaths[i] = expandPath(proc.WatchPaths[i])
+		}
+		p.Processes[name] = proc
+	}
+}
+
+// stateDirHashLen is the length of the config files hash that names the project state directory
+const stateDirHashLen = 16
+
+const defaultLogFileName = "process-compose.log"
+
+// applyStateDir sets the default project state directory, $XDG_STATE_HOME/process-compose/<hash of the
+// config files paths>, and defaults the unset project log into it. A relative PID directory is created in it, the
+// PID directory isn't defaulted as the processes recorded in it are terminated on startup
+func applyStateDir(opts *LoaderOptions) mutatorFunc {
+	return func(p *types.Project) {
+		if p.StateDir == "" {
+			name := hashFileNames(opts.FileNames)
+			if opts.inlineConfig != nil {
+				// the inline config has no file, its state dir is keyed on its content
+				name = hashConfigContent(opts.inlineConfig)
+			}
+			p.StateDir = filepath.Join(opts.getStateHome(), name)
+			p.IsDefaultStateDir = true
+		}
+		if p.CleanupPIDDir != "" && !filepath.IsAbs(p.CleanupPIDDir) {
+			p.CleanupPIDDir = filepath.Join(p.StateDir, p.CleanupPIDDir)
+		}
+		if p.LogLocation == "" {
+			p.LogLocation = filepath.Join(p.StateDir, defaultLogFileName)
+		}
+	}
+}
+
+func hashFileNames(fileNames []string) string {
+	hash := sha256.New()
+	for _, file := range fileNames {
+		if absPath, err := filepath.Abs(file); err == nil && file != "-" {
+			file = absPath
+		}
+		hash.Write([]byte(file + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:stateDirHashLen]
+}
+
+func hashConfigContent(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:stateDirHashLen]
+}
@@ -0,0 +1,122 @@
+package loader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/f1bonacc1/process-compose/src/types"
+)
+
+func Test_expandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/logs/app.log", filepath.Join(home, "logs", "app.log")},
+		{"~user/logs", "~user/logs"},
+		{"./~/logs", "./~/logs"},
+		{"/var/log", "/var/log"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := expandPath(tt.path); got != tt.want {
+			t.Errorf("expandPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func Test_expandHomePaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	p := &types.Project{
+		LogLocation: "~/pc.log",
+		Processes: types.Processes{
+			"proc": {
+				LogLocation: "~/proc.log",
+				WorkingDir:  "~/work",
+				StdinFile:   "input.txt",
+			},
+		},
+	}
+	expandHomePaths(p)
+	if want := filepath.Join(home, "pc.log"); p.LogLocation != want {
+		t.Errorf("expected project log location %s, got %s", want, p.LogLocation)
+	}
+	proc := p.Processes["proc"]
+	if want := filepath.Join(home, "proc.log"); proc.LogLocation != want {
+		t.Errorf("expected process log location %s, got %s", want, proc.LogLocation)
+	}
+	if want := filepath.Join(home, "work"); proc.WorkingDir != want {
+		t.Errorf("expected working dir %s, got %s", want, proc.WorkingDir)
+	}
+	if proc.StdinFile != "input.txt" {
+		t.Errorf("expected the relative stdin file to be left as is, got %s", proc.StdinFile)
+	}
+}
+
+func Test_applyStateDir(t *testing.T) {
+	stateHome := t.TempDir()
+	opts := &LoaderOptions{stateHome: stateHome, FileNames: []string{"process-compose.yaml"}}
+	p := &types.Project{}
+	applyStateDir(opts)(p)
+	if filepath.Dir(p.StateDir) != stateHome || len(filepath.Base(p.StateDir)) != stateDirHashLen {
+		t.Errorf("expected a state dir in %s named by the config hash, got %s", stateHome, p.StateDir)
+	}
+	if p.CleanupPIDDir != "" {
+		t.Errorf("expected no PID dir by default, got %s", p.CleanupPIDDir)
+	}
+	if want := filepath.Join(p.StateDir, defaultLogFileName); p.LogLocation != want {
+		t.Errorf("expected the default log location %s, got %s", want, p.LogLocation)
+	}
+	if p.SummaryFile != "" {
+		t.Errorf("expected no summary file by default, got %s", p.SummaryFile)
+	}
+
+	other := &types.Project{}
+	applyStateDir(&LoaderOptions{stateHome: stateHome, FileNames: []string{"other.yaml"}})(other)
+	if other.StateDir == p.StateDir {
+		t.Errorf("expected different state dirs for different config files, got %s", other.StateDir)
+	}
+
+	inline := &types.Project{}
+	applyStateDir(&LoaderOptions{stateHome: stateHome, inlineConfig: []byte("processes: {}")})(inline)
+	if want := filepath.Join(stateHome, hashConfigContent([]byte("processes: {}"))); inline.StateDir != want {
+		t.Errorf("expected the inline config state dir %s named by the content hash, got %s", want, inline.StateDir)
+	}
+	otherInline := &types.Project{}
+	applyStateDir(&LoaderOptions{stateHome: stateHome, inlineConfig: []byte("processes: {other: {}}")})(otherInline)
+	if otherInline.StateDir == inline.StateDir {
+		t.Errorf("expected different state dirs for different inline configs, got %s", otherInline.StateDir)
+	}
+
+	customDir := t.TempDir()
+	custom := &types.Project{
+		StateDir:      customDir,
+		SummaryFile:   "summary.json",
+		CleanupPIDDir: "pids",
+		LogLocation:   "pc.log",
+	}
+	applyStateDir(opts)(custom)
+	if custom.StateDir != customDir {
+		t.Errorf("expected state dir %s, got %s", customDir, custom.StateDir)
+	}
+	if want := filepath.Join(customDir, "pids"); custom.CleanupPIDDir != want {
+		t.Errorf("expected the relative PID dir in the state dir %s, got %s", want, custom.CleanupPIDDir)
+	}
+	if custom.SummaryFile != "summary.json" || custom.LogLocation != "pc.log" {
+		t.Errorf("expected the configured relative paths to be left as is, got %s and %s",
+			custom.SummaryFile, custom.LogLocation)
+	}
+
+	pidDir := t.TempDir()
+	absolute := &types.Project{CleanupPIDDir: pidDir}
+	applyStateDir(opts)(absolute)
+	if absolute.CleanupPIDDir != pidDir {
+		t.Errorf("expected the absolute PID dir to be left as is, got %s", absolute.CleanupPIDDir)
+	}
+}
@@ -1,17 +1,41 @@
 package loader
 
 import (
+	"errors"
 	"fmt"
+	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/health"
+	"github.com/f1bonacc1/process-compose/src/pclog"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
+	"syscall"
 )
 
 type validatorFunc func(p *types.Project) error
 
+// ValidationError is a semantic configuration error with the location of the offending definition
+type ValidationError struct {
+	Location types.Location
+	Message  string
+}
+
+func newValidationError(location types.Location, message string) *ValidationError {
+	return &ValidationError{Location: location, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	if e.Location.File == "" {
+		return e.Message
+	}
+	return e.Location.String() + ": " + e.Message
+}
+
 func validate(p *types.Project, v ...validatorFunc) error {
 	for _, f := range v {
 		if err := f(p); err != nil {
@@ -37,6 +61,27 @@ func validateLogLevel(p *types.Project) error {
 	return nil
 }
 
+// validateLogFormat also applies the project log format to the unified log
+func validateLogFormat(p *types.Project) error {
+	switch p.LogFormat {
+	case "":
+		return nil
+	case types.LogFormatText, types.LogFormatJSON:
+	default:
+		errStr := fmt.Sprintf("unknown log format '%s'", p.LogFormat)
+		if p.IsStrict {
+			return errors.New(errStr)
+		}
+		log.Warn().Msgf("%s, defaulting to '%s'", errStr, types.LogFormatText)
+		p.LogFormat = types.LogFormatText
+	}
+	if p.LoggerConfig == nil {
+		p.LoggerConfig = &types.LoggerConfig{}
+	}
+	p.LoggerConfig.DisableJSON = p.LogFormat == types.LogFormatText
+	return nil
+}
+
 func validateProcessConfig(p *types.Project) error {
 	for key, proc := range p.Processes {
 		if len(proc.Extensions) == 0 {
@@ -48,7 +93,7 @@ func validateProcessConfig(p *types.Project) error {
 			}
 			errStr := fmt.Sprintf("unknown key '%s' found in process '%s'", extKey, key)
 			if p.IsStrict {
-				return fmt.Errorf(errStr)
+				return newValidationError(proc.Location, errStr)
 			}
 			log.Error().Msgf(errStr)
 		}
@@ -56,6 +101,95 @@ func validateProcessConfig(p *types.Project) error {
 	return nil
 }
 
+func validateOutputBuffering(p *types.Project) error {
+	for name, proc := range p.Processes {
+		switch proc.OutputBuffering {
+		case "", types.OutputBufferingLine, types.OutputBufferingBlock:
+			continue
+		}
+		errStr := fmt.Sprintf("unknown output buffering '%s' in process '%s'", proc.OutputBuffering, name)
+		if p.IsStrict {
+			return newValidationError(proc.Location, errStr)
+		}
+		log.Warn().Msgf("%s, defaulting to '%s'", errStr, types.OutputBufferingLine)
+		proc.OutputBuffering = types.OutputBufferingLine
+		p.Processes[name] = proc
+	}
+	return nil
+}
+
+func validateKillSignal(p *types.Project) error {
+	for name, proc := range p.Processes {
+		if proc.ShutDownParams.KillSignal == "" {
+			continue
+		}
+		if _, err := command.ParseSignal(proc.ShutDownParams.KillSignal); err != nil {
+			errStr := fmt.Sprintf("invalid kill signal '%s' in process '%s'", proc.ShutDownParams.KillSignal, name)
+			if p.IsStrict {
+				return newValidationError(proc.Location, errStr)
+			}
+			log.Warn().Msgf("%s, defaulting to 'SIGKILL'", errStr)
+			proc.ShutDownParams.KillSignal = ""
+			p.Processes[name] = proc
+		}
+	}
+	return nil
+}
+
+func validateColor(p *types.Project) error {
+	for name, proc := range p.Processes {
+		if proc.Color == "" {
+			continue
+		}
+		if _, err := pclog.ParseColor(proc.Color); err != nil {
+			errStr := fmt.Sprintf("invalid color '%s' in process '%s'", proc.Color, name)
+			if p.IsStrict {
+				return newValidationError(proc.Location, errStr)
+			}
+			log.Warn().Msgf("%s, using the default color", errStr)
+			proc.Color = ""
+			p.Processes[name] = proc
+		}
+	}
+	return nil
+}
+
+func validateReloadSignal(p *types.Project) error {
+	for name, proc := range p.Processes {
+		if proc.ReloadSignal == "" {
+			continue
+		}
+		if _, err := command.ParseSignal(proc.ReloadSignal); err != nil {
+			errStr := fmt.Sprintf("invalid reload signal '%s' in process '%s'", proc.ReloadSignal, name)
+			if p.IsStrict {
+				return newValidationError(proc.Location, errStr)
+			}
+			log.Warn().Msgf("%s, defaulting to 'SIGHUP'", errStr)
+			proc.ReloadSignal = ""
+			p.Processes[name] = proc
+		}
+	}
+	return nil
+}
+
+func validateForwardSignals(p *types.Project) error {
+	if runtime.GOOS == "windows" {
+		// not supported, the project runner warns about it
+		return nil
+	}
+	for _, name := range p.ForwardSignals {
+		sig, err := command.ParseSignal(name)
+		if err != nil {
+			return fmt.Errorf("invalid forward signal '%s': %w", name, err)
+		}
+		switch syscall.Signal(sig) {
+		case syscall.SIGTERM, syscall.SIGINT, syscall.SIGKILL:
+			return fmt.Errorf("signal '%s' can't be forwarded to the processes", name)
+		}
+	}
+	return nil
+}
+
 func validateShellConfig(p *types.Project) error {
 	_, err := exec.LookPath(p.ShellConfig.ShellCommand)
 	if err != nil {
@@ -70,7 +204,7 @@ func validatePlatformCompatibility(p *types.Project) error {
 	}
 	for name, proc := range p.Processes {
 		if proc.IsTty {
-			return fmt.Errorf("PTY for process '%s' is not yet supported on Windows", name)
+			return newValidationError(proc.Location, fmt.Sprintf("PTY for process '%s' is not yet supported on Windows", name))
 		}
 	}
 	return nil
@@ -82,7 +216,7 @@ func validateNoCircularDependencies(p *types.Project) error {
 	for name := range p.Processes {
 		if !visited[name] {
 			if isCyclicHelper(p, name, visited, stack) {
-				return fmt.Errorf("circular dependency found in '%s'", name)
+				return newValidationError(p.Processes[name].Location, fmt.Sprintf("circular dependency found in '%s'", name))
 			}
 		}
 	}
@@ -114,14 +248,27 @@ func isCyclicHelper(p *types.Project, procName string, visited map[string]bool,
 	return false
 }
 
+// lookupDependency returns a dependency by name. A process with multiple replicas is returned by its first found replica
+func lookupDependency(p *types.Project, name string) (types.ProcessConfig, bool) {
+	if proc, ok := p.Processes[name]; ok {
+		return proc, true
+	}
+	for _, proc := range p.Processes {
+		if proc.Name == name {
+			return proc, true
+		}
+	}
+	return types.ProcessConfig{}, false
+}
+
 func validateHealthDependencyHasHealthCheck(p *types.Project) error {
 	for procName, proc := range p.Processes {
 		for depName, dep := range proc.DependsOn {
-			depProc, ok := p.Processes[depName]
+			depProc, ok := lookupDependency(p, depName)
 			if !ok {
 				errStr := fmt.Sprintf("dependency process '%s' in process '%s' is not defined", depName, procName)
 				if p.IsStrict {
-					return fmt.Errorf(errStr)
+					return newValidationError(proc.Location, errStr)
 				}
 				log.Error().Msg(errStr)
 				continue
@@ -129,26 +276,130 @@ func validateHealthDependencyHasHealthCheck(p *types.Project) error {
 			if dep.Condition == types.ProcessConditionHealthy && depProc.ReadinessProbe == nil && depProc.LivenessProbe == nil {
 				errStr := fmt.Sprintf("health dependency defined in '%s' but no health check exists in '%s'", procName, depName)
 				if p.IsStrict {
-					return fmt.Errorf(errStr)
+					return newValidationError(proc.Location, errStr)
 				}
 				log.Error().Msg(errStr)
 			}
 			if dep.Condition == types.ProcessConditionLogReady && depProc.ReadyLogLine == "" {
 				errStr := fmt.Sprintf("log ready dependency defined in '%s' but no ready log line exists in '%s'", procName, depName)
 				log.Error().Msg(errStr)
-				return fmt.Errorf(errStr)
+				return newValidationError(proc.Location, errStr)
 			}
 		}
 	}
 	return nil
 }
 
+func validateEnvFromProcess(p *types.Project) error {
+	for procName, proc := range p.Processes {
+		if proc.EnvFromProcess == "" {
+			continue
+		}
+		dep, ok := proc.DependsOn[proc.EnvFromProcess]
+		if !ok || (dep.Condition != types.ProcessConditionCompleted &&
+			dep.Condition != types.ProcessConditionCompletedSuccessfully) {
+			errStr := fmt.Sprintf("'env_from_process' in '%s' requires a '%s' or '%s' dependency on '%s'",
+				procName, types.ProcessConditionCompleted, types.ProcessConditionCompletedSuccessfully, proc.EnvFromProcess)
+			log.Error().Msg(errStr)
+			return newValidationError(proc.Location, errStr)
+		}
+	}
+	return nil
+}
+
+// validateEnvSchema checks the env_schema variables are defined with the declared type, and applies their defaults.
+// The variables undefined at load time might be added by the bootstrap_command, they are checked once it runs
+func validateEnvSchema(p *types.Project) error {
+	for procName, proc := range p.Processes {
+		if len(proc.EnvSchema) == 0 {
+			continue
+		}
+		if err := proc.CheckEnvSchema(p.Environment, p.BootstrapCommand != ""); err != nil {
+			return newValidationError(proc.Location, err.Error())
+		}
+		p.Processes[procName] = proc
+	}
+	return nil
+}
+
+func validateInputFrom(p *types.Project) error {
+	for procName, proc := range p.Processes {
+		if proc.InputFrom == "" {
+			continue
+		}
+		errStr := ""
+		switch {
+		case proc.InputFrom == proc.Name:
+			errStr = fmt.Sprintf("process '%s' can't read its own output with 'input_from'", procName)
+		case !isProcessDefined(p, proc.InputFrom):
+			errStr = fmt.Sprintf("'input_from' process '%s' of process '%s' is not defined", proc.InputFrom, procName)
+		case proc.StdinFile != "" || proc.KeepStdinOpen:
+			errStr = fmt.Sprintf("'input_from' can't be used with 'stdin_file' or 'keep_stdin_open' in process '%s'", procName)
+		default:
+			continue
+		}
+		log.Error().Msg(errStr)
+		return newValidationError(proc.Location, errStr)
+	}
+	return nil
+}
+
+func validateStdinData(p *types.Project) error {
+	for procName, proc := range p.Processes {
+		if proc.StdinData == "" || (proc.StdinFile == "" && !proc.KeepStdinOpen && proc.InputFrom == "") {
+			continue
+		}
+		errStr := fmt.Sprintf("'stdin_data' can't be used with 'stdin_file', 'keep_stdin_open' or 'input_from' in process '%s'", procName)
+		log.Error().Msg(errStr)
+		return newValidationError(proc.Location, errStr)
+	}
+	return nil
+}
+
+func validateLogLineTemplate(p *types.Project) error {
+	for procName, proc := range p.Processes {
+		if proc.LogLineTemplate == "" {
+			continue
+		}
+		if _, err := pclog.NewLineTemplate(proc.LogLineTemplate, proc.LogTimestampFormat); err != nil {
+			errStr := fmt.Sprintf("invalid 'log_line_template' in process '%s': %v", procName, err)
+			log.Error().Msg(errStr)
+			return newValidationError(proc.Location, errStr)
+		}
+	}
+	return nil
+}
+
+func validateStartupBarrier(p *types.Project) error {
+	for _, name := range p.StartupBarrier {
+		if isProcessDefined(p, p.ResolveProcessName(name)) {
+			continue
+		}
+		errStr := fmt.Sprintf("startup barrier process '%s' is not defined", name)
+		if p.IsStrict {
+			return errors.New(errStr)
+		}
+		log.Error().Msg(errStr)
+	}
+	return nil
+}
+
+// isProcessDefined checks the process names, as replicas are named after their process
+func isProcessDefined(p *types.Project, name string) bool {
+	for _, proc := range p.Processes {
+		if proc.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func validateNoIncompatibleHealthChecks(p *types.Project) error {
 	for procName, proc := range p.Processes {
 		if proc.ReadinessProbe != nil && proc.ReadyLogLine != "" {
 			errStr := fmt.Sprintf("'ready_log_line' and readiness probe defined in '%s' are incompatible", procName)
 			log.Error().Msg(errStr)
-			return fmt.Errorf(errStr)
+			return newValidationError(proc.Location, errStr)
 		}
 	}
 	return nil
@@ -157,11 +408,11 @@ func validateNoIncompatibleHealthChecks(p *types.Project) error {
 func validateDependencyIsEnabled(p *types.Project) error {
 	for procName, proc := range p.Processes {
 		for depName := range proc.DependsOn {
-			depProc, ok := p.Processes[depName]
+			depProc, ok := lookupDependency(p, depName)
 			if !ok {
 				errStr := fmt.Sprintf("dependency process '%s' in process '%s' is not defined", depName, procName)
 				if p.IsStrict {
-					return fmt.Errorf(errStr)
+					return newValidationError(proc.Location, errStr)
 				}
 				log.Error().Msg(errStr)
 				continue
@@ -169,7 +420,7 @@ func validateDependencyIsEnabled(p *types.Project) error {
 			if depProc.Disabled {
 				errStr := fmt.Sprintf("dependency process '%s' in process '%s' is disabled", depName, procName)
 				if p.IsStrict {
-					return fmt.Errorf(errStr)
+					return newValidationError(proc.Location, errStr)
 				}
 				log.Error().Msg(errStr)
 			}
@@ -177,3 +428,124 @@ func validateDependencyIsEnabled(p *types.Project) error {
 	}
 	return nil
 }
+
+// validateUnreachableProcesses warns about the enabled processes that are only needed by disabled processes.
+// They run for nothing, which usually means a stale definition
+func validateUnreachableProcesses(p *types.Project) error {
+	for _, name := range findUnreachableProcesses(p) {
+		proc := p.Processes[name]
+		log.Warn().Msg(newValidationError(proc.Location,
+			fmt.Sprintf("process '%s' is unreachable, all the processes that depend on it are disabled", name)).Error())
+	}
+	return nil
+}
+
+// validateUnrenderedTemplates warns about the replicated processes that use the process metadata template variables
+// without global or local vars: their templates aren't rendered, so each replica gets the same literal value
+func validateUnrenderedTemplates(p *types.Project) error {
+	for _, name := range findUnrenderedTemplates(p) {
+		proc := p.Processes[name]
+		log.Warn().Msg(newValidationError(proc.Location,
+			fmt.Sprintf("process '%s' uses the process metadata template variables, but its templates aren't rendered without vars", proc.Name)).Error())
+	}
+	return nil
+}
+
+var metadataTemplatePattern = regexp.MustCompile(`{{[^}]*\.(ReplicaIndex|ProcessName|Namespace|RunID)\b`)
+
+// findUnrenderedTemplates returns the sorted names of the first replicas of the processes that reference the
+// process metadata template variables, but have no global or local vars to render their templates
+func findUnrenderedTemplates(p *types.Project) []string {
+	if len(p.Vars) > 0 {
+		return nil
+	}
+	var names []string
+	for name, proc := range p.Processes {
+		if proc.Replicas < 2 || proc.ReplicaNum > 0 || len(proc.Vars) > 0 {
+			continue
+		}
+		fields := []string{proc.Command, proc.WorkingDir, proc.LogLocation, proc.StdoutLogLocation, proc.StderrLogLocation}
+		for _, probe := range []*health.Probe{proc.ReadinessProbe, proc.LivenessProbe} {
+			if probe == nil {
+				continue
+			}
+			if probe.Exec != nil {
+				fields = append(fields, probe.Exec.Command)
+			}
+			if probe.HttpGet != nil {
+				fields = append(fields, probe.HttpGet.Path, probe.HttpGet.Host, probe.HttpGet.Port)
+			}
+			if probe.TcpSocket != nil {
+				fields = append(fields, probe.TcpSocket.Host, probe.TcpSocket.Port)
+			}
+		}
+		for _, field := range fields {
+			if metadataTemplatePattern.MatchString(field) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveDependencyReplicas returns the names of the processes a dependency refers to: a single replica,
+// or all the replicas of a process
+func resolveDependencyReplicas(p *types.Project, name string) []string {
+	if _, ok := p.Processes[name]; ok {
+		return []string{name}
+	}
+	var replicas []string
+	for replicaName, proc := range p.Processes {
+		if proc.Name == name {
+			replicas = append(replicas, replicaName)
+		}
+	}
+	return replicas
+}
+
+// findUnreachableProcesses returns the sorted names of the enabled processes that have dependents,
+// but no enabled dependent that is reachable itself
+func findUnreachableProcesses(p *types.Project) []string {
+	dependents := map[string][]string{}
+	disabled := map[string]bool{}
+	for name, proc := range p.Processes {
+		disabled[name] = proc.Disabled
+		for depName := range proc.DependsOn {
+			for _, dep := range resolveDependencyReplicas(p, depName) {
+				dependents[dep] = append(dependents[dep], name)
+			}
+		}
+	}
+	reachable := map[string]bool{}
+	visiting := map[string]bool{}
+	var isReachable func(name string) bool
+	isReachable = func(name string) bool {
+		if result, ok := reachable[name]; ok {
+			return result
+		}
+		if disabled[name] || visiting[name] {
+			return false
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		result := len(dependents[name]) == 0
+		for _, dependent := range dependents[name] {
+			if isReachable(dependent) {
+				result = true
+				break
+			}
+		}
+		reachable[name] = result
+		return result
+	}
+	var unreachable []string
+	for name := range disabled {
+		if !disabled[name] && !isReachable(name) {
+			unreachable = append(unreachable, name)
+		}
+	}
+	sort.Strings(unreachable)
+	return unreachable
+}
@@ -2,7 +2,10 @@ package loader
 
 import (
 	"github.com/f1bonacc1/process-compose/src/command"
+	"github.com/f1bonacc1/process-compose/src/health"
 	"github.com/f1bonacc1/process-compose/src/types"
+	"reflect"
+	"runtime"
 	"testing"
 )
 
@@ -186,3 +189,357 @@ func Test_validateShellConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateOutputBuffering(t *testing.T) {
+	type args struct {
+		p *types.Project
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			args: args{
+				p: &types.Project{
+					Processes: types.Processes{
+						"test": {
+							Name:            "test",
+							OutputBuffering: types.OutputBufferingBlock,
+						},
+						"test2": {
+							Name: "test2",
+						},
+					},
+					IsStrict: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid non strict",
+			args: args{
+				p: &types.Project{
+					Processes: types.Processes{
+						"test": {
+							Name:            "test",
+							OutputBuffering: "invalid",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid strict",
+			args: args{
+				p: &types.Project{
+					Processes: types.Processes{
+						"test": {
+							Name:            "test",
+							OutputBuffering: "invalid",
+						},
+					},
+					IsStrict: true,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateOutputBuffering(tt.args.p); (err != nil) != tt.wantErr {
+				t.Errorf("validateOutputBuffering() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateKillSignal(t *testing.T) {
+	tests := []struct {
+		name       string
+		killSignal string
+		isStrict   bool
+		wantErr    bool
+		wantSignal string
+	}{
+		{name: "Name", killSignal: "SIGQUIT", isStrict: true, wantSignal: "SIGQUIT"},
+		{name: "Short name", killSignal: "kill", isStrict: true, wantSignal: "kill"},
+		{name: "Number", killSignal: "9", isStrict: true, wantSignal: "9"},
+		{name: "Invalid strict", killSignal: "SIGNOPE", isStrict: true, wantErr: true, wantSignal: "SIGNOPE"},
+		{name: "Invalid non strict", killSignal: "SIGNOPE", wantSignal: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Processes: types.Processes{
+					"test": {
+						Name:           "test",
+						ShutDownParams: types.ShutDownParams{KillSignal: tt.killSignal},
+					},
+				},
+				IsStrict: tt.isStrict,
+			}
+			if err := validateKillSignal(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateKillSignal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := p.Processes["test"].ShutDownParams.KillSignal; got != tt.wantSignal {
+				t.Errorf("validateKillSignal() kill signal = %s, want %s", got, tt.wantSignal)
+			}
+		})
+	}
+}
+
+func Test_validateColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		color     string
+		isStrict  bool
+		wantErr   bool
+		wantColor string
+	}{
+		{name: "Name", color: "hi-blue", isStrict: true, wantColor: "hi-blue"},
+		{name: "Code", color: "1;34", isStrict: true, wantColor: "1;34"},
+		{name: "Invalid strict", color: "purple", isStrict: true, wantErr: true, wantColor: "purple"},
+		{name: "Invalid non strict", color: "purple", wantColor: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Processes: types.Processes{
+					"test": {Name: "test", Color: tt.color},
+				},
+				IsStrict: tt.isStrict,
+			}
+			if err := validateColor(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := p.Processes["test"].Color; got != tt.wantColor {
+				t.Errorf("validateColor() color = %s, want %s", got, tt.wantColor)
+			}
+		})
+	}
+}
+
+func Test_validateEnvFromProcess(t *testing.T) {
+	tests := []struct {
+		name      string
+		dependsOn types.DependsOnConfig
+		wantErr   bool
+	}{
+		{name: "Completed", dependsOn: types.DependsOnConfig{"setup": {Condition: types.ProcessConditionCompleted}}},
+		{name: "Completed successfully", dependsOn: types.DependsOnConfig{"setup": {Condition: types.ProcessConditionCompletedSuccessfully}}},
+		{name: "Started", dependsOn: types.DependsOnConfig{"setup": {Condition: types.ProcessConditionStarted}}, wantErr: true},
+		{name: "No dependency", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Processes: types.Processes{
+					"setup": {Name: "setup"},
+					"test": {
+						Name:           "test",
+						EnvFromProcess: "setup",
+						DependsOn:      tt.dependsOn,
+					},
+				},
+			}
+			if err := validateEnvFromProcess(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateEnvFromProcess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateStdinData(t *testing.T) {
+	tests := []struct {
+		name    string
+		proc    types.ProcessConfig
+		wantErr bool
+	}{
+		{name: "Data", proc: types.ProcessConfig{StdinData: "yes\n"}},
+		{name: "No data", proc: types.ProcessConfig{StdinFile: "input.txt"}},
+		{name: "With stdin_file", proc: types.ProcessConfig{StdinData: "yes\n", StdinFile: "input.txt"}, wantErr: true},
+		{name: "With keep_stdin_open", proc: types.ProcessConfig{StdinData: "yes\n", KeepStdinOpen: true}, wantErr: true},
+		{name: "With input_from", proc: types.ProcessConfig{StdinData: "yes\n", InputFrom: "producer"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.proc.Name = "seed"
+			p := &types.Project{
+				Processes: types.Processes{"seed": tt.proc},
+			}
+			if err := validateStdinData(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateStdinData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateForwardSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("forward_signals isn't supported on Windows")
+	}
+	tests := []struct {
+		name    string
+		signals []string
+		wantErr bool
+	}{
+		{name: "None"},
+		{name: "Valid", signals: []string{"SIGUSR1", "HUP", "12"}},
+		{name: "Unknown", signals: []string{"SIGFOO"}, wantErr: true},
+		{name: "Shutdown", signals: []string{"SIGUSR1", "SIGTERM"}, wantErr: true},
+		{name: "Kill", signals: []string{"9"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{ForwardSignals: tt.signals}
+			if err := validateForwardSignals(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateForwardSignals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateLogLineTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "No template"},
+		{name: "Template", template: "{{.Timestamp}} | {{.Process}} | {{.Line}}"},
+		{name: "All fields", template: `{"ts":"{{.Timestamp}}","stream":"{{.Stream}}","replica":{{.ReplicaIndex}},"msg":{{printf "%q" .Line}}}`},
+		{name: "Syntax error", template: "{{.Line", wantErr: true},
+		{name: "Unknown field", template: "{{.Level}} {{.Line}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Processes: types.Processes{"app": {Name: "app", LogLineTemplate: tt.template}},
+			}
+			if err := validateLogLineTemplate(p); (err != nil) != tt.wantErr {
+				t.Errorf("validateLogLineTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateEnvSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       types.Environment
+		schema    map[string]types.EnvVarSpec
+		wantErr   bool
+		wantValue string
+		// the undefined variables are checked once the bootstrap command runs
+		bootstrapCommand string
+	}{
+		{name: "Int", env: types.Environment{"PC_TEST_PORT=8080"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "int"}}, wantValue: "8080"},
+		{name: "Invalid int", env: types.Environment{"PC_TEST_PORT=http"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "int"}}, wantErr: true},
+		{name: "Url", env: types.Environment{"PC_TEST_PORT=http://localhost:80"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "url"}}, wantValue: "http://localhost:80"},
+		{name: "Invalid url", env: types.Environment{"PC_TEST_PORT=localhost"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "url"}}, wantErr: true},
+		{name: "Duration", env: types.Environment{"PC_TEST_PORT=5s"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "duration"}}, wantValue: "5s"},
+		{name: "Default", schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "int", Default: "80", Required: true}}, wantValue: "80"},
+		{name: "Invalid default", schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "bool", Default: "80"}}, wantErr: true},
+		{name: "Missing required", schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Required: true}}, wantErr: true},
+		{name: "Missing optional", schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "int"}}},
+		{name: "Unknown type", env: types.Environment{"PC_TEST_PORT=80"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "float"}}, wantErr: true},
+		{name: "Missing required with bootstrap", schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Required: true}}, bootstrapCommand: "./secrets.sh"},
+		{name: "Default with bootstrap", schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "int", Default: "80"}}, bootstrapCommand: "./secrets.sh"},
+		{name: "Invalid int with bootstrap", env: types.Environment{"PC_TEST_PORT=http"}, schema: map[string]types.EnvVarSpec{"PC_TEST_PORT": {Type: "int"}}, bootstrapCommand: "./secrets.sh", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{
+				Processes: types.Processes{
+					"test": {Name: "test", Environment: tt.env, EnvSchema: tt.schema},
+				},
+				BootstrapCommand: tt.bootstrapCommand,
+			}
+			err := validateEnvSchema(p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateEnvSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			value, _ := types.LookupEnv("PC_TEST_PORT", p.Processes["test"].Environment)
+			if value != tt.wantValue {
+				t.Errorf("PC_TEST_PORT = %s, want %s", value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_validateLogFormat(t *testing.T) {
+	tests := []struct {
+		name            string
+		format          string
+		isStrict        bool
+		wantErr         bool
+		wantDisableJSON bool
+	}{
+		{name: "Text", format: types.LogFormatText, wantDisableJSON: true},
+		{name: "JSON", format: types.LogFormatJSON, wantDisableJSON: false},
+		{name: "Unknown", format: "xml", wantDisableJSON: true},
+		{name: "Unknown strict", format: "xml", isStrict: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Project{LogFormat: tt.format, IsStrict: tt.isStrict}
+			if err := validateLogFormat(p); (err != nil) != tt.wantErr {
+				t.Fatalf("validateLogFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if p.LoggerConfig.DisableJSON != tt.wantDisableJSON {
+				t.Errorf("DisableJSON = %v, want %v", p.LoggerConfig.DisableJSON, tt.wantDisableJSON)
+			}
+		})
+	}
+}
+
+func Test_findUnreachableProcesses(t *testing.T) {
+	p := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"db":     {Name: "db"},
+			"cache":  {Name: "cache"},
+			"legacy": {Name: "legacy", Disabled: true, DependsOn: types.DependsOnConfig{"cache": {}, "queue": {}}},
+			"queue":  {Name: "queue", DependsOn: types.DependsOnConfig{"broker": {}}},
+			"broker": {Name: "broker"},
+			"api":    {Name: "api", DependsOn: types.DependsOnConfig{"db": {}, "cache": {}}},
+			"old":    {Name: "old", Disabled: true},
+		},
+	}
+	// cache is required by the enabled api, queue and broker only by the disabled legacy
+	want := []string{"broker", "queue"}
+	if got := findUnreachableProcesses(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("findUnreachableProcesses() = %v, want %v", got, want)
+	}
+}
+
+func Test_findUnrenderedTemplates(t *testing.T) {
+	server := "./server --port {{add 8080 .ReplicaIndex}}"
+	p := &types.Project{
+		Processes: map[string]types.ProcessConfig{
+			"server-0": {Name: "server", Command: server, Replicas: 2, ReplicaNum: 0},
+			"server-1": {Name: "server", Command: server, Replicas: 2, ReplicaNum: 1},
+			"worker-0": {Name: "worker", Command: "./worker", Replicas: 2, ReplicaNum: 0,
+				ReadinessProbe: &health.Probe{HttpGet: &health.HttpProbe{Port: "{{add 9000 .ReplicaIndex}}"}}},
+			"rendered-0": {Name: "rendered", Command: server, Replicas: 2, Vars: types.Vars{"PORT": 8080}},
+			"single":     {Name: "single", Command: "echo {{.ProcessName}}"},
+			"literal-0":  {Name: "literal", Command: "echo {{ .Values }}", Replicas: 2},
+		},
+	}
+	want := []string{"server-0", "worker-0"}
+	if got := findUnrenderedTemplates(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("findUnrenderedTemplates() = %v, want %v", got, want)
+	}
+	p.Vars = types.Vars{"PORT": 8080}
+	if got := findUnrenderedTemplates(p); len(got) != 0 {
+		t.Errorf("findUnrenderedTemplates() with global vars = %v, want none", got)
+	}
+}
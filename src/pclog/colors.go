@@ -0,0 +1,45 @@
+package pclog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var colorNames = map[string]color.Attribute{
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+}
+
+// ParseColor parses a color name (e.g. red, hi-blue) or an ANSI SGR code (e.g. 32, 1;34)
+func ParseColor(value string) ([]color.Attribute, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if attr, ok := colorNames[value]; ok {
+		return []color.Attribute{attr}, nil
+	}
+	var attrs []color.Attribute
+	for _, code := range strings.Split(value, ";") {
+		num, err := strconv.Atoi(code)
+		if err != nil || num < 0 || num > 255 {
+			return nil, fmt.Errorf("invalid color '%s'", value)
+		}
+		attrs = append(attrs, color.Attribute(num))
+	}
+	return attrs, nil
+}
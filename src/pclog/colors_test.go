@@ -0,0 +1,34 @@
+package pclog
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []color.Attribute
+		wantErr bool
+	}{
+		{value: "red", want: []color.Attribute{color.FgRed}},
+		{value: "Hi-Blue", want: []color.Attribute{color.FgHiBlue}},
+		{value: "32", want: []color.Attribute{color.FgGreen}},
+		{value: "1;34", want: []color.Attribute{color.Bold, color.FgBlue}},
+		{value: "purple", wantErr: true},
+		{value: "1;", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseColor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package pclog
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// LogLine is the data of a process output line, as seen by the log_line_template
+type LogLine struct {
+	Timestamp    any
+	Process      string
+	Line         string
+	Stream       string
+	ReplicaIndex int
+}
+
+// LineTemplate renders the process output lines with a Go template, e.g. {{.Timestamp}} | {{.Process}} | {{.Line}}
+type LineTemplate struct {
+	tmpl       *template.Template
+	timeFormat string
+}
+
+// NewLineTemplate parses the template. The timestamp is rendered in the given format, see WithTimestampFormat
+func NewLineTemplate(text, timestampFormat string) (*LineTemplate, error) {
+	tmpl, err := template.New("log_line_template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	t := &LineTemplate{
+		tmpl:       tmpl,
+		timeFormat: resolveTimestampFormat(timestampFormat),
+	}
+	// the fields are only resolved on execution, catch the undefined ones early
+	if err = t.tmpl.Execute(&strings.Builder{}, LogLine{}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render renders an output line of the process. A line that fails to render is returned with the error
+func (t *LineTemplate) Render(message string, meta LogMetadata, isErr bool, ts time.Time) string {
+	line := LogLine{
+		Timestamp:    formatTimestamp(t.timeFormat, ts),
+		Process:      meta.Process,
+		Line:         message,
+		Stream:       "stdout",
+		ReplicaIndex: meta.Replica,
+	}
+	if isErr {
+		line.Stream = "stderr"
+	}
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, line); err != nil {
+		return fmt.Sprintf("%s (log_line_template: %v)", message, err)
+	}
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package pclog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"regexp"
+	"time"
+)
+
+// LogQuery filters log lines by a regex pattern and a time range
+type LogQuery struct {
+	Pattern *regexp.Regexp
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	// TimestampFormat is the format of the log file timestamps, see WithTimestampFormat. RFC3339 if empty
+	TimestampFormat string
+}
+
+// Match reports whether a line written at ts satisfies the query.
+// A zero ts only matches queries without a time range
+func (q *LogQuery) Match(line string, ts time.Time) bool {
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		if ts.IsZero() {
+			return false
+		}
+		if !q.Since.IsZero() && ts.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && ts.After(q.Until) {
+			return false
+		}
+	}
+	return q.Pattern == nil || q.Pattern.MatchString(line)
+}
+
+func (q *LogQuery) limit(matches []string) []string {
+	if q.Limit > 0 && len(matches) > q.Limit {
+		return matches[len(matches)-q.Limit:]
+	}
+	return matches
+}
+
+// SearchLogFile returns up to limit of the most recent lines in the log file that match the query.
+// JSON formatted lines are matched by their message and timestamp.
+func SearchLogFile(filePath string, query *LogQuery) ([]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	matches := []string{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line, ts := parseLogLine(scanner.Text(), query.TimestampFormat)
+		if query.Match(line, ts) {
+			matches = append(matches, line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return query.limit(matches), nil
+}
+
+// parseLogLine extracts the message and the timestamp, written in the given format, from a JSON log line.
+// Other lines are returned as is.
+func parseLogLine(raw, timestampFormat string) (string, time.Time) {
+	var fields struct {
+		Message string          `json:"message"`
+		Line    string          `json:"line"`
+		Time    json.RawMessage `json:"time"`
+		Ts      json.RawMessage `json:"ts"`
+	}
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return raw, time.Time{}
+	}
+	line := fields.Message
+	if fields.Line != "" {
+		line = fields.Line
+	}
+	rawTs := fields.Time
+	if len(fields.Ts) > 0 {
+		rawTs = fields.Ts
+	}
+	return line, parseTimestamp(timestampFormat, rawTs)
+}
+
+// parseTimestamp parses a numeric timestamp as unix seconds, and a string one with the format layout.
+// Returns the zero time if the timestamp can't be parsed
+func parseTimestamp(format string, raw json.RawMessage) time.Time {
+	var seconds json.Number
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		if sec, err := seconds.Int64(); err == nil {
+			return time.Unix(sec, 0)
+		}
+		if sec, err := seconds.Float64(); err == nil {
+			return time.UnixMilli(int64(sec * 1000))
+		}
+		return time.Time{}
+	}
+	var tsStr string
+	if err := json.Unmarshal(raw, &tsStr); err != nil {
+		return time.Time{}
+	}
+	layout := resolveTimestampFormat(format)
+	if layout == "" || layout == TimestampFormatUnix {
+		layout = time.RFC3339
+	}
+	ts, err := time.Parse(layout, tsStr)
+	if err != nil && layout != time.RFC3339 {
+		// written before the format was changed
+		ts, _ = time.Parse(time.RFC3339, tsStr)
+	}
+	return ts
+}
@@ -0,0 +1,73 @@
+package pclog
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseLogLine(t *testing.T) {
+	ts := time.Date(2024, 5, 28, 3, 0, 12, 0, time.UTC)
+	tests := []struct {
+		name     string
+		raw      string
+		format   string
+		wantLine string
+		wantTime time.Time
+	}{
+		{
+			name:     "rfc3339 default",
+			raw:      `{"level":"info","message":"started","time":"2024-05-28T03:00:12Z"}`,
+			wantLine: "started",
+			wantTime: ts,
+		},
+		{
+			name:     "aggregate ts",
+			raw:      `{"ts":"2024-05-28T03:00:12Z","process":"db","stream":"stdout","line":"started"}`,
+			format:   "rfc3339",
+			wantLine: "started",
+			wantTime: ts,
+		},
+		{
+			name:     "unix",
+			raw:      `{"ts":1716865212,"process":"db","stream":"stdout","line":"started"}`,
+			format:   TimestampFormatUnix,
+			wantLine: "started",
+			wantTime: time.Unix(1716865212, 0),
+		},
+		{
+			name:     "custom layout",
+			raw:      `{"message":"started","time":"2024/05/28 03:00:12"}`,
+			format:   "2006/01/02 15:04:05",
+			wantLine: "started",
+			wantTime: ts,
+		},
+		{
+			name:     "written before the format change",
+			raw:      `{"message":"started","time":"2024-05-28T03:00:12Z"}`,
+			format:   "2006/01/02 15:04:05",
+			wantLine: "started",
+			wantTime: ts,
+		},
+		{
+			name:     "no time",
+			raw:      `{"message":"started"}`,
+			wantLine: "started",
+		},
+		{
+			name:     "plain text",
+			raw:      "started",
+			wantLine: "started",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, got := parseLogLine(tt.raw, tt.format)
+			if line != tt.wantLine {
+				t.Errorf("parseLogLine() line = %q, want %q", line, tt.wantLine)
+			}
+			if !got.Equal(tt.wantTime) {
+				t.Errorf("parseLogLine() time = %v, want %v", got, tt.wantTime)
+			}
+		})
+	}
+}
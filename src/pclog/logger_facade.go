@@ -2,6 +2,7 @@ package pclog
 
 import (
 	"bufio"
+	"encoding/json"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -9,10 +10,14 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+const TimestampFormatUnix = "unix"
+
 type PCLog struct {
 	logger        zerolog.Logger
 	writer        *bufio.Writer
@@ -23,13 +28,30 @@ type PCLog struct {
 	isClosed      atomic.Bool
 	noMetaData    bool
 	flushEachLine bool
+	isJSON        bool
+	isAggregate   bool
+	isAppend      bool
+	runID         string
+	timeFormat    string
+	lineTemplate  *LineTemplate
 }
 
 type logEvent struct {
 	message string
-	process string
-	replica int
+	meta    LogMetadata
 	isErr   bool
+	time    time.Time
+}
+
+// aggregateLogLine is a single line of the project (aggregate) JSON log
+type aggregateLogLine struct {
+	Timestamp any    `json:"ts"`
+	Process   string `json:"process,omitempty"`
+	RunID     string `json:"run_id,omitempty"`
+	RestartID string `json:"restart_id,omitempty"`
+	Replica   *int   `json:"replica,omitempty"`
+	Stream    string `json:"stream"`
+	Line      string `json:"line"`
 }
 
 func NewLogger() *PCLog {
@@ -40,6 +62,62 @@ func NewLogger() *PCLog {
 	return l
 }
 
+// NewAggregateLogger creates a logger for the output of all the project processes.
+// In JSON mode, each line is written as a self describing JSON object tagged with runID.
+func NewAggregateLogger(runID string) *PCLog {
+	l := NewLogger()
+	l.isAggregate = true
+	l.runID = runID
+	return l
+}
+
+// WithTimestampFormat adds a timestamp to each log line in the given format: a Go time layout,
+// or one of the aliases: unix, rfc3339 (default for the aggregate log), rfc3339nano.
+// No timestamp is added with an empty format, except to the aggregate JSON log that always has one
+func (l *PCLog) WithTimestampFormat(format string) *PCLog {
+	l.timeFormat = resolveTimestampFormat(format)
+	return l
+}
+
+func resolveTimestampFormat(format string) string {
+	switch strings.ToLower(format) {
+	case TimestampFormatUnix:
+		return TimestampFormatUnix
+	case "rfc3339":
+		return time.RFC3339
+	case "rfc3339nano":
+		return time.RFC3339Nano
+	default:
+		return format
+	}
+}
+
+// WithLineTemplate renders each log line with the template instead of the logger format
+func (l *PCLog) WithLineTemplate(tmpl *LineTemplate) *PCLog {
+	l.lineTemplate = tmpl
+	return l
+}
+
+// WithAppend keeps the existing log file content, instead of truncating it when the log is opened
+func (l *PCLog) WithAppend(isAppend bool) *PCLog {
+	l.isAppend = isAppend
+	return l
+}
+
+func (l *PCLog) formatTimestamp(t time.Time) interface{} {
+	return formatTimestamp(l.timeFormat, t)
+}
+
+func formatTimestamp(format string, t time.Time) interface{} {
+	if format == TimestampFormatUnix {
+		return t.Unix()
+	}
+	if format == "" {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(format)
+}
+
 func (l *PCLog) Open(filePath string, config *types.LoggerConfig) {
 	if l.file != nil {
 		log.Error().Msgf("log file for %s is already open", filePath)
@@ -57,7 +135,8 @@ func (l *PCLog) Open(filePath string, config *types.LoggerConfig) {
 	}
 	l.writer = bufio.NewWriter(f)
 	l.file = f
-	if config == nil || !config.DisableJSON {
+	l.isJSON = config == nil || !config.DisableJSON
+	if l.isJSON {
 		l.logger = zerolog.New(l.writer)
 	} else {
 		out := zerolog.NewConsoleWriter(
@@ -77,7 +156,7 @@ func (l *PCLog) Open(filePath string, config *types.LoggerConfig) {
 	if config != nil {
 		l.noMetaData = config.NoMetadata
 		l.flushEachLine = config.FlushEachLine
-		if config.AddTimestamp {
+		if config.AddTimestamp && l.timeFormat == "" {
 			l.logger = l.logger.With().Timestamp().Logger()
 			if len(config.TimestampFormat) > 0 {
 				zerolog.TimeFieldFormat = config.TimestampFormat
@@ -106,7 +185,10 @@ func (l *PCLog) getFileWriter(filePath string, config *types.LoggerConfig) (io.W
 		log.Err(err).Msgf("failed to create log file directory %s", dirName)
 		return nil, err
 	}
-	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND | os.O_TRUNC
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !l.isAppend {
+		flags |= os.O_TRUNC
+	}
 	f, err := os.OpenFile(filePath, flags, 0600)
 	if err != nil {
 		l.isClosed.Store(true)
@@ -127,27 +209,27 @@ func (l *PCLog) getRollingWriter(filePath string, rotation *types.LogRotationCon
 	}, nil
 }
 
-func (l *PCLog) Info(message string, process string, replica int) {
+func (l *PCLog) Info(message string, meta LogMetadata) {
 	if l.isClosed.Load() {
 		return
 	}
 	l.logEventChan <- logEvent{
 		message: message,
-		process: process,
-		replica: replica,
+		meta:    meta,
 		isErr:   false,
+		time:    time.Now(),
 	}
 }
 
-func (l *PCLog) Error(message string, process string, replica int) {
+func (l *PCLog) Error(message string, meta LogMetadata) {
 	if l.isClosed.Load() {
 		return
 	}
 	l.logEventChan <- logEvent{
 		message: message,
-		process: process,
-		replica: replica,
+		meta:    meta,
 		isErr:   true,
+		time:    time.Now(),
 	}
 }
 
@@ -171,14 +253,11 @@ func (l *PCLog) runCollector() {
 			break
 		}
 
-		level := l.logger.Info()
-		if event.isErr {
-			level = l.logger.Error()
+		if l.isAggregate && l.isJSON {
+			l.writeAggregateLine(event)
+		} else {
+			l.writeLine(event)
 		}
-		if !l.noMetaData {
-			level = level.Str("process", event.process).Int("replica", event.replica)
-		}
-		level.Msg(event.message)
 		if l.flushEachLine {
 			log.Debug().Msg("flushing")
 			l.writer.Flush()
@@ -186,3 +265,49 @@ func (l *PCLog) runCollector() {
 	}
 	l.wg.Done()
 }
+
+func (l *PCLog) writeLine(event logEvent) {
+	if l.lineTemplate != nil {
+		l.writer.WriteString(l.lineTemplate.Render(event.message, event.meta, event.isErr, event.time) + "\n")
+		return
+	}
+	level := l.logger.Info()
+	if event.isErr {
+		level = l.logger.Error()
+	}
+	if l.timeFormat != "" {
+		level = level.Interface(zerolog.TimestampFieldName, l.formatTimestamp(event.time))
+	}
+	if !l.noMetaData {
+		level = level.Str("process", event.meta.Process).Int("replica", event.meta.Replica)
+		if l.runID != "" {
+			level = level.Str("run_id", l.runID).Str("restart_id", event.meta.RestartID)
+		}
+	}
+	level.Msg(event.message)
+}
+
+func (l *PCLog) writeAggregateLine(event logEvent) {
+	line := aggregateLogLine{
+		Timestamp: l.formatTimestamp(event.time),
+		Stream:    "stdout",
+		Line:      event.message,
+	}
+	if event.isErr {
+		line.Stream = "stderr"
+	}
+	if !l.noMetaData {
+		line.Process = event.meta.Process
+		line.RunID = l.runID
+		line.RestartID = event.meta.RestartID
+		if event.meta.Replicas > 1 {
+			line.Replica = &event.meta.Replica
+		}
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		log.Err(err).Msgf("failed to marshal log line of %s", event.meta.Process)
+		return
+	}
+	l.writer.Write(append(data, '\n'))
+}
@@ -4,7 +4,15 @@ import "github.com/f1bonacc1/process-compose/src/types"
 
 type PcLogger interface {
 	Open(filePath string, rotation *types.LoggerConfig)
-	Info(message string, process string, replica int)
-	Error(message string, process string, replica int)
+	Info(message string, meta LogMetadata)
+	Error(message string, meta LogMetadata)
 	Close()
 }
+
+// LogMetadata describes the origin of a process log line
+type LogMetadata struct {
+	Process   string
+	Replica   int
+	Replicas  int
+	RestartID string
+}
@@ -16,11 +16,11 @@ func (l *PcNilLog) Open(filePath string, rotation *types.LoggerConfig) {
 func (l *PcNilLog) Sync() {
 }
 
-func (l *PcNilLog) Info(message string, process string, replica int) {
+func (l *PcNilLog) Info(message string, meta LogMetadata) {
 
 }
 
-func (l *PcNilLog) Error(message string, process string, replica int) {
+func (l *PcNilLog) Error(message string, meta LogMetadata) {
 
 }
 
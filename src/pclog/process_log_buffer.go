@@ -2,6 +2,7 @@ package pclog
 
 import (
 	"sync"
+	"time"
 )
 
 const (
@@ -10,6 +11,7 @@ const (
 
 type ProcessLogBuffer struct {
 	buffer    []string
+	times     []time.Time
 	size      int
 	observers map[string]LogObserver
 	mx        sync.Mutex
@@ -19,16 +21,26 @@ func NewLogBuffer(size int) *ProcessLogBuffer {
 	return &ProcessLogBuffer{
 		size:      size,
 		buffer:    make([]string, 0, size+slack),
+		times:     make([]time.Time, 0, size+slack),
 		observers: map[string]LogObserver{},
 	}
 }
 
+// SetSize changes the number of lines kept, the oldest lines are dropped once the buffer is full
+func (b *ProcessLogBuffer) SetSize(size int) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.size = size
+}
+
 func (b *ProcessLogBuffer) Write(message string) {
 	b.mx.Lock()
 	defer b.mx.Unlock()
 	b.buffer = append(b.buffer, message)
+	b.times = append(b.times, time.Now())
 	if len(b.buffer) > b.size+slack {
 		b.buffer = b.buffer[slack:]
+		b.times = b.times[slack:]
 	}
 	for _, observer := range b.observers {
 		_, _ = observer.WriteString(message)
@@ -62,6 +74,19 @@ func (b *ProcessLogBuffer) GetLogRange(offsetFromEnd, limit int) []string {
 	return b.buffer[len(b.buffer)-offsetFromEnd : offsetFromEnd+limit]
 }
 
+// Search returns up to limit of the most recent lines that match the query (0 for no limit)
+func (b *ProcessLogBuffer) Search(query *LogQuery) []string {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	matches := []string{}
+	for i, line := range b.buffer {
+		if query.Match(line, b.times[i]) {
+			matches = append(matches, line)
+		}
+	}
+	return query.limit(matches)
+}
+
 func (b *ProcessLogBuffer) GetLogLength() int {
 	return len(b.buffer)
 }
@@ -3,6 +3,7 @@ package pclog
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 )
 
 func GenerateUniqueID(length int) string {
@@ -13,3 +14,12 @@ func GenerateUniqueID(length int) string {
 	rand.Read(b)
 	return hex.EncodeToString(b)
 }
+
+// GenerateUUID returns a random (version 4) UUID
+func GenerateUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -7,6 +7,11 @@ import (
 	"text/template"
 )
 
+// funcs are the template functions, in addition to the text/template builtins
+var funcs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}
+
 type Templater struct {
 	vars types.Vars
 	err  error
@@ -32,7 +37,7 @@ func (t *Templater) render(str string, extra types.Vars) string {
 		return str
 	}
 
-	tpl, err := template.New("").Parse(str)
+	tpl, err := template.New("").Funcs(funcs).Parse(str)
 	if err != nil {
 		t.err = err
 		return ""
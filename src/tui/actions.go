@@ -24,6 +24,8 @@ const (
 	ActionProcessInfo      = ActionName("process_info")
 	ActionProcessStop      = ActionName("process_stop")
 	ActionProcessRestart   = ActionName("process_restart")
+	ActionProcessInput     = ActionName("process_input")
+	ActionProcessReload    = ActionName("process_reload")
 	ActionProcessScreen    = ActionName("process_screen")
 	ActionQuit             = ActionName("quit")
 	ActionLogFind          = ActionName("find")
@@ -52,6 +54,8 @@ var defaultShortcuts = map[ActionName]tcell.Key{
 	ActionProcessStart:     tcell.KeyF7,
 	ActionProcessStop:      tcell.KeyF9,
 	ActionProcessRestart:   tcell.KeyCtrlR,
+	ActionProcessInput:     tcell.KeyCtrlO,
+	ActionProcessReload:    tcell.KeyCtrlL,
 	ActionProcessScreen:    tcell.KeyF8,
 	ActionQuit:             tcell.KeyF10,
 	ActionLogFind:          tcell.KeyCtrlF,
@@ -99,6 +103,8 @@ var procActionsOrder = []ActionName{
 	ActionProcessScreen,
 	ActionProcessStop,
 	ActionProcessRestart,
+	ActionProcessReload,
+	ActionProcessInput,
 	ActionNsFilter,
 	ActionHideDisabled,
 	ActionQuit,
@@ -311,6 +317,12 @@ func newShortCuts() *ShortCuts {
 			ActionProcessRestart: {
 				Description: "Restart",
 			},
+			ActionProcessInput: {
+				Description: "Send Input",
+			},
+			ActionProcessReload: {
+				Description: "Reload",
+			},
 			ActionQuit: {
 				Description: "Quit",
 			},
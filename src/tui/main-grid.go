@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"time"
@@ -87,6 +88,8 @@ func (pv *pcView) getCommandInput() tview.Primitive {
 		return pv.getSearchInput()
 	case commandModePassword:
 		return pv.getPassInput()
+	case commandModeInput:
+		return pv.getStdinInput()
 	default:
 		return nil
 	}
@@ -134,6 +137,30 @@ func (pv *pcView) getPassInput() tview.Primitive {
 	return textInput
 }
 
+func (pv *pcView) getStdinInput() tview.Primitive {
+	name := pv.getSelectedProcName()
+	textInput := tview.NewInputField().SetLabel(fmt.Sprintf("Input to %s:", name))
+	textInput.SetFieldBackgroundColor(pv.styles.Dialog().FieldBgColor.Color())
+	textInput.SetFieldTextColor(pv.styles.Dialog().FieldFgColor.Color())
+	textInput.SetLabelColor(pv.styles.Dialog().LabelFgColor.Color())
+	textInput.SetLabelStyle(textInput.GetLabelStyle().Background(pv.styles.BgColor()))
+	textInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			if err := pv.project.WriteProcessStdin(name, textInput.GetText()); err != nil {
+				pv.attentionMessage(err.Error(), 3*time.Second)
+			}
+			// stay in input mode for the next line
+			textInput.SetText("")
+		}
+		if key == tcell.KeyEsc {
+			pv.commandModeType = commandModeOff
+			pv.appView.SetFocus(pv.procTable)
+			pv.redrawGrid()
+		}
+	})
+	return textInput
+}
+
 func (pv *pcView) handlePassEntered(textInput *tview.InputField) {
 	pass := textInput.GetText()
 	name := pv.getSelectedProcName()
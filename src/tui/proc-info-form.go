@@ -17,6 +17,8 @@ func (pv *pcView) createProcInfoForm(info *types.ProcessConfig, ports *types.Pro
 	f.SetButtonsAlign(tview.AlignCenter)
 	f.SetTitle("Process " + info.Name + " Info")
 	addStringIfNotEmpty("Description:", info.Description, f)
+	addStringIfNotEmpty("Owner:", info.Owner, f)
+	addStringIfNotEmpty("Runbook:", info.RunbookURL, f)
 	addStringIfNotEmpty("Entrypoint:", strings.Join(info.Entrypoint, " "), f)
 	addStringIfNotEmpty("Command:", info.Command, f)
 	addStringIfNotEmpty("Working Directory:", info.WorkingDir, f)
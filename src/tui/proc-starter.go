@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/f1bonacc1/process-compose/src/command"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/rs/zerolog/log"
@@ -23,6 +24,7 @@ func (pv *pcView) startProcess() {
 		pv.runForeground(info)
 		return
 	}
+	pv.project.Audit(app.AuditClientTUI, "Start", name)
 	err = pv.project.StartProcess(name)
 	if err != nil {
 		pv.showError(err.Error())
@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/f1bonacc1/process-compose/src/types"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -22,6 +23,7 @@ type tableRowValues struct {
 	status    string
 	age       string
 	mem       string
+	rate      string
 	health    string
 	restarts  string
 	exitCode  string
@@ -112,6 +114,7 @@ func setRowValues(procTable *tview.Table, row int, rowVals tableRowValues) {
 	procTable.SetCell(row, int(ProcessStateAge), tview.NewTableCell(rowVals.age).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateHealth), tview.NewTableCell(rowVals.health).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateMem), tview.NewTableCell(rowVals.mem).SetAlign(tview.AlignLeft).SetExpansion(1).SetTextColor(rowVals.fgColor))
+	procTable.SetCell(row, int(ProcessStateRate), tview.NewTableCell(rowVals.rate).SetAlign(tview.AlignRight).SetExpansion(0).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateRestarts), tview.NewTableCell(rowVals.restarts).SetAlign(tview.AlignRight).SetExpansion(0).SetTextColor(rowVals.fgColor))
 	procTable.SetCell(row, int(ProcessStateExit), tview.NewTableCell(rowVals.exitCode).SetAlign(tview.AlignRight).SetExpansion(0).SetTextColor(rowVals.fgColor))
 }
@@ -148,6 +151,25 @@ func (pv *pcView) showPassIfNeeded() {
 	}
 }
 
+// showProcessInput lets the user type input lines for the selected process if it keeps its stdin open
+func (pv *pcView) showProcessInput() {
+	name := pv.getSelectedProcName()
+	if len(name) == 0 {
+		return
+	}
+	info, err := pv.project.GetProcessInfo(name)
+	if err != nil {
+		pv.attentionMessage(err.Error(), 3*time.Second)
+		return
+	}
+	if !info.KeepStdinOpen || pv.project.IsRemote() {
+		pv.attentionMessage(fmt.Sprintf("%s doesn't accept input", name), 3*time.Second)
+		return
+	}
+	pv.commandModeType = commandModeInput
+	pv.redrawGrid()
+}
+
 func (pv *pcView) isPassModeNeeded(state *types.ProcessState) bool {
 	return state.IsRunning &&
 		state.IsElevated &&
@@ -175,6 +197,7 @@ func (pv *pcView) createProcTable() *tview.Table {
 		ProcessStateAge:       "AGE(A)",
 		ProcessStateHealth:    "HEALTH(H)",
 		ProcessStateMem:       "MEM(M)",
+		ProcessStateRate:      "RATE(L)",
 		ProcessStateRestarts:  "RESTARTS(R)",
 		ProcessStateExit:      "EXIT CODE(E)",
 	}
@@ -189,8 +212,14 @@ func (pv *pcView) createProcTable() *tview.Table {
 			pv.showPassIfNeeded()
 		case pv.shortcuts.ShortCutKeys[ActionProcessRestart].key:
 			name := pv.getSelectedProcName()
+			pv.project.Audit(app.AuditClientTUI, "Restart", name)
 			pv.project.RestartProcess(name)
 			pv.showPassIfNeeded()
+		case pv.shortcuts.ShortCutKeys[ActionProcessReload].key:
+			name := pv.getSelectedProcName()
+			go pv.handleProcessReload(name)
+		case pv.shortcuts.ShortCutKeys[ActionProcessInput].key:
+			pv.showProcessInput()
 		case tcell.KeyRune:
 			if event.Rune() == 'S' {
 				pv.setTableSorter(ProcessStateStatus)
@@ -204,6 +233,8 @@ func (pv *pcView) createProcTable() *tview.Table {
 				pv.setTableSorter(ProcessStateHealth)
 			} else if event.Rune() == 'M' {
 				pv.setTableSorter(ProcessStateMem)
+			} else if event.Rune() == 'L' {
+				pv.setTableSorter(ProcessStateRate)
 			} else if event.Rune() == 'R' {
 				pv.setTableSorter(ProcessStateRestarts)
 			} else if event.Rune() == 'E' {
@@ -226,6 +257,7 @@ func (pv *pcView) createProcTable() *tview.Table {
 			expansion = 1
 			align = tview.AlignCenter
 		case
+			ProcessStateRate,
 			ProcessStateRestarts,
 			ProcessStateExit:
 			align = tview.AlignRight
@@ -344,6 +376,8 @@ func (pv *pcView) getIconForState(state types.ProcessState) (string, tcell.Color
 			return "▲", pv.styles.ProcTable().FgWarning.Color()
 		}
 		return "●", pv.styles.ProcTable().FgColor.Color()
+	case types.ProcessStateRateLimited:
+		return "●", pv.styles.ProcTable().FgWarning.Color()
 	case types.ProcessStatePending,
 		types.ProcessStateRestarting:
 		return "●", pv.styles.ProcTable().FgPending.Color()
@@ -390,6 +424,13 @@ func getStrForMem(mem int64) string {
 	return byteCountIEC(mem)
 }
 
+func getStrForRate(rate float64) string {
+	if rate < 0.05 {
+		return types.PlaceHolderValue
+	}
+	return strconv.FormatFloat(rate, 'f', 1, 64) + "/s"
+}
+
 func getStrForRestarts(restarts int) string {
 	if restarts == 0 {
 		return types.PlaceHolderValue
@@ -424,6 +465,7 @@ func (pv *pcView) getTableRowValues(state types.ProcessState) tableRowValues {
 		age:       state.SystemTime,
 		health:    state.Health,
 		mem:       getStrForMem(state.Mem),
+		rate:      getStrForRate(state.OutputRate),
 		restarts:  getStrForRestarts(state.Restarts),
 		exitCode:  getStrForExitCode(state),
 	}
@@ -462,9 +504,19 @@ func (pv *pcView) selectFirstEnabledProcess() {
 func (pv *pcView) handleProcessStopped(name string) {
 	ctx, cancel := context.WithCancel(context.Background())
 	pv.showAutoProgress(ctx, time.Second*1)
-	err := pv.project.StopProcess(name)
+	pv.project.Audit(app.AuditClientTUI, "Stop", name)
+	err := pv.project.StopProcess(name, 0)
 	cancel()
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to stop process")
 	}
 }
+
+func (pv *pcView) handleProcessReload(name string) {
+	pv.project.Audit(app.AuditClientTUI, "Reload", name)
+	err := pv.project.ReloadProcess(name)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload process")
+		pv.attentionMessage(err.Error(), 3*time.Second)
+	}
+}
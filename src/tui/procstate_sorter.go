@@ -19,8 +19,9 @@ const (
 	ProcessStateAge       ColumnID = 5
 	ProcessStateHealth    ColumnID = 6
 	ProcessStateMem       ColumnID = 7
-	ProcessStateRestarts  ColumnID = 8
-	ProcessStateExit      ColumnID = 9
+	ProcessStateRate      ColumnID = 8
+	ProcessStateRestarts  ColumnID = 9
+	ProcessStateExit      ColumnID = 10
 )
 
 var columnNames = map[ColumnID]string{
@@ -32,6 +33,7 @@ var columnNames = map[ColumnID]string{
 	ProcessStateAge:       "AGE",
 	ProcessStateHealth:    "HEALTH",
 	ProcessStateMem:       "MEM",
+	ProcessStateRate:      "RATE",
 	ProcessStateRestarts:  "RESTARTS",
 	ProcessStateExit:      "EXIT",
 }
@@ -45,6 +47,7 @@ var columnIDs = map[string]ColumnID{
 	"AGE":       ProcessStateAge,
 	"HEALTH":    ProcessStateHealth,
 	"MEM":       ProcessStateMem,
+	"RATE":      ProcessStateRate,
 	"RESTARTS":  ProcessStateRestarts,
 	"EXIT":      ProcessStateExit,
 }
@@ -155,6 +158,10 @@ func getSorter(sortBy ColumnID, states *types.ProcessesState) sortFn {
 		return func(i, j int) bool {
 			return states.States[i].Mem < states.States[j].Mem
 		}
+	case ProcessStateRate:
+		return func(i, j int) bool {
+			return states.States[i].OutputRate < states.States[j].OutputRate
+		}
 	case ProcessStateName:
 		fallthrough
 	default:
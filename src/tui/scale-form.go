@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"github.com/f1bonacc1/process-compose/src/app"
 	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
 	"strconv"
@@ -23,6 +24,7 @@ func (pv *pcView) showScale() {
 			return
 		}
 		log.Info().Msgf("Scaling %s to %d", name, scale)
+		pv.project.Audit(app.AuditClientTUI, "Scale", name)
 		err = pv.project.ScaleProcess(name, scale)
 		if err != nil {
 			pv.showError("Invalid Scale: " + err.Error())
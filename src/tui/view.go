@@ -34,6 +34,7 @@ const (
 	commandModeDisabled
 	commandModeSearch
 	commandModePassword
+	commandModeInput
 )
 
 const (
@@ -365,6 +366,7 @@ func (pv *pcView) showInfo() {
 func (pv *pcView) handleShutDown() {
 	pv.attentionMessage("Shutting Down...", 0)
 	if !pv.project.IsRemote() {
+		pv.project.Audit(app.AuditClientTUI, "Shutdown")
 		_ = pv.project.ShutDownProject()
 	}
 	time.Sleep(time.Second)
@@ -509,7 +511,10 @@ func (pv *pcView) resume() {
 
 	go pv.updateTable(ctxTbl)
 	go pv.updateLogs(ctxLog)
-	go setSignal(ctxSig)
+	// a local project is shut down by the process-compose signal handler, with its grace period
+	if pv.project.IsRemote() {
+		go setSignal(ctxSig)
+	}
 }
 
 func (pv *pcView) changeFocus() {
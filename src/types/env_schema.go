@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckEnvSchema checks the env_schema variables are defined with the declared type, and adds the defaults of the
+// undefined ones to the process environment. The variables are looked up in the process, project and OS environments.
+// With skipUndefined, the undefined variables are left to a later check, once the missing environment is known
+func (p *ProcessConfig) CheckEnvSchema(projectEnv Environment, skipUndefined bool) error {
+	keys := make([]string, 0, len(p.EnvSchema))
+	for key := range p.EnvSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		spec := p.EnvSchema[key]
+		value, ok := LookupEnv(key, p.Environment, projectEnv)
+		if !ok && skipUndefined {
+			continue
+		}
+		if !ok && spec.Default != "" {
+			value, ok = spec.Default, true
+			p.Environment = append(slices.Clip(p.Environment), key+"="+value)
+		}
+		if !ok {
+			if spec.Required {
+				return fmt.Errorf("required environment variable '%s' of process '%s' is not defined", key, p.Name)
+			}
+			continue
+		}
+		if err := spec.checkType(value); err != nil {
+			return fmt.Errorf("environment variable '%s' of process '%s' should be of type %s, got '%s': %v",
+				key, p.Name, spec.Type, value, err)
+		}
+	}
+	return nil
+}
+
+// LookupEnv looks up the variable in the environments, in the given order, and then in the OS environment
+func LookupEnv(key string, envs ...Environment) (string, bool) {
+	for _, env := range envs {
+		for i := len(env) - 1; i >= 0; i-- {
+			if k, v, _ := strings.Cut(env[i], "="); k == key {
+				return v, true
+			}
+		}
+	}
+	return os.LookupEnv(key)
+}
+
+func (s EnvVarSpec) checkType(value string) error {
+	var err error
+	switch s.Type {
+	case "", EnvVarTypeString:
+	case EnvVarTypeInt:
+		_, err = strconv.Atoi(value)
+	case EnvVarTypeBool:
+		_, err = strconv.ParseBool(value)
+	case EnvVarTypeDuration:
+		_, err = time.ParseDuration(value)
+	case EnvVarTypeURL:
+		var u *url.URL
+		u, err = url.Parse(value)
+		if err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("missing scheme or host")
+		}
+	default:
+		err = fmt.Errorf("unknown type")
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+package types
+
+import "fmt"
+
+// Location points to a definition in a configuration file
+type Location struct {
+	File string
+	Line int
+}
+
+func (l Location) String() string {
+	if l.Line == 0 {
+		return l.File
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // LogRotationConfig is the configuration for logging
 type LogRotationConfig struct {
 	// Directory to log to when filelogging is enabled
@@ -17,6 +19,11 @@ type LogRotationConfig struct {
 	Compress bool `json:"compress" yaml:"compress"`
 }
 
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 type LoggerConfig struct {
 	// Rotation is the configuration for logging rotation
 	Rotation *LogRotationConfig `yaml:"rotation"`
@@ -35,3 +42,15 @@ type LoggerConfig struct {
 	// FlushEachLine flushes the logger on each line
 	FlushEachLine bool `yaml:"flush_each_line"`
 }
+
+// LogSearchQuery is the process log search criteria
+type LogSearchQuery struct {
+	// Pattern is a regular expression the log lines should match
+	Pattern string
+	// Since filters out lines logged before it (if not zero)
+	Since time.Time
+	// Until filters out lines logged after it (if not zero)
+	Until time.Time
+	// Limit is the max number of the most recent matching lines to return (0 for no limit)
+	Limit int
+}
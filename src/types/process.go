@@ -14,34 +14,80 @@ const PlaceHolderValue = "-"
 type Processes map[string]ProcessConfig
 type Environment []string
 type ProcessConfig struct {
-	Name              string
-	Disabled          bool                   `yaml:"disabled,omitempty"`
-	IsDaemon          bool                   `yaml:"is_daemon,omitempty"`
-	Command           string                 `yaml:"command"`
-	Entrypoint        []string               `yaml:"entrypoint"`
-	LogLocation       string                 `yaml:"log_location,omitempty"`
-	LoggerConfig      *LoggerConfig          `yaml:"log_configuration,omitempty"`
-	Environment       Environment            `yaml:"environment,omitempty"`
-	RestartPolicy     RestartPolicyConfig    `yaml:"availability,omitempty"`
-	DependsOn         DependsOnConfig        `yaml:"depends_on,omitempty"`
-	LivenessProbe     *health.Probe          `yaml:"liveness_probe,omitempty"`
-	ReadinessProbe    *health.Probe          `yaml:"readiness_probe,omitempty"`
-	ReadyLogLine      string                 `yaml:"ready_log_line,omitempty"`
-	ShutDownParams    ShutDownParams         `yaml:"shutdown,omitempty"`
-	DisableAnsiColors bool                   `yaml:"disable_ansi_colors,omitempty"`
-	WorkingDir        string                 `yaml:"working_dir"`
-	Namespace         string                 `yaml:"namespace"`
-	Replicas          int                    `yaml:"replicas"`
-	Extensions        map[string]interface{} `yaml:",inline"`
-	Description       string                 `yaml:"description,omitempty"`
-	Vars              Vars                   `yaml:"vars"`
-	IsForeground      bool                   `yaml:"is_foreground"`
-	IsTty             bool                   `yaml:"is_tty"`
-	IsElevated        bool                   `yaml:"is_elevated"`
-	ReplicaNum        int
-	ReplicaName       string
-	Executable        string
-	Args              []string
+	Name                 string
+	Disabled             bool                   `yaml:"disabled,omitempty"`
+	IsDaemon             bool                   `yaml:"is_daemon,omitempty"`
+	Command              string                 `yaml:"command"`
+	ValidateCommand      string                 `yaml:"validate_command,omitempty"`
+	Entrypoint           []string               `yaml:"entrypoint"`
+	LogLocation          string                 `yaml:"log_location,omitempty"`
+	StdoutLogLocation    string                 `yaml:"stdout_log_location,omitempty"`
+	StderrLogLocation    string                 `yaml:"stderr_log_location,omitempty"`
+	LogTimestampFormat   string                 `yaml:"log_timestamp_format,omitempty"`
+	LogLineTemplate      string                 `yaml:"log_line_template,omitempty"`
+	LoggerConfig         *LoggerConfig          `yaml:"log_configuration,omitempty"`
+	Environment          Environment            `yaml:"environment,omitempty"`
+	RestartPolicy        RestartPolicyConfig    `yaml:"availability,omitempty"`
+	DependsOn            DependsOnConfig        `yaml:"depends_on,omitempty"`
+	LivenessProbe        *health.Probe          `yaml:"liveness_probe,omitempty"`
+	ReadinessProbe       *health.Probe          `yaml:"readiness_probe,omitempty"`
+	ReadyLogLine         string                 `yaml:"ready_log_line,omitempty"`
+	ShutDownParams       ShutDownParams         `yaml:"shutdown,omitempty"`
+	DisableAnsiColors    bool                   `yaml:"disable_ansi_colors,omitempty"`
+	WorkingDir           string                 `yaml:"working_dir"`
+	StdinFile            string                 `yaml:"stdin_file,omitempty"`
+	StdinData            string                 `yaml:"stdin_data,omitempty"`
+	CreateWorkingDir     bool                   `yaml:"create_working_dir,omitempty"`
+	Namespace            string                 `yaml:"namespace"`
+	Replicas             int                    `yaml:"replicas"`
+	Extensions           map[string]interface{} `yaml:",inline"`
+	Description          string                 `yaml:"description,omitempty"`
+	Owner                string                 `yaml:"owner,omitempty"`
+	RunbookURL           string                 `yaml:"runbook_url,omitempty"`
+	Labels               map[string]string      `yaml:"labels,omitempty"`
+	MaxOutputLines       int                    `yaml:"max_output_lines,omitempty"`
+	Vars                 Vars                   `yaml:"vars"`
+	IsForeground         bool                   `yaml:"is_foreground"`
+	IsTty                bool                   `yaml:"is_tty"`
+	IsElevated           bool                   `yaml:"is_elevated"`
+	Detach               bool                   `yaml:"detach,omitempty"`
+	Shadow               bool                   `yaml:"shadow,omitempty"`
+	OutputBuffering      string                 `yaml:"output_buffering,omitempty"`
+	Ulimits              Ulimits                `yaml:"ulimits,omitempty"`
+	SuccessExitCodes     []int                  `yaml:"success_exit_codes,omitempty"`
+	RestartExitCodes     []int                  `yaml:"restart_exit_codes,omitempty"`
+	NoRestartExitCodes   []int                  `yaml:"no_restart_exit_codes,omitempty"`
+	OnStart              string                 `yaml:"on_start,omitempty"`
+	OnStop               string                 `yaml:"on_stop,omitempty"`
+	OnFailure            string                 `yaml:"on_failure,omitempty"`
+	MinUptime            time.Duration          `yaml:"min_uptime,omitempty"`
+	WatchPaths           []string               `yaml:"watch_paths,omitempty"`
+	WatchDebounce        time.Duration          `yaml:"watch_debounce,omitempty"`
+	EnvFromProcess       string                 `yaml:"env_from_process,omitempty"`
+	KeepStdinOpen        bool                   `yaml:"keep_stdin_open,omitempty"`
+	MaxOutputRate        float64                `yaml:"max_output_rate,omitempty"`
+	ReloadSignal         string                 `yaml:"reload_signal,omitempty"`
+	BaseProcess          string                 `yaml:"base_process,omitempty"`
+	Color                string                 `yaml:"color,omitempty"`
+	DisabledIf           string                 `yaml:"disabled_if,omitempty"`
+	EnvSchema            map[string]EnvVarSpec  `yaml:"env_schema,omitempty"`
+	InputFrom            string                 `yaml:"input_from,omitempty"`
+	MaxRestartsPerMinute int                    `yaml:"max_restarts_per_minute,omitempty"`
+	OutputWebhook        string                 `yaml:"output_webhook,omitempty"`
+	EnableCoreDump       bool                   `yaml:"enable_core_dump,omitempty"`
+	CoreDumpDir          string                 `yaml:"core_dump_dir,omitempty"`
+	OutputFIFO           string                 `yaml:"output_fifo,omitempty"`
+	EnvRequired          []string               `yaml:"env_required,omitempty"`
+	LogAppend            *bool                  `yaml:"log_append,omitempty"`
+	MergeStrategy        map[string]string      `yaml:"merge_strategy,omitempty"`
+	Priority             int                    `yaml:"priority,omitempty"`
+	Critical             bool                   `yaml:"critical,omitempty"`
+	Isolated             bool                   `yaml:"isolated,omitempty"`
+	Location             Location               `yaml:"-"`
+	ReplicaNum           int
+	ReplicaName          string
+	Executable           string
+	Args                 []string
 }
 
 func (p *ProcessConfig) GetDependencies() []string {
@@ -63,10 +109,41 @@ func (p *ProcessConfig) CalculateReplicaName() string {
 	return fmt.Sprintf("%s-%0*d", p.Name, myWidth, p.ReplicaNum)
 }
 
+// IsService reports whether the process is long-running: a daemon, or a process restarted when it ends
+func (p *ProcessConfig) IsService() bool {
+	return p.IsDaemon ||
+		p.RestartPolicy.Restart == RestartPolicyAlways ||
+		p.RestartPolicy.Restart == RestartPolicyOnFailure
+}
+
+// IsLogAppend reports whether the process log files are appended instead of truncated on each run
+func (p *ProcessConfig) IsLogAppend() bool {
+	return p.LogAppend != nil && *p.LogAppend
+}
+
 func (p *ProcessConfig) IsDeferred() bool {
 	return p.IsForeground || p.Disabled
 }
 
+// IsSuccessExitCode returns true if the exit code is one of the SuccessExitCodes (0 if not set)
+func (p *ProcessConfig) IsSuccessExitCode(exitCode int) bool {
+	// the exit codes that aren't restarted are successful completions
+	for _, code := range p.NoRestartExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+	if len(p.SuccessExitCodes) == 0 {
+		return exitCode == 0
+	}
+	for _, code := range p.SuccessExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+	return false
+}
+
 // Compare returns true if two process configs are equal
 func (p *ProcessConfig) Compare(another *ProcessConfig) bool {
 	if p == nil || another == nil {
@@ -78,16 +155,51 @@ func (p *ProcessConfig) Compare(another *ProcessConfig) bool {
 		p.Disabled != another.Disabled ||
 		p.IsDaemon != another.IsDaemon ||
 		p.Command != another.Command ||
+		p.ValidateCommand != another.ValidateCommand ||
 		p.LogLocation != another.LogLocation ||
+		p.StdoutLogLocation != another.StdoutLogLocation ||
+		p.StderrLogLocation != another.StderrLogLocation ||
+		p.LogTimestampFormat != another.LogTimestampFormat ||
+		p.LogLineTemplate != another.LogLineTemplate ||
 		p.ReadyLogLine != another.ReadyLogLine ||
 		p.DisableAnsiColors != another.DisableAnsiColors ||
 		p.WorkingDir != another.WorkingDir ||
+		p.StdinFile != another.StdinFile ||
+		p.StdinData != another.StdinData ||
+		p.CreateWorkingDir != another.CreateWorkingDir ||
 		p.Namespace != another.Namespace ||
 		p.Replicas != another.Replicas ||
 		p.Description != another.Description ||
+		p.Owner != another.Owner ||
+		p.RunbookURL != another.RunbookURL ||
+		p.MaxOutputLines != another.MaxOutputLines ||
 		p.IsForeground != another.IsForeground ||
 		p.IsTty != another.IsTty ||
-		p.IsElevated != another.IsElevated {
+		p.IsElevated != another.IsElevated ||
+		p.Detach != another.Detach ||
+		p.Shadow != another.Shadow ||
+		p.OutputBuffering != another.OutputBuffering ||
+		p.OnStart != another.OnStart ||
+		p.OnStop != another.OnStop ||
+		p.OnFailure != another.OnFailure ||
+		p.MinUptime != another.MinUptime ||
+		p.WatchDebounce != another.WatchDebounce ||
+		p.EnvFromProcess != another.EnvFromProcess ||
+		p.KeepStdinOpen != another.KeepStdinOpen ||
+		p.MaxOutputRate != another.MaxOutputRate ||
+		p.ReloadSignal != another.ReloadSignal ||
+		p.BaseProcess != another.BaseProcess ||
+		p.Color != another.Color ||
+		p.DisabledIf != another.DisabledIf ||
+		p.InputFrom != another.InputFrom ||
+		p.MaxRestartsPerMinute != another.MaxRestartsPerMinute ||
+		p.OutputWebhook != another.OutputWebhook ||
+		p.EnableCoreDump != another.EnableCoreDump ||
+		p.CoreDumpDir != another.CoreDumpDir ||
+		p.OutputFIFO != another.OutputFIFO ||
+		p.Priority != another.Priority ||
+		p.Critical != another.Critical ||
+		p.Isolated != another.Isolated {
 		return false
 	}
 
@@ -96,10 +208,19 @@ func (p *ProcessConfig) Compare(another *ProcessConfig) bool {
 		!reflect.DeepEqual(p.ReadinessProbe, another.ReadinessProbe) ||
 		!reflect.DeepEqual(p.ShutDownParams, another.ShutDownParams) ||
 		!reflect.DeepEqual(p.Vars, another.Vars) ||
+		!reflect.DeepEqual(p.Labels, another.Labels) ||
 		!reflect.DeepEqual(p.Extensions, another.Extensions) ||
 		!reflect.DeepEqual(p.DependsOn, another.DependsOn) ||
 		!reflect.DeepEqual(p.RestartPolicy, another.RestartPolicy) ||
+		!reflect.DeepEqual(p.LogAppend, another.LogAppend) ||
 		!reflect.DeepEqual(p.Environment, another.Environment) ||
+		!reflect.DeepEqual(p.Ulimits, another.Ulimits) ||
+		!reflect.DeepEqual(p.SuccessExitCodes, another.SuccessExitCodes) ||
+		!reflect.DeepEqual(p.RestartExitCodes, another.RestartExitCodes) ||
+		!reflect.DeepEqual(p.NoRestartExitCodes, another.NoRestartExitCodes) ||
+		!reflect.DeepEqual(p.WatchPaths, another.WatchPaths) ||
+		!reflect.DeepEqual(p.EnvSchema, another.EnvSchema) ||
+		!reflect.DeepEqual(p.EnvRequired, another.EnvRequired) ||
 		!reflect.DeepEqual(p.Args, another.Args) {
 		return false
 	}
@@ -131,17 +252,18 @@ func compareStructs(a, b interface{}) []string {
 
 func NewProcessState(proc *ProcessConfig) *ProcessState {
 	state := &ProcessState{
-		Name:       proc.ReplicaName,
-		Namespace:  proc.Namespace,
-		Status:     ProcessStatePending,
-		SystemTime: PlaceHolderValue,
-		Age:        time.Duration(0),
-		IsRunning:  false,
-		Health:     ProcessHealthUnknown,
-		Restarts:   0,
-		ExitCode:   0,
-		Mem:        0,
-		Pid:        0,
+		Name:        proc.ReplicaName,
+		Namespace:   proc.Namespace,
+		Description: proc.Description,
+		Status:      ProcessStatePending,
+		SystemTime:  PlaceHolderValue,
+		Age:         time.Duration(0),
+		IsRunning:   false,
+		Health:      ProcessHealthUnknown,
+		Restarts:    0,
+		ExitCode:    0,
+		Mem:         0,
+		Pid:         0,
 	}
 	if proc.Disabled {
 		state.Status = ProcessStateDisabled
@@ -154,6 +276,7 @@ func NewProcessState(proc *ProcessConfig) *ProcessState {
 type ProcessState struct {
 	Name             string        `json:"name"`
 	Namespace        string        `json:"namespace"`
+	Description      string        `json:"description"`
 	Status           string        `json:"status"`
 	SystemTime       string        `json:"system_time"`
 	Age              time.Duration `json:"age"`
@@ -164,6 +287,7 @@ type ProcessState struct {
 	IsElevated       bool          `json:"is_elevated"`
 	PasswordProvided bool          `json:"password_provided"`
 	Mem              int64         `json:"mem"`
+	OutputRate       float64       `json:"output_rate"`
 	IsRunning        bool
 }
 
@@ -177,6 +301,13 @@ type ProcessesState struct {
 	States []ProcessState `json:"data"`
 }
 
+const (
+	// MergeStrategyAppend merges a list or a map field with the overridden one (default)
+	MergeStrategyAppend = "append"
+	// MergeStrategyReplace replaces a list or a map field with the overriding one
+	MergeStrategyReplace = "replace"
+)
+
 const (
 	RestartPolicyAlways        = "always"
 	RestartPolicyOnFailure     = "on_failure"
@@ -184,6 +315,11 @@ const (
 	RestartPolicyNo            = "no"
 )
 
+const (
+	OutputBufferingLine  = "line"
+	OutputBufferingBlock = "block"
+)
+
 const (
 	ProcessStateDisabled    = "Disabled"
 	ProcessStateForeground  = "Foreground"
@@ -192,6 +328,7 @@ const (
 	ProcessStateLaunching   = "Launching"
 	ProcessStateLaunched    = "Launched"
 	ProcessStateRestarting  = "Restarting"
+	ProcessStateRateLimited = "RateLimited"
 	ProcessStateTerminating = "Terminating"
 	ProcessStateCompleted   = "Completed"
 	ProcessStateSkipped     = "Skipped"
@@ -216,6 +353,7 @@ type ShutDownParams struct {
 	ShutDownCommand string `yaml:"command,omitempty"`
 	ShutDownTimeout int    `yaml:"timeout_seconds,omitempty"`
 	Signal          int    `yaml:"signal,omitempty"`
+	KillSignal      string `yaml:"kill_signal,omitempty"`
 	ParentOnly      bool   `yaml:"parent_only,omitempty"`
 }
 
@@ -223,7 +361,7 @@ const (
 	// ProcessConditionCompleted is the type for waiting until a process has completed (any exit code).
 	ProcessConditionCompleted = "process_completed"
 
-	// ProcessConditionCompletedSuccessfully is the type for waiting until a process has completed successfully (exit code 0).
+	// ProcessConditionCompletedSuccessfully is the type for waiting until a process has completed successfully (exit code in success_exit_codes, 0 by default).
 	ProcessConditionCompletedSuccessfully = "process_completed_successfully"
 
 	// ProcessConditionHealthy is the type for waiting until a process is healthy.
@@ -234,13 +372,43 @@ const (
 
 	// ProcessConditionLogReady is the type for waiting until a process has printed a predefined log line
 	ProcessConditionLogReady = "process_log_ready"
+
+	// ProcessConditionStartedNonBlocking is the type for starting after the process was launched, without waiting for it.
+	ProcessConditionStartedNonBlocking = "service_started_nonblocking"
 )
 
 type DependsOnConfig map[string]ProcessDependency
 
+// EnvVarSpec declares an environment variable expected by a process
+type EnvVarSpec struct {
+	Type     string `yaml:"type,omitempty"`
+	Default  string `yaml:"default,omitempty"`
+	Required bool   `yaml:"required,omitempty"`
+}
+
+const (
+	EnvVarTypeString   = "string"
+	EnvVarTypeInt      = "int"
+	EnvVarTypeBool     = "bool"
+	EnvVarTypeURL      = "url"
+	EnvVarTypeDuration = "duration"
+)
+
 type ProcessDependency struct {
-	Condition  string                 `yaml:",omitempty"`
-	Extensions map[string]interface{} `yaml:",inline"`
+	Condition                string                 `yaml:",omitempty"`
+	ReadinessProbeInterval   time.Duration          `yaml:"readiness_probe_interval,omitempty"`
+	ReadinessProbeTimeout    time.Duration          `yaml:"readiness_probe_timeout,omitempty"`
+	ReadinessProbeMaxRetries int                    `yaml:"readiness_probe_max_retries,omitempty"`
+	WaitWarningInterval      time.Duration          `yaml:"wait_warning_interval,omitempty"`
+	ReadyReplicas            int                    `yaml:"ready_replicas,omitempty"`
+	AttemptLimit             int                    `yaml:"attempt_limit,omitempty"`
+	RetryDelay               time.Duration          `yaml:"retry_delay,omitempty"`
+	Extensions               map[string]interface{} `yaml:",inline"`
+}
+
+// HasReadinessRetries returns true if the dependency defines its own readiness probe retry parameters
+func (d *ProcessDependency) HasReadinessRetries() bool {
+	return d.ReadinessProbeInterval > 0 || d.ReadinessProbeTimeout > 0 || d.ReadinessProbeMaxRetries > 0
 }
 
 const (
@@ -1,40 +1,267 @@
 package types
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/f1bonacc1/process-compose/src/command"
+	"math"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 type Vars map[string]any
 
+// Ulimits maps a resource name (nofile, nproc, stack, core, memlock, fsize) to its soft limit
+type Ulimits map[string]int64
+
+// UlimitUnlimited is the highest soft limit, capped at the hard limit without a warning
+const UlimitUnlimited = math.MaxInt64
+
 type Project struct {
-	Version             string               `yaml:"version"`
-	LogLocation         string               `yaml:"log_location,omitempty"`
-	LogLevel            string               `yaml:"log_level,omitempty"`
-	LogLength           int                  `yaml:"log_length,omitempty"`
-	LoggerConfig        *LoggerConfig        `yaml:"log_configuration,omitempty"`
-	LogFormat           string               `yaml:"log_format,omitempty"`
-	Processes           Processes            `yaml:"processes"`
-	Environment         Environment          `yaml:"environment,omitempty"`
-	ShellConfig         *command.ShellConfig `yaml:"shell,omitempty"`
-	IsStrict            bool                 `yaml:"is_strict"`
-	Vars                Vars                 `yaml:"vars"`
-	DisableEnvExpansion bool                 `yaml:"disable_env_expansion"`
-	IsTuiDisabled       bool                 `yaml:"is_tui_disabled"`
-	FileNames           []string
+	Version              string               `yaml:"version"`
+	LogLocation          string               `yaml:"log_location,omitempty"`
+	LogLevel             string               `yaml:"log_level,omitempty"`
+	LogLength            int                  `yaml:"log_length,omitempty"`
+	LoggerConfig         *LoggerConfig        `yaml:"log_configuration,omitempty"`
+	LogFormat            string               `yaml:"log_format,omitempty"`
+	LogTimestampFormat   string               `yaml:"log_timestamp_format,omitempty"`
+	Processes            Processes            `yaml:"processes"`
+	Environment          Environment          `yaml:"environment,omitempty"`
+	ShellConfig          *command.ShellConfig `yaml:"shell,omitempty"`
+	IsStrict             bool                 `yaml:"is_strict"`
+	Vars                 Vars                 `yaml:"vars"`
+	DisableEnvExpansion  bool                 `yaml:"disable_env_expansion"`
+	IsTuiDisabled        bool                 `yaml:"is_tui_disabled"`
+	DefaultUlimits       Ulimits              `yaml:"default_ulimits,omitempty"`
+	Namespace            string               `yaml:"namespace,omitempty"`
+	Imports              []ImportConfig       `yaml:"imports,omitempty"`
+	StartupBarrier       []string             `yaml:"startup_barrier,omitempty"`
+	MaxRestartsPerMinute int                  `yaml:"max_restarts_per_minute,omitempty"`
+	BootstrapCommand     string               `yaml:"bootstrap_command,omitempty"`
+	ValidateCommand      string               `yaml:"validate_command,omitempty"`
+	ForwardSignals       []string             `yaml:"forward_signals,omitempty"`
+	CleanupPIDDir        string               `yaml:"cleanup_pid_dir,omitempty"`
+	DeadlockTimeout      time.Duration        `yaml:"deadlock_timeout,omitempty"`
+	SecretEnvVars        []string             `yaml:"secret_env_vars,omitempty"`
+	AutoMaskSecrets      *bool                `yaml:"auto_mask_secrets,omitempty"`
+	ReaperMode           bool                 `yaml:"reaper_mode,omitempty"`
+	SummaryFile          string               `yaml:"summary_file,omitempty"`
+	GracePeriodSeconds   int                  `yaml:"grace_period_seconds,omitempty"`
+	StateDir             string               `yaml:"state_dir,omitempty"`
+	AutoTag              bool                 `yaml:"auto_tag,omitempty"`
+	FileNames            []string
+	RunID                string `yaml:"-"`
+	// CliEnvVars are the --var KEY=VALUE variables, they override all the other environment sources
+	CliEnvVars []string `yaml:"-"`
+	// IsDefaultStateDir is set when the state directory is the one created by process-compose for the project
+	IsDefaultStateDir bool `yaml:"-"`
+}
+
+// secretEnvVarPatterns are the environment variable name parts that are masked by auto_mask_secrets
+var secretEnvVarPatterns = []string{"SECRET", "PASSWORD", "TOKEN", "KEY", "CREDENTIAL", "APIKEY"}
+
+// IsSecretEnvVar returns true if the environment variable value must not be shown
+func (p *Project) IsSecretEnvVar(key string) bool {
+	for _, secret := range p.SecretEnvVars {
+		if secret == key {
+			return true
+		}
+	}
+	if p.AutoMaskSecrets != nil && !*p.AutoMaskSecrets {
+		return false
+	}
+	upperKey := strings.ToUpper(key)
+	for _, pattern := range secretEnvVarPatterns {
+		if strings.Contains(upperKey, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportConfig defines a sub-project whose processes are added to the project under a namespace
+type ImportConfig struct {
+	Path      string `yaml:"path"`
+	Namespace string `yaml:"namespace,omitempty"`
 }
 
 type ProcessFunc func(process ProcessConfig) error
 
-// WithProcesses run ProcessFunc on each Process and dependencies in dependency order
-func (p *Project) WithProcesses(names []string, fn ProcessFunc) error {
-	return p.withProcesses(names, fn, map[string]bool{})
+// WithProcesses run ProcessFunc on each Process and dependencies in dependency order.
+// The traversal stops with ctx.Err() once ctx is cancelled
+func (p *Project) WithProcesses(ctx context.Context, names []string, fn ProcessFunc) error {
+	return p.withProcesses(ctx, names, fn, map[string]bool{})
+}
+
+// WithProcessesParallel runs ProcessFunc on each Process and dependencies. The processes are grouped into layers
+// by their dependency depth, the layers run in dependency order and the processes of each layer run concurrently.
+// Processes with a failed dependency are skipped and the errors of all the processes are joined.
+// The traversal stops with ctx.Err() once ctx is cancelled
+func (p *Project) WithProcessesParallel(ctx context.Context, names []string, fn ProcessFunc) error {
+	layers, dependencies, err := p.getProcessLayers(names)
+	if err != nil {
+		return err
+	}
+	failed := map[string]bool{}
+	var errs []error
+	for _, layer := range layers {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		layerErrs := make([]error, len(layer))
+		var wg sync.WaitGroup
+		for i, process := range layer {
+			if dep := firstFailed(dependencies[process.ReplicaName], failed); dep != "" {
+				layerErrs[i] = fmt.Errorf("error in process %s dependency %s", process.Name, dep)
+				continue
+			}
+			wg.Add(1)
+			go func(i int, process ProcessConfig) {
+				defer wg.Done()
+				layerErrs[i] = fn(process)
+			}(i, process)
+		}
+		wg.Wait()
+		for i, layerErr := range layerErrs {
+			if layerErr != nil {
+				failed[layer[i].ReplicaName] = true
+				errs = append(errs, layerErr)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetProcessesByDepth groups the process names by their dependency depth: processes without dependencies
+// are in depth 0, processes that depend on them are in depth 1, etc. The processes of the same depth can
+// run simultaneously. Returns nil for circular dependencies, which the loader validation rejects
+func (p *Project) GetProcessesByDepth() [][]string {
+	layers, _, err := p.getProcessLayers(nil)
+	if err != nil {
+		return nil
+	}
+	depths := make([][]string, len(layers))
+	for i, layer := range layers {
+		for _, process := range layer {
+			depths[i] = append(depths[i], process.ReplicaName)
+		}
+		sort.Strings(depths[i])
+	}
+	return depths
+}
+
+func firstFailed(names []string, failed map[string]bool) string {
+	for _, name := range names {
+		if failed[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// getProcessLayers groups the processes and their dependencies by dependency depth. It also returns
+// the dependencies replica names of each process
+func (p *Project) getProcessLayers(names []string) ([][]ProcessConfig, map[string][]string, error) {
+	depths := map[string]int{}
+	dependencies := map[string][]string{}
+	visiting := map[string]bool{}
+	var layers [][]ProcessConfig
+	var visit func(process ProcessConfig) (int, error)
+	visit = func(process ProcessConfig) (int, error) {
+		if depth, ok := depths[process.ReplicaName]; ok {
+			return depth, nil
+		}
+		if visiting[process.ReplicaName] {
+			return 0, fmt.Errorf("circular dependency in process %s", process.ReplicaName)
+		}
+		visiting[process.ReplicaName] = true
+		defer delete(visiting, process.ReplicaName)
+		var deps []ProcessConfig
+		// GetProcesses returns all the processes for no names
+		if depNames := process.GetDependencies(); len(depNames) > 0 {
+			var err error
+			if deps, err = p.GetProcesses(depNames...); err != nil {
+				return 0, err
+			}
+		}
+		depth := 0
+		for _, dep := range deps {
+			depDepth, err := visit(dep)
+			if err != nil {
+				return 0, err
+			}
+			depth = max(depth, depDepth+1)
+			dependencies[process.ReplicaName] = append(dependencies[process.ReplicaName], dep.ReplicaName)
+		}
+		depths[process.ReplicaName] = depth
+		for len(layers) <= depth {
+			layers = append(layers, nil)
+		}
+		layers[depth] = append(layers[depth], process)
+		return depth, nil
+	}
+	processes, err := p.GetProcesses(names...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, process := range processes {
+		if _, err = visit(process); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, layer := range layers {
+		sort.Slice(layer, func(i, j int) bool {
+			return layer[i].ReplicaName < layer[j].ReplicaName
+		})
+	}
+	return layers, dependencies, nil
+}
+
+// sortByPriority orders the processes from the highest priority to the lowest, keeping the order of equal priorities
+func sortByPriority(processes []ProcessConfig) {
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].Priority > processes[j].Priority
+	})
+}
+
+// GetDependents returns the processes that depend on the process, directly or transitively, ordered by
+// their distance from it
+func (p *Project) GetDependents(name string) []string {
+	var dependents []string
+	visited := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		baseName := current
+		if proc, ok := p.Processes[current]; ok {
+			baseName = proc.Name
+		}
+		var layer []string
+		for replicaName, proc := range p.Processes {
+			if visited[replicaName] {
+				continue
+			}
+			_, onReplica := proc.DependsOn[current]
+			_, onBase := proc.DependsOn[baseName]
+			if onReplica || onBase {
+				visited[replicaName] = true
+				layer = append(layer, replicaName)
+			}
+		}
+		sort.Strings(layer)
+		dependents = append(dependents, layer...)
+		queue = append(queue, layer...)
+	}
+	return dependents
 }
 
 func (p *Project) GetDependenciesOrderNames() ([]string, error) {
 	order := []string{}
-	err := p.WithProcesses([]string{}, func(process ProcessConfig) error {
+	err := p.WithProcesses(context.Background(), []string{}, func(process ProcessConfig) error {
 		order = append(order, process.ReplicaName)
 		return nil
 	})
@@ -51,6 +278,27 @@ func (p *Project) GetLexicographicProcessNames() ([]string, error) {
 	return names, nil
 }
 
+// ResolveProcessName returns the full name of a process, so processes of a namespaced project
+// can also be referred to by their bare names
+func (p *Project) ResolveProcessName(name string) string {
+	if p.Namespace == "" || strings.HasPrefix(name, p.Namespace+"/") {
+		return name
+	}
+	if _, ok := p.Processes[name]; ok {
+		return name
+	}
+	qualified := p.Namespace + "/" + name
+	if _, ok := p.Processes[qualified]; ok {
+		return qualified
+	}
+	for _, proc := range p.Processes {
+		if proc.Name == qualified {
+			return qualified
+		}
+	}
+	return name
+}
+
 func (p *Project) GetProcesses(names ...string) ([]ProcessConfig, error) {
 	processes := []ProcessConfig{}
 	if len(names) == 0 {
@@ -63,6 +311,7 @@ func (p *Project) GetProcesses(names ...string) ([]ProcessConfig, error) {
 		return processes, nil
 	}
 	for _, name := range names {
+		name = p.ResolveProcessName(name)
 		if proc, ok := p.Processes[name]; ok {
 			if proc.IsDeferred() {
 				continue
@@ -88,13 +337,17 @@ func (p *Project) GetProcesses(names ...string) ([]ProcessConfig, error) {
 	return processes, nil
 }
 
-func (p *Project) withProcesses(names []string, fn ProcessFunc, done map[string]bool) error {
+func (p *Project) withProcesses(ctx context.Context, names []string, fn ProcessFunc, done map[string]bool) error {
 	processes, err := p.GetProcesses(names...)
 	if err != nil {
 		return err
 	}
+	sortByPriority(processes)
 	var finalErr error
 	for _, process := range processes {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		if done[process.ReplicaName] {
 			continue
 		}
@@ -102,7 +355,10 @@ func (p *Project) withProcesses(names []string, fn ProcessFunc, done map[string]
 
 		dependencies := process.GetDependencies()
 		if len(dependencies) > 0 {
-			err = p.withProcesses(dependencies, fn, done)
+			err = p.withProcesses(ctx, dependencies, fn, done)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if err != nil {
 				finalErr = fmt.Errorf("error in process %s dependency: %w", process.Name, err)
 				continue
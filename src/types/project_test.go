@@ -0,0 +1,184 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestProject_WithProcessesCancel(t *testing.T) {
+	p := &Project{
+		Processes: Processes{
+			"a": {Name: "a", ReplicaName: "a"},
+			"b": {Name: "b", ReplicaName: "b"},
+			"c": {Name: "c", ReplicaName: "c"},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := 0
+	err := p.WithProcesses(ctx, []string{}, func(process ProcessConfig) error {
+		visited++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WithProcesses() error = %v, want %v", err, context.Canceled)
+	}
+	if visited != 1 {
+		t.Errorf("WithProcesses() visited %d processes, want 1", visited)
+	}
+
+	visited = 0
+	err = p.WithProcesses(context.Background(), []string{}, func(process ProcessConfig) error {
+		visited++
+		return nil
+	})
+	if err != nil {
+		t.Errorf("WithProcesses() error = %v", err)
+	}
+	if visited != 3 {
+		t.Errorf("WithProcesses() visited %d processes, want 3", visited)
+	}
+}
+
+func TestProject_WithProcessesParallel(t *testing.T) {
+	p := &Project{
+		Processes: Processes{
+			"db":     {Name: "db", ReplicaName: "db"},
+			"cache":  {Name: "cache", ReplicaName: "cache"},
+			"broken": {Name: "broken", ReplicaName: "broken"},
+			"api": {Name: "api", ReplicaName: "api", DependsOn: DependsOnConfig{
+				"db":    {Condition: ProcessConditionStarted},
+				"cache": {Condition: ProcessConditionStarted},
+			}},
+			"web": {Name: "web", ReplicaName: "web", DependsOn: DependsOnConfig{
+				"api": {Condition: ProcessConditionStarted},
+			}},
+			"worker": {Name: "worker", ReplicaName: "worker", DependsOn: DependsOnConfig{
+				"broken": {Condition: ProcessConditionStarted},
+			}},
+		},
+	}
+	var mtx sync.Mutex
+	visited := map[string]bool{}
+	errBroken := errors.New("broken")
+	err := p.WithProcessesParallel(context.Background(), []string{}, func(process ProcessConfig) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+		for _, dep := range process.GetDependencies() {
+			if !visited[dep] {
+				t.Errorf("%s ran before its dependency %s", process.Name, dep)
+			}
+		}
+		visited[process.Name] = true
+		if process.Name == "broken" {
+			return errBroken
+		}
+		return nil
+	})
+	if !errors.Is(err, errBroken) {
+		t.Errorf("WithProcessesParallel() error = %v, want %v", err, errBroken)
+	}
+	if visited["worker"] {
+		t.Errorf("WithProcessesParallel() ran worker despite its failed dependency")
+	}
+	if len(visited) != 5 {
+		t.Errorf("WithProcessesParallel() visited %d processes, want 5", len(visited))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = p.WithProcessesParallel(ctx, []string{"web"}, func(process ProcessConfig) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WithProcessesParallel() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestProject_GetProcessesByDepth(t *testing.T) {
+	p := &Project{
+		Processes: Processes{
+			"db":    {Name: "db", ReplicaName: "db"},
+			"cache": {Name: "cache", ReplicaName: "cache"},
+			"api": {Name: "api", ReplicaName: "api", DependsOn: DependsOnConfig{
+				"db":    {Condition: ProcessConditionStarted},
+				"cache": {Condition: ProcessConditionStarted},
+			}},
+			"migrate": {Name: "migrate", ReplicaName: "migrate", DependsOn: DependsOnConfig{
+				"db": {Condition: ProcessConditionStarted},
+			}},
+			"web": {Name: "web", ReplicaName: "web", DependsOn: DependsOnConfig{
+				"api":   {Condition: ProcessConditionStarted},
+				"cache": {Condition: ProcessConditionStarted},
+			}},
+		},
+	}
+	want := [][]string{{"cache", "db"}, {"api", "migrate"}, {"web"}}
+	if got := p.GetProcessesByDepth(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetProcessesByDepth() = %v, want %v", got, want)
+	}
+
+	p.Processes["db"] = ProcessConfig{Name: "db", ReplicaName: "db", DependsOn: DependsOnConfig{
+		"web": {Condition: ProcessConditionStarted},
+	}}
+	if got := p.GetProcessesByDepth(); got != nil {
+		t.Errorf("GetProcessesByDepth() = %v, want nil for a circular dependency", got)
+	}
+}
+
+func TestProject_GetDependents(t *testing.T) {
+	p := &Project{
+		Processes: Processes{
+			"db":    {Name: "db", ReplicaName: "db"},
+			"cache": {Name: "cache", ReplicaName: "cache"},
+			"api-0": {Name: "api", ReplicaName: "api-0", DependsOn: DependsOnConfig{
+				"db": {Condition: ProcessConditionStarted},
+			}},
+			"api-1": {Name: "api", ReplicaName: "api-1", DependsOn: DependsOnConfig{
+				"db": {Condition: ProcessConditionStarted},
+			}},
+			"web": {Name: "web", ReplicaName: "web", DependsOn: DependsOnConfig{
+				"api":   {Condition: ProcessConditionStarted},
+				"cache": {Condition: ProcessConditionStarted},
+			}},
+		},
+	}
+	want := []string{"api-0", "api-1", "web"}
+	if got := p.GetDependents("db"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDependents(db) = %v, want %v", got, want)
+	}
+	if got := p.GetDependents("web"); len(got) != 0 {
+		t.Errorf("GetDependents(web) = %v, want none", got)
+	}
+}
+
+func TestProject_IsSecretEnvVar(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name    string
+		project Project
+		key     string
+		want    bool
+	}{
+		{name: "Listed", project: Project{SecretEnvVars: []string{"DB_URL"}}, key: "DB_URL", want: true},
+		{name: "Auto Detected", project: Project{}, key: "github_token", want: true},
+		{name: "Api Key", project: Project{}, key: "STRIPE_APIKEY", want: true},
+		{name: "Not A Secret", project: Project{}, key: "PORT", want: false},
+		{name: "Segment", project: Project{}, key: "AWS_SECRET_ACCESS_KEY", want: true},
+		{name: "Part Of A Word", project: Project{}, key: "DBPASSWORD", want: true},
+		{name: "Without Separator", project: Project{}, key: "APITOKEN", want: true},
+		{name: "Auto Mask Disabled", project: Project{AutoMaskSecrets: &disabled}, key: "DB_PASSWORD", want: false},
+		{name: "Listed With Auto Mask Disabled", project: Project{AutoMaskSecrets: &disabled, SecretEnvVars: []string{"DB_PASSWORD"}},
+			key: "DB_PASSWORD", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.project.IsSecretEnvVar(tt.key); got != tt.want {
+				t.Errorf("IsSecretEnvVar(%s) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}